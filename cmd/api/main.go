@@ -1,21 +1,24 @@
 package main
 
 import (
+	"ChallengeUALA/internal/application/ports"
 	"ChallengeUALA/internal/application/services"
-	"ChallengeUALA/internal/domain"
 	"ChallengeUALA/internal/infrastructure/dlq"
 	"ChallengeUALA/internal/infrastructure/messaging/consumer"
 	"ChallengeUALA/internal/infrastructure/messaging/producer"
 	"ChallengeUALA/internal/infrastructure/repositories"
+	"ChallengeUALA/internal/infrastructure/search"
 	"ChallengeUALA/internal/interfaces/http"
+	"ChallengeUALA/internal/platform/auth"
 	"ChallengeUALA/internal/platform/config"
+	"ChallengeUALA/internal/platform/storage"
 	"ChallengeUALA/internal/worker"
 	"context"
-	"encoding/json"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/go-redis/redis/v8"
 	"github.com/gofiber/fiber/v2"
@@ -28,77 +31,113 @@ func main() {
 		log.Fatalf("Error loading app config: %v", err)
 	}
 
+	// Sin secreto cualquiera podría firmar tokens válidos
+	if cfg.Auth.Secret == "" {
+		log.Fatalf("AUTH_SECRET is required")
+	}
+
 	// Crear logger
 	logger := log.New(os.Stdout, "App: ", log.LstdFlags|log.Lshortfile)
 
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
 	// Configuración de Redis
 	redisClient := redis.NewClient(cfg.Redis)
 	redisRepo := repositories.NewRedisRepository(redisClient)
 
 	// Inicializar los repositorios
-	userRepository := repositories.NewUserRepository()
-	tweetRepository := repositories.NewTweetRepository()
-	followRepository := repositories.NewFollowRepository()
+	store, err := storage.Open(ctx, cfg.Database)
+	if err != nil {
+		log.Fatalf("Error opening storage: %v", err)
+	}
+	defer store.Close()
 
-	// KafkaProducer
-	kafkaProducer := producer.NewKafkaProducer(cfg.Kafka)
+	// Los likes se guardan en Redis para compartirlos entre instancias de la API,
+	// salvo con almacenamiento en memoria donde todo vive en este proceso
+	var likeRepo ports.LikeRepository = repositories.NewLikeRepository()
+	if store.Shared() {
+		likeRepo = repositories.NewRedisLikeRepository(redisClient)
+	}
 
-	// DLQ
-	deadLetterQueue := dlq.NewDLQ()
+	// Los servicios publican las notificaciones en su propio tópico
+	notificationProducer := producer.NewKafkaNotificationProducer(cfg.Kafka)
+	// Se cierra después de apagar la API para no perder las notificaciones que quedaron en el batch
+	defer notificationProducer.Close()
 
 	// Servicios
-	tweetService := services.NewTweetService(tweetRepository, kafkaProducer, deadLetterQueue, logger)
-	followService := services.NewFollowService(followRepository, userRepository)
-	timelineService := services.NewTimelineService(tweetRepository, followRepository, redisRepo, deadLetterQueue, logger)
+	tweetService := services.NewTweetService(store.Tweets, likeRepo, store.Users, notificationProducer, repositories.NewRedisIdempotencyRepository(redisClient), logger)
+	followService := services.NewFollowService(store.Follows, store.Users, redisRepo, notificationProducer)
+	likeService := services.NewLikeService(likeRepo, store.Tweets, notificationProducer)
+	passwords := auth.NewPasswordHasher()
+	userService := services.NewUserService(store.Users, passwords)
+
+	timelineService := services.NewTimelineService(store.Tweets, store.Follows, redisRepo, likeRepo, cfg.Timeline.CelebrityThreshold, logger)
+	trendingService := services.NewTrendingService(repositories.NewRedisTrendRepository(redisClient), likeRepo, logger)
+	searchService := services.NewSearchService(search.NewInvertedIndex(), store.Users, likeRepo)
+	notificationService := services.NewNotificationService(repositories.NewRedisNotificationRepository(redisClient))
+
+	// Con almacenamiento en memoria los datos solo existen en este proceso, así que los
+	// workers que normalmente corren en los binarios consumer y worker corren acá.
+	if !store.Shared() {
+		logger.Println("STORAGE is memory, running consumer and worker in-process")
+		kafkaProducer := producer.NewKafkaProducer(cfg.Kafka)
+		deadLetterQueue := dlq.NewDLQ()
+
+		outboxRelay := worker.NewOutboxRelay(store.Tweets, kafkaProducer, time.Second, logger)
+		go outboxRelay.Start(ctx)
+
+		// El mismo consumer que arma los timelines alimenta el índice de búsqueda
+		updater := worker.MultiUpdater(timelineService, worker.IndexerUpdater(searchService))
+		fanoutConsumer := worker.NewFanoutConsumer(consumer.NewKafkaConsumer(cfg.Kafka).Reader, updater, deadLetterQueue, logger)
+		defer fanoutConsumer.Close()
+		go func() {
+			_ = fanoutConsumer.Run(ctx)
+		}()
+
+		trendingConsumer := worker.NewTrendingConsumer(consumer.NewKafkaTrendingConsumer(cfg.Kafka).Reader, trendingService, logger)
+		defer trendingConsumer.Close()
+		go func() {
+			_ = trendingConsumer.Run(ctx)
+		}()
+
+		notificationConsumer := worker.NewNotificationConsumer(consumer.NewKafkaNotificationConsumer(cfg.Kafka).Reader, notificationService, logger)
+		defer notificationConsumer.Close()
+		go func() {
+			_ = notificationConsumer.Run(ctx)
+		}()
+
+		dlqWorker := worker.NewDLQWorker(deadLetterQueue, kafkaProducer, logger)
+		go dlqWorker.Start(ctx)
+	} else {
+		// El fan-out corre en cmd/consumer, que no alimenta el índice de búsqueda: cada instancia
+		// de la API lee el tópico completo, sin consumer group, para armar el suyo
+		searchConsumer := worker.NewFanoutConsumer(consumer.NewKafkaSearchConsumer(cfg.Kafka).Reader, worker.IndexerUpdater(searchService), nil, logger)
+		defer searchConsumer.Close()
+		go func() {
+			_ = searchConsumer.Run(ctx)
+		}()
+	}
 
 	// Configuración de Fiber para la API
 	app := fiber.New()
+	tokens := auth.NewTokenSigner(cfg.Auth.Secret, cfg.Auth.TokenTTL)
+	// Las sesiones viven en Redis para que cerrar una invalide sus tokens en todas las instancias
+	authService := services.NewAuthService(store.Users, passwords, repositories.NewRedisSessionRepository(redisClient), tokens)
+	// El rate limit vive en Redis para que el límite sea el mismo en todas las instancias
+	rateLimiter := repositories.NewRedisRateLimiter(redisClient)
 
 	// Setup de las rutas de la API
-	http.SetupRoutes(app, tweetService, followService, timelineService)
+	http.SetupRoutes(app, tweetService, followService, timelineService, likeService, trendingService, searchService, notificationService, userService, authService, rateLimiter, cfg.RateLimit)
 
 	// Iniciar la API en una goroutine
 	go func() {
 		log.Fatal(app.Listen(":8080"))
 	}()
 
-	// Kafka Consumer
-	kafkaConsumer := consumer.NewKafkaConsumer(cfg.Kafka)
-
-	// Canal para manejar señales de terminación
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
-
-	// Goroutine para manejar mensajes de Kafka
-	go func() {
-		log.Println("Kafka consumer started")
-		ctx := context.Background()
-		for {
-			msg, err := kafkaConsumer.Reader.ReadMessage(ctx)
-			if err != nil {
-				log.Printf("Error reading message: %v", err)
-				continue
-			}
-			log.Println("Message received: ", string(msg.Value))
-			var tweet domain.Tweet
-			if err := json.Unmarshal(msg.Value, &tweet); err != nil {
-				log.Printf("Error unmarshalling tweet: %v", err)
-				continue
-			}
-
-			// Actualizar el timeline para los seguidores del usuario que publicó el tweet
-			if err := timelineService.UpdateTimeline(ctx, &tweet); err != nil {
-				log.Printf("Error updating timeline: %v", err)
-			} else {
-				log.Printf("Timeline updated for followers of user %s", tweet.UserID)
-			}
-		}
-	}()
-
-	// Dead Letter Queue Worker
-	dlqWorker := worker.NewDLQWorker(deadLetterQueue, producer.NewKafkaProducer(cfg.Kafka), logger)
-	go dlqWorker.Start(context.Background())
-
-	<-sigChan
+	<-ctx.Done()
 	log.Println("Shutting down...")
+	if err := app.Shutdown(); err != nil {
+		log.Printf("Error shutting down API: %v", err)
+	}
 }
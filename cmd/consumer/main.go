@@ -0,0 +1,84 @@
+package main
+
+import (
+	"ChallengeUALA/internal/application/services"
+	"ChallengeUALA/internal/infrastructure/dlq"
+	"ChallengeUALA/internal/infrastructure/messaging/consumer"
+	"ChallengeUALA/internal/infrastructure/messaging/producer"
+	"ChallengeUALA/internal/infrastructure/repositories"
+	"ChallengeUALA/internal/platform/config"
+	"ChallengeUALA/internal/platform/storage"
+	"ChallengeUALA/internal/worker"
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// El consumer lee los tweets de Kafka y hace el fan-out a los timelines de Redis. En otro consumer
+// group cuenta los hashtags para las tendencias, y del tópico de notificaciones llena los inbox.
+func main() {
+	// Cargar la configuración de la aplicación
+	cfg, err := config.LoadAppConfig()
+	if err != nil {
+		log.Fatalf("Error loading app config: %v", err)
+	}
+
+	// Crear logger
+	logger := log.New(os.Stdout, "Consumer: ", log.LstdFlags|log.Lshortfile)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// El grafo de follows tiene que ser el mismo que ve la API
+	store, err := storage.Open(ctx, cfg.Database)
+	if err != nil {
+		log.Fatalf("Error opening storage: %v", err)
+	}
+	defer store.Close()
+
+	if !store.Shared() {
+		log.Fatalf("The consumer needs STORAGE=%s, with in-memory storage the api runs it in-process", config.StoragePostgres)
+	}
+
+	// Configuración de Redis
+	redisClient := redis.NewClient(cfg.Redis)
+	redisRepo := repositories.NewRedisRepository(redisClient)
+
+	// DLQ: los fan-out que agotan los reintentos se vuelven a publicar desde este mismo proceso
+	deadLetterQueue := dlq.NewDLQ()
+	dlqWorker := worker.NewDLQWorker(deadLetterQueue, producer.NewKafkaProducer(cfg.Kafka), logger)
+	go dlqWorker.Start(ctx)
+
+	likeRepo := repositories.NewRedisLikeRepository(redisClient)
+	timelineService := services.NewTimelineService(store.Tweets, store.Follows, redisRepo, likeRepo, cfg.Timeline.CelebrityThreshold, logger)
+
+	fanoutConsumer := worker.NewFanoutConsumer(consumer.NewKafkaConsumer(cfg.Kafka).Reader, timelineService, deadLetterQueue, logger)
+	defer fanoutConsumer.Close()
+
+	trendingService := services.NewTrendingService(repositories.NewRedisTrendRepository(redisClient), likeRepo, logger)
+	trendingConsumer := worker.NewTrendingConsumer(consumer.NewKafkaTrendingConsumer(cfg.Kafka).Reader, trendingService, logger)
+	defer trendingConsumer.Close()
+	go func() {
+		if err := trendingConsumer.Run(ctx); err != nil {
+			log.Printf("Trending consumer stopped with error: %v", err)
+		}
+	}()
+
+	notificationService := services.NewNotificationService(repositories.NewRedisNotificationRepository(redisClient))
+	notificationConsumer := worker.NewNotificationConsumer(consumer.NewKafkaNotificationConsumer(cfg.Kafka).Reader, notificationService, logger)
+	defer notificationConsumer.Close()
+	go func() {
+		if err := notificationConsumer.Run(ctx); err != nil {
+			log.Printf("Notification consumer stopped with error: %v", err)
+		}
+	}()
+
+	if err := fanoutConsumer.Run(ctx); err != nil {
+		log.Printf("Consumer stopped with error: %v", err)
+	}
+	log.Println("Shutting down...")
+}
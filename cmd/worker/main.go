@@ -0,0 +1,45 @@
+package main
+
+import (
+	"ChallengeUALA/internal/infrastructure/messaging/producer"
+	"ChallengeUALA/internal/platform/config"
+	"ChallengeUALA/internal/platform/storage"
+	"ChallengeUALA/internal/worker"
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// El worker publica en Kafka los eventos pendientes del outbox.
+func main() {
+	// Cargar la configuración de la aplicación
+	cfg, err := config.LoadAppConfig()
+	if err != nil {
+		log.Fatalf("Error loading app config: %v", err)
+	}
+
+	// Crear logger
+	logger := log.New(os.Stdout, "Worker: ", log.LstdFlags|log.Lshortfile)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	store, err := storage.Open(ctx, cfg.Database)
+	if err != nil {
+		log.Fatalf("Error opening storage: %v", err)
+	}
+	defer store.Close()
+
+	if !store.Shared() {
+		log.Fatalf("The worker needs STORAGE=%s, with in-memory storage the api runs it in-process", config.StoragePostgres)
+	}
+
+	// Outbox Relay: publica en Kafka los eventos guardados junto con los tweets
+	outboxRelay := worker.NewOutboxRelay(store.Tweets, producer.NewKafkaProducer(cfg.Kafka), time.Second, logger)
+	outboxRelay.Start(ctx)
+
+	log.Println("Shutting down...")
+}
@@ -0,0 +1,21 @@
+package ports
+
+import (
+	"time"
+)
+
+// TokenSigner emite y verifica los tokens con los que se autentican los usuarios (puerto de salida)
+type TokenSigner interface {
+	// Sign emite un token de la sesión sessionID de userID
+	Sign(userID, sessionID string) (string, error)
+	// Verify devuelve el usuario y la sesión del token, o un error si no es válido o venció
+	Verify(token string) (string, string, error)
+	// TTL es cuánto dura un token desde que se emite
+	TTL() time.Duration
+}
+
+// PasswordHasher guarda las contraseñas de forma que no se puedan recuperar (puerto de salida)
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Matches(hash, password string) bool
+}
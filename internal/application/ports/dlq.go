@@ -8,5 +8,6 @@ import (
 // DeadLetterQueue define el contrato para guardar eventos fallidos (puerto de salida)
 type DeadLetterQueue interface {
 	StoreEvent(ctx context.Context, eventType string, payload []byte) error
+	// RetrieveEvents saca los eventos guardados de la cola y los devuelve
 	RetrieveEvents(ctx context.Context) ([]dlq.Message, error)
 }
@@ -8,3 +8,15 @@ import (
 type EventProducer interface {
 	PublishEvent(ctx context.Context, key string, value []byte) error
 }
+
+// Event es un evento a publicar junto con otros en una misma escritura
+type Event struct {
+	Key   string
+	Value []byte
+}
+
+// BatchEventProducer publica varios eventos con una sola escritura, para no pagar la latencia del
+// broker una vez por evento
+type BatchEventProducer interface {
+	PublishEvents(ctx context.Context, events ...Event) error
+}
@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"ChallengeUALA/internal/domain"
+	"context"
+	"time"
+)
+
+// RateLimiter cuenta los pedidos de cada key en una ventana deslizante (puerto de salida)
+type RateLimiter interface {
+	// Allow consume un pedido de key si en la última window hubo menos de limit. Los pedidos
+	// rechazados no cuentan.
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (domain.RateLimitDecision, error)
+}
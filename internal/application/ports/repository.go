@@ -3,24 +3,140 @@ package ports
 import (
 	"ChallengeUALA/internal/domain"
 	"context"
+	"time"
 )
 
 // TweetRepository define el contrato para almacenar y recuperar tweets (puerto de salida)
 type TweetRepository interface {
 	Save(ctx context.Context, tweet *domain.Tweet) error
+	// SaveWithEvent guarda el tweet y el evento en el outbox de forma atómica
+	SaveWithEvent(ctx context.Context, tweet *domain.Tweet, event *domain.OutboxEvent) error
+	// GetByID, UpdateWithEvent, Delete y DeleteWithEvent devuelven domain.ErrTweetNotFound si el tweet no existe
+	GetByID(ctx context.Context, id string) (*domain.Tweet, error)
+	// ListByAuthor devuelve una página de los tweets de un autor en el orden y con el cursor de los timelines
+	ListByAuthor(ctx context.Context, authorID string, cursor string, limit int) (*domain.TimelinePage, error)
+	// ListByConversation devuelve todos los tweets de una conversación, sin orden
+	ListByConversation(ctx context.Context, conversationID string) ([]*domain.Tweet, error)
+	// ListRetweets devuelve los retweets de un tweet, sin las citas y sin orden
+	ListRetweets(ctx context.Context, tweetID string) ([]*domain.Tweet, error)
+	// UpdateWithEvent guarda los cambios de un tweet existente y el evento en el outbox de forma
+	// atómica, e incrementa tweet.Version. Si el tweet guardado ya no tiene la versión que se leyó
+	// devuelve domain.ErrTweetConflict sin guardar nada.
+	UpdateWithEvent(ctx context.Context, tweet *domain.Tweet, event *domain.OutboxEvent) error
+	Delete(ctx context.Context, id string) error
+	// DeleteWithEvent borra el tweet y guarda el evento en el outbox de forma atómica
+	DeleteWithEvent(ctx context.Context, id string, event *domain.OutboxEvent) error
+}
+
+// OutboxRepository define el contrato para leer los eventos pendientes del outbox (puerto de salida)
+type OutboxRepository interface {
+	FetchPending(ctx context.Context, limit int) ([]*domain.OutboxEvent, error)
+	MarkPublished(ctx context.Context, eventID string) error
 }
 
 type FollowRepository interface {
 	Follow(ctx context.Context, followerID string, followedID string) error
+	Unfollow(ctx context.Context, followerID string, followedID string) error
 	IsFollowing(ctx context.Context, followerID string, followedID string) (bool, error)
 	GetFollowers(ctx context.Context, userID string) ([]string, error)
+	GetFollowing(ctx context.Context, userID string) ([]string, error)
+	CountFollowers(ctx context.Context, userID string) (int, error)
+	CountFollowing(ctx context.Context, userID string) (int, error)
+	// ListFollowers y ListFollowing devuelven hasta limit IDs ordenados, a partir del primero mayor que afterID
+	ListFollowers(ctx context.Context, userID string, afterID string, limit int) ([]string, error)
+	ListFollowing(ctx context.Context, userID string, afterID string, limit int) ([]string, error)
+}
+
+// LikeRepository define el contrato para guardar los likes de los tweets (puerto de salida)
+type LikeRepository interface {
+	// Like y Unlike devuelven false si el like ya existía o no existía, respectivamente
+	Like(ctx context.Context, tweetID string, userID string) (bool, error)
+	Unlike(ctx context.Context, tweetID string, userID string) (bool, error)
+	// CountLikes devuelve la cantidad de likes de cada tweet; los tweets sin likes no aparecen
+	CountLikes(ctx context.Context, tweetIDs []string) (map[string]int, error)
+	// ListLikers devuelve hasta limit IDs ordenados, a partir del primero mayor que afterID
+	ListLikers(ctx context.Context, tweetID string, afterID string, limit int) ([]string, error)
 }
 
+// UserRepository define el contrato para guardar los usuarios registrados (puerto de salida)
 type UserRepository interface {
+	// Create guarda un usuario nuevo, o devuelve domain.ErrHandleTaken si otro usuario ya tiene el handle
+	Create(ctx context.Context, user *domain.User) error
+	// GetByID y GetByHandle devuelven domain.ErrUserNotFound si el usuario no existe.
+	// GetByHandle no distingue mayúsculas y acepta el handle con o sin '@'.
 	GetByID(ctx context.Context, id string) (*domain.User, error)
+	GetByHandle(ctx context.Context, handle string) (*domain.User, error)
 }
 
 type RedisRepository interface {
 	AddToTimeline(ctx context.Context, userID string, tweet *domain.Tweet) error
-	GetTimeline(ctx context.Context, userID string) ([]*domain.Tweet, error)
+	AddToTimelines(ctx context.Context, userIDs []string, tweet *domain.Tweet) error
+	// AddRetweetToTimelines agrega un retweet a los timelines que todavía no muestran el tweet original
+	AddRetweetToTimelines(ctx context.Context, userIDs []string, retweet *domain.Tweet) error
+	GetTimeline(ctx context.Context, userID string, cursor string, limit int) (*domain.TimelinePage, error)
+	RemoveAuthorFromTimeline(ctx context.Context, userID string, authorID string) error
+	// RemoveFromTimelines quita un tweet de los timelines de varios usuarios
+	RemoveFromTimelines(ctx context.Context, userIDs []string, tweetID string) error
+	// ReplaceInTimelines reemplaza la versión guardada de un tweet en los timelines que lo tienen
+	ReplaceInTimelines(ctx context.Context, userIDs []string, tweet *domain.Tweet) error
+	// Tweets de autores con demasiados seguidores, que se leen al pedir el timeline en vez de hacer fan-out
+	AddToCelebrityTweets(ctx context.Context, tweet *domain.Tweet) error
+	// GetCelebrities devuelve cuáles de userIDs tienen tweets recientes de celebridad
+	GetCelebrities(ctx context.Context, userIDs []string) ([]string, error)
+	GetCelebrityTweets(ctx context.Context, authorID string, cursor string, limit int) (*domain.TimelinePage, error)
+	RemoveFromCelebrityTweets(ctx context.Context, authorID string, tweetID string) error
+	ReplaceInCelebrityTweets(ctx context.Context, tweet *domain.Tweet) error
+}
+
+// TrendRepository guarda cuántas veces se usa cada hashtag, agrupado en buckets de tiempo, y los
+// tweets recientes de cada hashtag
+type TrendRepository interface {
+	// IncrementHashtags suma un uso de tweetID a cada tag en el bucket que empieza en bucket. Los
+	// tags que el tweet ya sumó en ese bucket no se vuelven a contar, así que se puede reintentar.
+	// El bucket se descarta pasado ttl.
+	IncrementHashtags(ctx context.Context, tweetID string, tags []string, bucket time.Time, ttl time.Duration) error
+	// TopHashtags suma los buckets pedidos y devuelve los limit hashtags más usados
+	TopHashtags(ctx context.Context, buckets []time.Time, limit int) ([]domain.Trend, error)
+	AddToHashtags(ctx context.Context, tags []string, tweet *domain.Tweet) error
+	GetHashtagTweets(ctx context.Context, tag string, cursor string, limit int) (*domain.TimelinePage, error)
+	RemoveFromHashtags(ctx context.Context, tags []string, tweetID string) error
+	ReplaceInHashtags(ctx context.Context, tags []string, tweet *domain.Tweet) error
+}
+
+// NotificationRepository guarda el inbox de notificaciones de cada usuario (puerto de salida)
+type NotificationRepository interface {
+	// Add guarda la notificación como no leída. Guardar dos veces la misma notificación no la duplica.
+	Add(ctx context.Context, notification *domain.Notification) error
+	// List devuelve hasta limit notificaciones, de la más nueva a la más vieja, creadas antes de
+	// before (o desde la más nueva si before es cero), con Read completo
+	List(ctx context.Context, userID string, before time.Time, limit int) ([]*domain.Notification, error)
+	CountUnread(ctx context.Context, userID string) (int, error)
+	// MarkRead marca como leídas las notificaciones pedidas, o todas si ids está vacío
+	MarkRead(ctx context.Context, userID string, ids []string) error
+}
+
+// IdempotencyRepository guarda qué tweet creó cada Idempotency-Key (puerto de salida)
+type IdempotencyRepository interface {
+	// Reserve toma la key durante lockTTL para procesar el pedido identificado por fingerprint y
+	// devuelve "". Si la key ya se usó con otro fingerprint devuelve domain.ErrIdempotencyKeyMismatch;
+	// si no, devuelve el ID del tweet que creó, o domain.ErrIdempotencyKeyInUse si el pedido que la
+	// tomó todavía no terminó.
+	Reserve(ctx context.Context, key, fingerprint string, lockTTL time.Duration) (string, error)
+	// Complete asocia la key al tweet creado durante ttl
+	Complete(ctx context.Context, key, fingerprint, tweetID string, ttl time.Duration) error
+	// Release libera una key reservada cuyo pedido falló, para que se pueda reintentar
+	Release(ctx context.Context, key string) error
+}
+
+// SessionRepository guarda las sesiones abiertas de cada usuario, para poder cerrarlas antes de
+// que venzan sus tokens (puerto de salida)
+type SessionRepository interface {
+	// Open abre una sesión que vence en ttl
+	Open(ctx context.Context, userID, sessionID string, ttl time.Duration) error
+	// Extend hace que una sesión abierta venza en ttl. Devuelve false si la sesión ya no existe.
+	Extend(ctx context.Context, userID, sessionID string, ttl time.Duration) (bool, error)
+	IsOpen(ctx context.Context, userID, sessionID string) (bool, error)
+	// Close cierra una sesión y CloseAll todas las del usuario
+	Close(ctx context.Context, userID, sessionID string) error
+	CloseAll(ctx context.Context, userID string) error
 }
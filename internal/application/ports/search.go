@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"ChallengeUALA/internal/domain"
+	"context"
+)
+
+// SearchIndex es el índice de búsqueda de tweets
+type SearchIndex interface {
+	// Index agrega el tweet al índice o reemplaza la versión indexada si ya estaba
+	Index(ctx context.Context, tweet *domain.Tweet) error
+	Remove(ctx context.Context, tweetID string) error
+	// Search devuelve todos los tweets que cumplen la búsqueda, sin un orden en particular
+	Search(ctx context.Context, query *domain.SearchQuery) ([]*domain.Tweet, error)
+}
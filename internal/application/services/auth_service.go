@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"ChallengeUALA/internal/application/ports"
+	"ChallengeUALA/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// AuthService abre y cierra las sesiones de los usuarios. Cada token pertenece a una sesión
+// guardada en sessions, así que cerrarla invalida sus tokens aunque no hayan vencido.
+type AuthService struct {
+	userRepo  ports.UserRepository
+	passwords ports.PasswordHasher
+	sessions  ports.SessionRepository
+	tokens    ports.TokenSigner
+}
+
+// NewAuthService crea una nueva instancia de AuthService
+func NewAuthService(
+	userRepo ports.UserRepository,
+	passwords ports.PasswordHasher,
+	sessions ports.SessionRepository,
+	tokens ports.TokenSigner,
+) *AuthService {
+	return &AuthService{
+		userRepo:  userRepo,
+		passwords: passwords,
+		sessions:  sessions,
+		tokens:    tokens,
+	}
+}
+
+// Login verifica el handle y la contraseña y abre una sesión nueva. Si el usuario no existe o la
+// contraseña no coincide devuelve domain.ErrInvalidCredentials, sin distinguir los dos casos.
+func (s *AuthService) Login(ctx context.Context, handle, password string) (*domain.User, string, error) {
+	user, err := s.userRepo.GetByHandle(ctx, handle)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return nil, "", domain.ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("error in calling userRepo.GetByHandle: %w", err)
+	}
+
+	if !s.passwords.Matches(user.PasswordHash, password) {
+		return nil, "", domain.ErrInvalidCredentials
+	}
+
+	token, err := s.OpenSession(ctx, user.ID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return user, token, nil
+}
+
+// OpenSession abre una sesión nueva de userID y devuelve su token. La sesión vence junto con él.
+func (s *AuthService) OpenSession(ctx context.Context, userID string) (string, error) {
+	sessionID := uuid.NewString()
+	if err := s.sessions.Open(ctx, userID, sessionID, s.tokens.TTL()); err != nil {
+		return "", fmt.Errorf("error in calling sessions.Open: %w", err)
+	}
+
+	token, err := s.tokens.Sign(userID, sessionID)
+	if err != nil {
+		return "", fmt.Errorf("error in calling tokens.Sign: %w", err)
+	}
+
+	return token, nil
+}
+
+// Authenticate verifica el token y que su sesión siga abierta, y devuelve el usuario y la sesión.
+// Si no es válido, venció o la sesión se cerró devuelve domain.ErrInvalidSession.
+func (s *AuthService) Authenticate(ctx context.Context, token string) (string, string, error) {
+	userID, sessionID, err := s.tokens.Verify(token)
+	if err != nil {
+		return "", "", domain.ErrInvalidSession
+	}
+
+	open, err := s.sessions.IsOpen(ctx, userID, sessionID)
+	if err != nil {
+		return "", "", fmt.Errorf("error in calling sessions.IsOpen: %w", err)
+	}
+	if !open {
+		return "", "", domain.ErrInvalidSession
+	}
+
+	return userID, sessionID, nil
+}
+
+// Refresh emite un token nuevo de una sesión abierta y la extiende para que venza con él. Si la
+// sesión se cerró devuelve domain.ErrInvalidSession.
+func (s *AuthService) Refresh(ctx context.Context, userID, sessionID string) (string, error) {
+	extended, err := s.sessions.Extend(ctx, userID, sessionID, s.tokens.TTL())
+	if err != nil {
+		return "", fmt.Errorf("error in calling sessions.Extend: %w", err)
+	}
+	if !extended {
+		return "", domain.ErrInvalidSession
+	}
+
+	token, err := s.tokens.Sign(userID, sessionID)
+	if err != nil {
+		return "", fmt.Errorf("error in calling tokens.Sign: %w", err)
+	}
+
+	return token, nil
+}
+
+// Logout cierra la sesión, invalidando todos sus tokens.
+func (s *AuthService) Logout(ctx context.Context, userID, sessionID string) error {
+	if err := s.sessions.Close(ctx, userID, sessionID); err != nil {
+		return fmt.Errorf("error in calling sessions.Close: %w", err)
+	}
+
+	return nil
+}
+
+// LogoutAll cierra todas las sesiones del usuario, por ejemplo si se filtró alguno de sus tokens.
+func (s *AuthService) LogoutAll(ctx context.Context, userID string) error {
+	if err := s.sessions.CloseAll(ctx, userID); err != nil {
+		return fmt.Errorf("error in calling sessions.CloseAll: %w", err)
+	}
+
+	return nil
+}
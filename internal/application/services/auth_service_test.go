@@ -0,0 +1,188 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"ChallengeUALA/internal/application/services"
+	"ChallengeUALA/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPasswordHasher simula el hash de las contraseñas.
+type MockPasswordHasher struct {
+	mock.Mock
+}
+
+func (m *MockPasswordHasher) Hash(password string) (string, error) {
+	args := m.Called(password)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockPasswordHasher) Matches(hash, password string) bool {
+	args := m.Called(hash, password)
+	return args.Bool(0)
+}
+
+// MockSessionRepository simula las sesiones abiertas.
+type MockSessionRepository struct {
+	mock.Mock
+}
+
+func (m *MockSessionRepository) Open(ctx context.Context, userID, sessionID string, ttl time.Duration) error {
+	args := m.Called(ctx, userID, sessionID, ttl)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) Extend(ctx context.Context, userID, sessionID string, ttl time.Duration) (bool, error) {
+	args := m.Called(ctx, userID, sessionID, ttl)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSessionRepository) IsOpen(ctx context.Context, userID, sessionID string) (bool, error) {
+	args := m.Called(ctx, userID, sessionID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSessionRepository) Close(ctx context.Context, userID, sessionID string) error {
+	args := m.Called(ctx, userID, sessionID)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) CloseAll(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+// MockTokenSigner simula la firma de los tokens.
+type MockTokenSigner struct {
+	mock.Mock
+}
+
+func (m *MockTokenSigner) Sign(userID, sessionID string) (string, error) {
+	args := m.Called(userID, sessionID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockTokenSigner) Verify(token string) (string, string, error) {
+	args := m.Called(token)
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+func (m *MockTokenSigner) TTL() time.Duration {
+	return time.Hour
+}
+
+func TestLogin_Success(t *testing.T) {
+	ctx := context.Background()
+	mockUserRepo := new(MockUserRepository)
+	mockHasher := new(MockPasswordHasher)
+	mockSessions := new(MockSessionRepository)
+	mockTokens := new(MockTokenSigner)
+	service := services.NewAuthService(mockUserRepo, mockHasher, mockSessions, mockTokens)
+
+	user := &domain.User{ID: "user1", Handle: "ana", PasswordHash: "hash"}
+	mockUserRepo.On("GetByHandle", ctx, "@ana").Return(user, nil)
+	mockHasher.On("Matches", "hash", "secreto123").Return(true)
+	mockSessions.On("Open", ctx, "user1", mock.Anything, time.Hour).Return(nil)
+	mockTokens.On("Sign", "user1", mock.Anything).Return("token", nil)
+
+	found, token, err := service.Login(ctx, "@ana", "secreto123")
+	assert.NoError(t, err)
+	assert.Equal(t, user, found)
+	assert.Equal(t, "token", token)
+
+	// El token es de la sesión que se abrió
+	sessionID := mockSessions.Calls[0].Arguments.String(2)
+	assert.NotEmpty(t, sessionID)
+	mockTokens.AssertCalled(t, "Sign", "user1", sessionID)
+}
+
+func TestLogin_InvalidCredentials(t *testing.T) {
+	ctx := context.Background()
+	mockUserRepo := new(MockUserRepository)
+	mockHasher := new(MockPasswordHasher)
+	mockSessions := new(MockSessionRepository)
+	service := services.NewAuthService(mockUserRepo, mockHasher, mockSessions, new(MockTokenSigner))
+
+	mockUserRepo.On("GetByHandle", ctx, "ana").Return(&domain.User{ID: "user1", PasswordHash: "hash"}, nil)
+	mockUserRepo.On("GetByHandle", ctx, "beto").Return(nil, domain.ErrUserNotFound)
+	mockHasher.On("Matches", "hash", "incorrecta").Return(false)
+
+	// No se distingue un handle inexistente de una contraseña incorrecta
+	_, _, err := service.Login(ctx, "ana", "incorrecta")
+	assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
+
+	_, _, err = service.Login(ctx, "beto", "incorrecta")
+	assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
+
+	mockSessions.AssertNotCalled(t, "Open", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthenticate(t *testing.T) {
+	ctx := context.Background()
+	mockSessions := new(MockSessionRepository)
+	mockTokens := new(MockTokenSigner)
+	service := services.NewAuthService(nil, nil, mockSessions, mockTokens)
+
+	mockTokens.On("Verify", "valid").Return("user1", "session1", nil)
+	mockTokens.On("Verify", "closed").Return("user1", "session2", nil)
+	mockTokens.On("Verify", "forged").Return("", "", errors.New("invalid token"))
+	mockTokens.On("Verify", "unavailable").Return("user1", "session3", nil)
+	mockSessions.On("IsOpen", ctx, "user1", "session1").Return(true, nil)
+	mockSessions.On("IsOpen", ctx, "user1", "session2").Return(false, nil)
+	mockSessions.On("IsOpen", ctx, "user1", "session3").Return(false, errors.New("redis error"))
+
+	userID, sessionID, err := service.Authenticate(ctx, "valid")
+	assert.NoError(t, err)
+	assert.Equal(t, "user1", userID)
+	assert.Equal(t, "session1", sessionID)
+
+	// Un token bien firmado de una sesión cerrada ya no sirve
+	_, _, err = service.Authenticate(ctx, "closed")
+	assert.ErrorIs(t, err, domain.ErrInvalidSession)
+
+	_, _, err = service.Authenticate(ctx, "forged")
+	assert.ErrorIs(t, err, domain.ErrInvalidSession)
+
+	_, _, err = service.Authenticate(ctx, "unavailable")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, domain.ErrInvalidSession)
+}
+
+func TestRefresh(t *testing.T) {
+	ctx := context.Background()
+	mockSessions := new(MockSessionRepository)
+	mockTokens := new(MockTokenSigner)
+	service := services.NewAuthService(nil, nil, mockSessions, mockTokens)
+
+	mockSessions.On("Extend", ctx, "user1", "session1", time.Hour).Return(true, nil)
+	mockSessions.On("Extend", ctx, "user1", "session2", time.Hour).Return(false, nil)
+	mockTokens.On("Sign", "user1", "session1").Return("token", nil)
+
+	token, err := service.Refresh(ctx, "user1", "session1")
+	assert.NoError(t, err)
+	assert.Equal(t, "token", token)
+
+	// Una sesión cerrada no se puede renovar
+	_, err = service.Refresh(ctx, "user1", "session2")
+	assert.ErrorIs(t, err, domain.ErrInvalidSession)
+	mockTokens.AssertNumberOfCalls(t, "Sign", 1)
+}
+
+func TestLogout(t *testing.T) {
+	ctx := context.Background()
+	mockSessions := new(MockSessionRepository)
+	service := services.NewAuthService(nil, nil, mockSessions, new(MockTokenSigner))
+
+	mockSessions.On("Close", ctx, "user1", "session1").Return(nil)
+	mockSessions.On("CloseAll", ctx, "user1").Return(errors.New("redis error"))
+
+	assert.NoError(t, service.Logout(ctx, "user1", "session1"))
+	assert.Error(t, service.LogoutAll(ctx, "user1"))
+	mockSessions.AssertExpectations(t)
+}
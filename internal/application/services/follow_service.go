@@ -2,24 +2,39 @@ package services
 
 import (
 	"ChallengeUALA/internal/application/ports"
+	"ChallengeUALA/internal/domain"
 	"context"
+	"encoding/base64"
 	"fmt"
 	uuid2 "github.com/google/uuid"
 )
 
+// maxFollowListLimit es la cantidad máxima de usuarios que se devuelven por página
+const maxFollowListLimit = 100
+
 type FollowService struct {
-	followRepo ports.FollowRepository
-	userRepo   ports.UserRepository
+	followRepo    ports.FollowRepository
+	userRepo      ports.UserRepository
+	redisRepo     ports.RedisRepository
+	notifications ports.BatchEventProducer
 }
 
-func NewFollowService(followRepo ports.FollowRepository, userRepo ports.UserRepository) *FollowService {
+func NewFollowService(
+	followRepo ports.FollowRepository,
+	userRepo ports.UserRepository,
+	redisRepo ports.RedisRepository,
+	notifications ports.BatchEventProducer,
+) *FollowService {
 	return &FollowService{
-		followRepo: followRepo,
-		userRepo:   userRepo,
+		followRepo:    followRepo,
+		userRepo:      userRepo,
+		redisRepo:     redisRepo,
+		notifications: notifications,
 	}
 }
 
-// Follow permite a un usuario seguir a otro
+// Follow permite a un usuario seguir a otro y le avisa al seguido con una notificación.
+// Devuelve domain.ErrUserNotFound si alguno de los dos usuarios no existe.
 func (s *FollowService) Follow(ctx context.Context, followerID, followeeID string) error {
 
 	err := validateUUID(followerID, followeeID)
@@ -57,14 +72,133 @@ func (s *FollowService) Follow(ctx context.Context, followerID, followeeID strin
 		return fmt.Errorf("error in calling followRepo.Follow(): %w", err)
 	}
 
+	publishNotifications(ctx, s.notifications, domain.NewNotification(domain.NotificationFollow, followeeID, followerID, ""))
+
+	return nil
+}
+
+// Unfollow permite a un usuario dejar de seguir a otro y limpia su timeline
+// de los tweets del usuario que dejó de seguir.
+func (s *FollowService) Unfollow(ctx context.Context, followerID, followeeID string) error {
+
+	err := validateUUID(followerID, followeeID)
+	if err != nil {
+		return err
+	}
+
+	if followerID == followeeID {
+		return fmt.Errorf("user %s can't unfollow itself", followerID)
+	}
+
+	isFollowing, err := s.followRepo.IsFollowing(ctx, followerID, followeeID)
+	if err != nil {
+		return fmt.Errorf("error calling IsFollowing(): %w", err)
+	}
+
+	if !isFollowing {
+		return fmt.Errorf("user %s is not following user %s: %w", followerID, followeeID, domain.ErrNotFollowing)
+	}
+
+	// Limpiamos el timeline antes de borrar el follow: si la limpieza falla el follow sigue
+	// existiendo y un reintento la vuelve a hacer, en vez de encontrarse con que ya no lo sigue
+	if err := s.redisRepo.RemoveAuthorFromTimeline(ctx, followerID, followeeID); err != nil {
+		return fmt.Errorf("error in calling redisRepo.RemoveAuthorFromTimeline(): %w", err)
+	}
+
+	if err := s.followRepo.Unfollow(ctx, followerID, followeeID); err != nil {
+		return fmt.Errorf("error in calling followRepo.Unfollow(): %w", err)
+	}
+
 	return nil
 }
 
+// GetFollowers devuelve una página de los seguidores de un usuario junto con el total.
+func (s *FollowService) GetFollowers(ctx context.Context, userID, cursor string, limit int) (*domain.FollowList, error) {
+	return s.listFollows(ctx, userID, cursor, limit, s.followRepo.ListFollowers, s.followRepo.CountFollowers)
+}
+
+// GetFollowing devuelve una página de los usuarios que sigue un usuario junto con el total.
+func (s *FollowService) GetFollowing(ctx context.Context, userID, cursor string, limit int) (*domain.FollowList, error) {
+	return s.listFollows(ctx, userID, cursor, limit, s.followRepo.ListFollowing, s.followRepo.CountFollowing)
+}
+
+// listFollows arma una página de una lista de follows. El cursor es el último ID devuelto, codificado.
+// Devuelve domain.ErrUserNotFound si el usuario no existe.
+func (s *FollowService) listFollows(
+	ctx context.Context,
+	userID, cursor string,
+	limit int,
+	list func(ctx context.Context, userID, afterID string, limit int) ([]string, error),
+	count func(ctx context.Context, userID string) (int, error),
+) (*domain.FollowList, error) {
+	if err := validateUUID(userID); err != nil {
+		return nil, err
+	}
+
+	if limit < 0 {
+		return nil, fmt.Errorf("limit must be positive")
+	}
+
+	if limit == 0 || limit > maxFollowListLimit {
+		limit = maxFollowListLimit
+	}
+
+	afterID, err := decodeIDCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, fmt.Errorf("error in calling userRepo.GetByID(): %w", err)
+	}
+
+	// Pedimos un elemento de más para saber si hay una página siguiente
+	users, err := list(ctx, userID, afterID, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("error listing follows: %w", err)
+	}
+
+	total, err := count(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error counting follows: %w", err)
+	}
+
+	result := &domain.FollowList{
+		UserID: userID,
+		Users:  users,
+		Count:  total,
+	}
+
+	if len(users) > limit {
+		result.Users = users[:limit]
+		result.NextCursor = encodeIDCursor(users[limit-1])
+	}
+
+	return result, nil
+}
+
+// decodeIDCursor devuelve el último ID devuelto en la página anterior, o vacío si no hay cursor.
+func decodeIDCursor(cursor string) (string, error) {
+	if cursor == "" {
+		return "", nil
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(decoded) == 0 {
+		return "", domain.ErrInvalidCursor
+	}
+	return string(decoded), nil
+}
+
+func encodeIDCursor(id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(id))
+}
+
 func validateUUID(uuid ...string) error {
 	for _, u := range uuid {
 		_, err := uuid2.Parse(u)
 		if err != nil {
-			return fmt.Errorf("invalid UUID: %s", u)
+			return fmt.Errorf("%w: %s", domain.ErrInvalidUserID, u)
 		}
 	}
 	return nil
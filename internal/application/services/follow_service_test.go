@@ -24,6 +24,11 @@ func (m *MockFollowsRepository) Follow(ctx context.Context, followerID, followed
 	return args.Error(0)
 }
 
+func (m *MockFollowsRepository) Unfollow(ctx context.Context, followerID, followedID string) error {
+	args := m.Called(ctx, followerID, followedID)
+	return args.Error(0)
+}
+
 func (m *MockFollowsRepository) IsFollowing(ctx context.Context, followerID, followedID string) (bool, error) {
 	args := m.Called(ctx, followerID, followedID)
 	return args.Bool(0), args.Error(1)
@@ -34,21 +39,58 @@ func (m *MockFollowsRepository) GetFollowers(ctx context.Context, userID string)
 	return args.Get(0).([]string), args.Error(1)
 }
 
+func (m *MockFollowsRepository) GetFollowing(ctx context.Context, userID string) ([]string, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockFollowsRepository) CountFollowers(ctx context.Context, userID string) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockFollowsRepository) CountFollowing(ctx context.Context, userID string) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockFollowsRepository) ListFollowers(ctx context.Context, userID string, afterID string, limit int) ([]string, error) {
+	args := m.Called(ctx, userID, afterID, limit)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockFollowsRepository) ListFollowing(ctx context.Context, userID string, afterID string, limit int) ([]string, error) {
+	args := m.Called(ctx, userID, afterID, limit)
+	return args.Get(0).([]string), args.Error(1)
+}
+
 // Mock de UserRepository
 type MockUserRepository struct {
 	mock.Mock
 }
 
+func (m *MockUserRepository) Create(ctx context.Context, user *domain.User) error {
+	args := m.Called(ctx, user)
+	return args.Error(0)
+}
+
 func (m *MockUserRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
 	args := m.Called(ctx, id)
-	return args.Get(0).(*domain.User), args.Error(1)
+	user, _ := args.Get(0).(*domain.User)
+	return user, args.Error(1)
+}
+
+func (m *MockUserRepository) GetByHandle(ctx context.Context, handle string) (*domain.User, error) {
+	args := m.Called(ctx, handle)
+	user, _ := args.Get(0).(*domain.User)
+	return user, args.Error(1)
 }
 
 func TestFollowService_Follow(t *testing.T) {
 	// Arrange
 	mockFollowRepo := new(MockFollowsRepository)
 	mockUserRepo := new(MockUserRepository)
-	service := services.NewFollowService(mockFollowRepo, mockUserRepo)
+	service := services.NewFollowService(mockFollowRepo, mockUserRepo, nil, nil)
 
 	ctx := context.Background()
 	followerID := uuid.NewString()
@@ -68,10 +110,35 @@ func TestFollowService_Follow(t *testing.T) {
 	mockFollowRepo.AssertExpectations(t)
 }
 
+func TestFollowService_Follow_NotifiesFollowee(t *testing.T) {
+	mockFollowRepo := new(MockFollowsRepository)
+	mockUserRepo := new(MockUserRepository)
+	mockProducer := new(MockEventProducer)
+	service := services.NewFollowService(mockFollowRepo, mockUserRepo, nil, mockProducer)
+
+	ctx := context.Background()
+	followerID := uuid.NewString()
+	followeeID := uuid.NewString()
+
+	mockUserRepo.On("GetByID", ctx, mock.Anything).Return(&domain.User{}, nil)
+	mockFollowRepo.On("IsFollowing", ctx, followerID, followeeID).Return(false, nil)
+	mockFollowRepo.On("Follow", ctx, followerID, followeeID).Return(nil)
+	// Si no se puede publicar la notificación el follow igual queda hecho
+	mockProducer.On("PublishEvents", ctx, mock.Anything).Return(errors.New("kafka error"))
+
+	assert.NoError(t, service.Follow(ctx, followerID, followeeID))
+
+	published := mockProducer.published(t)
+	if assert.Len(t, published, 1) {
+		assert.Equal(t, domain.NotificationFollow, published[0].Type)
+		assert.Equal(t, followerID, published[0].ActorID)
+	}
+}
+
 func TestFollowService_Follow_SameUser(t *testing.T) {
 	mockFollowRepo := new(MockFollowsRepository)
 	mockUserRepo := new(MockUserRepository)
-	service := services.NewFollowService(mockFollowRepo, mockUserRepo)
+	service := services.NewFollowService(mockFollowRepo, mockUserRepo, nil, nil)
 
 	ctx := context.Background()
 	userID := uuid.NewString()
@@ -85,7 +152,7 @@ func TestFollowService_Follow_AlreadyFollowing(t *testing.T) {
 	// Arrange
 	mockFollowRepo := new(MockFollowsRepository)
 	mockUserRepo := new(MockUserRepository)
-	service := services.NewFollowService(mockFollowRepo, mockUserRepo)
+	service := services.NewFollowService(mockFollowRepo, mockUserRepo, nil, nil)
 
 	ctx := context.Background()
 	followerID := uuid.NewString()
@@ -107,7 +174,7 @@ func TestFollowService_Follow_InvalidUUID(t *testing.T) {
 	// Arrange
 	mockFollowRepo := new(MockFollowsRepository)
 	mockUserRepo := new(MockUserRepository)
-	service := services.NewFollowService(mockFollowRepo, mockUserRepo)
+	service := services.NewFollowService(mockFollowRepo, mockUserRepo, nil, nil)
 
 	ctx := context.Background()
 	followerID := "asd-uuid"
@@ -118,6 +185,7 @@ func TestFollowService_Follow_InvalidUUID(t *testing.T) {
 
 	// Assert
 	assert.Error(t, err)
+	assert.ErrorIs(t, err, domain.ErrInvalidUserID)
 	assert.Equal(t, fmt.Sprintf("invalid UUID: %s", followerID), err.Error())
 }
 
@@ -125,7 +193,7 @@ func TestFollowService_Follow_ErrorFollowingUser(t *testing.T) {
 	// Arrange
 	mockFollowRepo := new(MockFollowsRepository)
 	mockUserRepo := new(MockUserRepository)
-	service := services.NewFollowService(mockFollowRepo, mockUserRepo)
+	service := services.NewFollowService(mockFollowRepo, mockUserRepo, nil, nil)
 
 	ctx := context.Background()
 	followerID := uuid.NewString()
@@ -149,7 +217,7 @@ func TestFollowService_Follow_GetFollowerError(t *testing.T) {
 	// Arrange
 	mockFollowRepo := new(MockFollowsRepository)
 	mockUserRepo := new(MockUserRepository)
-	service := services.NewFollowService(mockFollowRepo, mockUserRepo)
+	service := services.NewFollowService(mockFollowRepo, mockUserRepo, nil, nil)
 
 	ctx := context.Background()
 	followerID := uuid.NewString()
@@ -170,7 +238,7 @@ func TestFollowService_Follow_GetFolloweeError(t *testing.T) {
 	// Arrange
 	mockFollowRepo := new(MockFollowsRepository)
 	mockUserRepo := new(MockUserRepository)
-	service := services.NewFollowService(mockFollowRepo, mockUserRepo)
+	service := services.NewFollowService(mockFollowRepo, mockUserRepo, nil, nil)
 
 	ctx := context.Background()
 	followerID := uuid.NewString()
@@ -187,3 +255,173 @@ func TestFollowService_Follow_GetFolloweeError(t *testing.T) {
 	assert.Equal(t, "error in calling userRepo.GetByID(): error getting user", err.Error())
 	mockUserRepo.AssertExpectations(t)
 }
+
+func TestFollowService_Unfollow(t *testing.T) {
+	// Arrange
+	mockFollowRepo := new(MockFollowsRepository)
+	mockUserRepo := new(MockUserRepository)
+	mockRedisRepo := new(MockRedisRepository)
+	service := services.NewFollowService(mockFollowRepo, mockUserRepo, mockRedisRepo, nil)
+
+	ctx := context.Background()
+	followerID := uuid.NewString()
+	followeeID := uuid.NewString()
+
+	mockFollowRepo.On("IsFollowing", ctx, followerID, followeeID).Return(true, nil)
+	mockFollowRepo.On("Unfollow", ctx, followerID, followeeID).Return(nil)
+	mockRedisRepo.On("RemoveAuthorFromTimeline", ctx, followerID, followeeID).Return(nil)
+
+	// Act
+	err := service.Unfollow(ctx, followerID, followeeID)
+
+	// Assert
+	assert.NoError(t, err)
+	mockFollowRepo.AssertExpectations(t)
+	mockRedisRepo.AssertExpectations(t)
+}
+
+func TestFollowService_Unfollow_NotFollowing(t *testing.T) {
+	// Arrange
+	mockFollowRepo := new(MockFollowsRepository)
+	mockUserRepo := new(MockUserRepository)
+	mockRedisRepo := new(MockRedisRepository)
+	service := services.NewFollowService(mockFollowRepo, mockUserRepo, mockRedisRepo, nil)
+
+	ctx := context.Background()
+	followerID := uuid.NewString()
+	followeeID := uuid.NewString()
+
+	mockFollowRepo.On("IsFollowing", ctx, followerID, followeeID).Return(false, nil)
+
+	// Act
+	err := service.Unfollow(ctx, followerID, followeeID)
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrNotFollowing)
+	mockFollowRepo.AssertNotCalled(t, "Unfollow", ctx, followerID, followeeID)
+	mockRedisRepo.AssertNotCalled(t, "RemoveAuthorFromTimeline", ctx, followerID, followeeID)
+}
+
+func TestFollowService_Unfollow_SameUser(t *testing.T) {
+	service := services.NewFollowService(new(MockFollowsRepository), new(MockUserRepository), new(MockRedisRepository), nil)
+
+	ctx := context.Background()
+	userID := uuid.NewString()
+
+	err := service.Unfollow(ctx, userID, userID)
+	assert.Error(t, err)
+	assert.Equal(t, fmt.Sprintf("user %s can't unfollow itself", userID), err.Error())
+}
+
+func TestFollowService_Unfollow_RemoveFromTimelineError(t *testing.T) {
+	// Arrange
+	mockFollowRepo := new(MockFollowsRepository)
+	mockUserRepo := new(MockUserRepository)
+	mockRedisRepo := new(MockRedisRepository)
+	service := services.NewFollowService(mockFollowRepo, mockUserRepo, mockRedisRepo, nil)
+
+	ctx := context.Background()
+	followerID := uuid.NewString()
+	followeeID := uuid.NewString()
+
+	mockFollowRepo.On("IsFollowing", ctx, followerID, followeeID).Return(true, nil)
+	mockRedisRepo.On("RemoveAuthorFromTimeline", ctx, followerID, followeeID).Return(errors.New("redis error"))
+
+	// Act
+	err := service.Unfollow(ctx, followerID, followeeID)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, "error in calling redisRepo.RemoveAuthorFromTimeline(): redis error", err.Error())
+	mockFollowRepo.AssertExpectations(t)
+	// El follow queda para que un reintento vuelva a limpiar el timeline
+	mockFollowRepo.AssertNotCalled(t, "Unfollow", ctx, followerID, followeeID)
+}
+
+func TestFollowService_GetFollowers(t *testing.T) {
+	// Arrange
+	mockFollowRepo := new(MockFollowsRepository)
+	mockUserRepo := new(MockUserRepository)
+	service := services.NewFollowService(mockFollowRepo, mockUserRepo, nil, nil)
+
+	ctx := context.Background()
+	userID := uuid.NewString()
+
+	mockUserRepo.On("GetByID", ctx, userID).Return(&domain.User{ID: userID}, nil)
+
+	mockFollowRepo.On("ListFollowers", ctx, userID, "", 3).Return([]string{"a", "b", "c"}, nil)
+	mockFollowRepo.On("CountFollowers", ctx, userID).Return(5, nil)
+
+	// Act
+	result, err := service.GetFollowers(ctx, userID, "", 2)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, result.Users)
+	assert.Equal(t, 5, result.Count)
+	assert.NotEmpty(t, result.NextCursor)
+
+	// La página siguiente arranca después del último usuario devuelto
+	mockFollowRepo.On("ListFollowers", ctx, userID, "b", 3).Return([]string{"c"}, nil)
+
+	result, err = service.GetFollowers(ctx, userID, result.NextCursor, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"c"}, result.Users)
+	assert.Empty(t, result.NextCursor)
+	mockFollowRepo.AssertExpectations(t)
+}
+
+func TestFollowService_GetFollowing(t *testing.T) {
+	// Arrange
+	mockFollowRepo := new(MockFollowsRepository)
+	mockUserRepo := new(MockUserRepository)
+	service := services.NewFollowService(mockFollowRepo, mockUserRepo, nil, nil)
+
+	ctx := context.Background()
+	userID := uuid.NewString()
+
+	mockUserRepo.On("GetByID", ctx, userID).Return(&domain.User{ID: userID}, nil)
+
+	mockFollowRepo.On("ListFollowing", ctx, userID, "", 101).Return([]string{"a"}, nil)
+	mockFollowRepo.On("CountFollowing", ctx, userID).Return(1, nil)
+
+	// Act
+	result, err := service.GetFollowing(ctx, userID, "", 0)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a"}, result.Users)
+	assert.Equal(t, 1, result.Count)
+	assert.Empty(t, result.NextCursor)
+	mockFollowRepo.AssertExpectations(t)
+}
+
+func TestFollowService_GetFollowers_UserNotFound(t *testing.T) {
+	mockFollowRepo := new(MockFollowsRepository)
+	mockUserRepo := new(MockUserRepository)
+	service := services.NewFollowService(mockFollowRepo, mockUserRepo, nil, nil)
+
+	ctx := context.Background()
+	userID := uuid.NewString()
+	mockUserRepo.On("GetByID", ctx, userID).Return(nil, domain.ErrUserNotFound)
+
+	_, err := service.GetFollowers(ctx, userID, "", 10)
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	mockFollowRepo.AssertNotCalled(t, "ListFollowers", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFollowService_GetFollowers_InvalidCursor(t *testing.T) {
+	service := services.NewFollowService(new(MockFollowsRepository), new(MockUserRepository), nil, nil)
+
+	_, err := service.GetFollowers(context.Background(), uuid.NewString(), "%%%", 10)
+	assert.ErrorIs(t, err, domain.ErrInvalidCursor)
+}
+
+func TestFollowService_GetFollowers_InvalidUUID(t *testing.T) {
+	service := services.NewFollowService(new(MockFollowsRepository), new(MockUserRepository), nil, nil)
+
+	_, err := service.GetFollowers(context.Background(), "asd-uuid", "", 10)
+	assert.Error(t, err)
+	assert.ErrorIs(t, err, domain.ErrInvalidUserID)
+	assert.Equal(t, "invalid UUID: asd-uuid", err.Error())
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"ChallengeUALA/internal/application/ports"
+	"ChallengeUALA/internal/domain"
+)
+
+// maxLikeListLimit es la cantidad máxima de usuarios que se devuelven por página
+const maxLikeListLimit = 100
+
+// LikeService maneja los likes de los tweets. Los likes a un retweet cuentan para el tweet original.
+type LikeService struct {
+	likeRepo      ports.LikeRepository
+	tweetRepo     ports.TweetRepository
+	notifications ports.BatchEventProducer
+}
+
+// NewLikeService crea una nueva instancia de LikeService
+func NewLikeService(likeRepo ports.LikeRepository, tweetRepo ports.TweetRepository, notifications ports.BatchEventProducer) *LikeService {
+	return &LikeService{
+		likeRepo:      likeRepo,
+		tweetRepo:     tweetRepo,
+		notifications: notifications,
+	}
+}
+
+// Like registra el like de un usuario a un tweet y le avisa al autor. Dar like dos veces no es un
+// error, pero solo el primero notifica.
+func (s *LikeService) Like(ctx context.Context, tweetID, userID string) error {
+	if userID == "" {
+		return fmt.Errorf("userID is required")
+	}
+
+	liked, err := s.tweetRepo.GetByID(ctx, tweetID)
+	if err != nil {
+		return fmt.Errorf("error in calling tweetRepo.GetByID: %w", err)
+	}
+
+	// El like es del tweet original, así que la notificación es para su autor
+	if liked.IsRetweet() {
+		liked, err = s.tweetRepo.GetByID(ctx, liked.ReferencedTweetID)
+		if err != nil {
+			return fmt.Errorf("error in calling tweetRepo.GetByID: %w", err)
+		}
+	}
+
+	added, err := s.likeRepo.Like(ctx, liked.ID, userID)
+	if err != nil {
+		return fmt.Errorf("error in calling likeRepo.Like: %w", err)
+	}
+
+	if added {
+		publishNotifications(ctx, s.notifications, domain.NewNotification(domain.NotificationLike, liked.UserID, userID, liked.ID))
+	}
+
+	return nil
+}
+
+// Unlike saca el like de un usuario a un tweet. Sacar un like que no existe no es un error.
+func (s *LikeService) Unlike(ctx context.Context, tweetID, userID string) error {
+	if userID == "" {
+		return fmt.Errorf("userID is required")
+	}
+
+	likedID, err := s.likedTweetID(ctx, tweetID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.likeRepo.Unlike(ctx, likedID, userID); err != nil {
+		return fmt.Errorf("error in calling likeRepo.Unlike: %w", err)
+	}
+
+	return nil
+}
+
+// GetLikers devuelve una página de los usuarios que le dieron like a un tweet junto con el total.
+// El cursor es el último ID devuelto, codificado, igual que en las listas de follows.
+func (s *LikeService) GetLikers(ctx context.Context, tweetID, cursor string, limit int) (*domain.LikeList, error) {
+	if limit < 0 {
+		return nil, fmt.Errorf("limit must be positive")
+	}
+
+	if limit == 0 || limit > maxLikeListLimit {
+		limit = maxLikeListLimit
+	}
+
+	afterID, err := decodeIDCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	likedID, err := s.likedTweetID(ctx, tweetID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Pedimos un elemento de más para saber si hay una página siguiente
+	users, err := s.likeRepo.ListLikers(ctx, likedID, afterID, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("error in calling likeRepo.ListLikers: %w", err)
+	}
+
+	counts, err := s.likeRepo.CountLikes(ctx, []string{likedID})
+	if err != nil {
+		return nil, fmt.Errorf("error in calling likeRepo.CountLikes: %w", err)
+	}
+
+	result := &domain.LikeList{
+		TweetID: likedID,
+		Users:   users,
+		Count:   counts[likedID],
+	}
+
+	if len(users) > limit {
+		result.Users = users[:limit]
+		result.NextCursor = encodeIDCursor(users[limit-1])
+	}
+
+	return result, nil
+}
+
+// likedTweetID devuelve el tweet que recibe el like: el original si tweetID es un retweet.
+func (s *LikeService) likedTweetID(ctx context.Context, tweetID string) (string, error) {
+	tweet, err := s.tweetRepo.GetByID(ctx, tweetID)
+	if err != nil {
+		return "", fmt.Errorf("error in calling tweetRepo.GetByID: %w", err)
+	}
+
+	return tweet.ContentID(), nil
+}
+
+// hydrateLikeCounts completa LikeCount en los tweets con una sola consulta al repositorio.
+// Los retweets muestran los likes del tweet original.
+func hydrateLikeCounts(ctx context.Context, likeRepo ports.LikeRepository, tweets []*domain.Tweet) error {
+	if len(tweets) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]bool, len(tweets))
+	ids := make([]string, 0, len(tweets))
+	for _, tweet := range tweets {
+		if !seen[tweet.ContentID()] {
+			seen[tweet.ContentID()] = true
+			ids = append(ids, tweet.ContentID())
+		}
+	}
+
+	counts, err := likeRepo.CountLikes(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("error in calling likeRepo.CountLikes: %w", err)
+	}
+
+	for _, tweet := range tweets {
+		tweet.LikeCount = counts[tweet.ContentID()]
+	}
+
+	return nil
+}
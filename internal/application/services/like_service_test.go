@@ -0,0 +1,170 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"log"
+	"testing"
+
+	"ChallengeUALA/internal/application/services"
+	"ChallengeUALA/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockLikeRepository simula el almacenamiento de likes.
+type MockLikeRepository struct {
+	mock.Mock
+}
+
+func (m *MockLikeRepository) Like(ctx context.Context, tweetID, userID string) (bool, error) {
+	args := m.Called(ctx, tweetID, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockLikeRepository) Unlike(ctx context.Context, tweetID, userID string) (bool, error) {
+	args := m.Called(ctx, tweetID, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockLikeRepository) CountLikes(ctx context.Context, tweetIDs []string) (map[string]int, error) {
+	args := m.Called(ctx, tweetIDs)
+	counts, _ := args.Get(0).(map[string]int)
+	return counts, args.Error(1)
+}
+
+func (m *MockLikeRepository) ListLikers(ctx context.Context, tweetID, afterID string, limit int) ([]string, error) {
+	args := m.Called(ctx, tweetID, afterID, limit)
+	users, _ := args.Get(0).([]string)
+	return users, args.Error(1)
+}
+
+func TestLike_Success(t *testing.T) {
+	ctx := context.Background()
+	mockLikeRepo := new(MockLikeRepository)
+	mockTweetRepo := new(MockTweetRepository)
+	service := services.NewLikeService(mockLikeRepo, mockTweetRepo, nil)
+
+	mockTweetRepo.On("GetByID", ctx, "tweet1").Return(&domain.Tweet{ID: "tweet1"}, nil)
+	mockLikeRepo.On("Like", ctx, "tweet1", "user1").Return(true, nil)
+
+	assert.NoError(t, service.Like(ctx, "tweet1", "user1"))
+	mockLikeRepo.AssertExpectations(t)
+}
+
+func TestLike_RetweetLikesOriginal(t *testing.T) {
+	ctx := context.Background()
+	mockLikeRepo := new(MockLikeRepository)
+	mockTweetRepo := new(MockTweetRepository)
+	service := services.NewLikeService(mockLikeRepo, mockTweetRepo, nil)
+
+	retweet := &domain.Tweet{ID: "rt1", Kind: domain.TweetKindRetweet, ReferencedTweetID: "tweet1"}
+	mockTweetRepo.On("GetByID", ctx, "rt1").Return(retweet, nil)
+	mockLikeRepo.On("Unlike", ctx, "tweet1", "user1").Return(false, nil)
+
+	assert.NoError(t, service.Unlike(ctx, "rt1", "user1"))
+	mockLikeRepo.AssertExpectations(t)
+}
+
+func TestLike_NotifiesAuthor(t *testing.T) {
+	ctx := context.Background()
+	mockLikeRepo := new(MockLikeRepository)
+	mockTweetRepo := new(MockTweetRepository)
+	mockProducer := new(MockEventProducer)
+	service := services.NewLikeService(mockLikeRepo, mockTweetRepo, mockProducer)
+
+	// El like a un retweet le llega al autor del tweet original
+	retweet := &domain.Tweet{ID: "rt1", UserID: "user3", Kind: domain.TweetKindRetweet, ReferencedTweetID: "tweet1"}
+	mockTweetRepo.On("GetByID", ctx, "rt1").Return(retweet, nil)
+	mockTweetRepo.On("GetByID", ctx, "tweet1").Return(&domain.Tweet{ID: "tweet1", UserID: "user1"}, nil)
+	mockLikeRepo.On("Like", ctx, "tweet1", "user2").Return(true, nil).Once()
+	mockLikeRepo.On("Like", ctx, "tweet1", "user2").Return(false, nil).Once()
+	mockLikeRepo.On("Like", ctx, "tweet1", "user1").Return(true, nil).Once()
+	mockProducer.On("PublishEvents", ctx, mock.Anything).Return(nil)
+
+	assert.NoError(t, service.Like(ctx, "rt1", "user2"))
+	// Repetir el like o darse like a uno mismo no notifica
+	assert.NoError(t, service.Like(ctx, "rt1", "user2"))
+	assert.NoError(t, service.Like(ctx, "rt1", "user1"))
+
+	published := mockProducer.published(t)
+	if assert.Len(t, published, 1) {
+		assert.Equal(t, domain.NotificationLike, published[0].Type)
+		assert.Equal(t, "user2", published[0].ActorID)
+		assert.Equal(t, "tweet1", published[0].TweetID)
+	}
+	mockLikeRepo.AssertExpectations(t)
+}
+
+func TestLike_TweetNotFound(t *testing.T) {
+	ctx := context.Background()
+	mockLikeRepo := new(MockLikeRepository)
+	mockTweetRepo := new(MockTweetRepository)
+	service := services.NewLikeService(mockLikeRepo, mockTweetRepo, nil)
+
+	mockTweetRepo.On("GetByID", ctx, "tweet1").Return(nil, domain.ErrTweetNotFound)
+
+	err := service.Like(ctx, "tweet1", "user1")
+	assert.ErrorIs(t, err, domain.ErrTweetNotFound)
+	mockLikeRepo.AssertNotCalled(t, "Like", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestLike_UserIDEmpty(t *testing.T) {
+	service := services.NewLikeService(new(MockLikeRepository), new(MockTweetRepository), nil)
+
+	assert.Error(t, service.Like(context.Background(), "tweet1", ""))
+	assert.Error(t, service.Unlike(context.Background(), "tweet1", ""))
+}
+
+func TestGetLikers_Paginates(t *testing.T) {
+	ctx := context.Background()
+	mockLikeRepo := new(MockLikeRepository)
+	mockTweetRepo := new(MockTweetRepository)
+	service := services.NewLikeService(mockLikeRepo, mockTweetRepo, nil)
+
+	mockTweetRepo.On("GetByID", ctx, "tweet1").Return(&domain.Tweet{ID: "tweet1"}, nil)
+	mockLikeRepo.On("ListLikers", ctx, "tweet1", "", 3).Return([]string{"a", "b", "c"}, nil)
+	mockLikeRepo.On("CountLikes", ctx, []string{"tweet1"}).Return(map[string]int{"tweet1": 5}, nil)
+
+	result, err := service.GetLikers(ctx, "tweet1", "", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, result.Users)
+	assert.Equal(t, 5, result.Count)
+	assert.NotEmpty(t, result.NextCursor)
+
+	// La página siguiente arranca después del último usuario devuelto
+	mockLikeRepo.On("ListLikers", ctx, "tweet1", "b", 3).Return([]string{"c"}, nil)
+
+	result, err = service.GetLikers(ctx, "tweet1", result.NextCursor, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"c"}, result.Users)
+	assert.Empty(t, result.NextCursor)
+}
+
+func TestGetLikers_InvalidCursor(t *testing.T) {
+	service := services.NewLikeService(new(MockLikeRepository), new(MockTweetRepository), nil)
+
+	_, err := service.GetLikers(context.Background(), "tweet1", "%%%", 0)
+	assert.ErrorIs(t, err, domain.ErrInvalidCursor)
+}
+
+func TestGetTweet_HydratesLikeCount(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockTweetRepository)
+	mockLikeRepo := new(MockLikeRepository)
+	tweetService := services.NewTweetService(mockRepo, mockLikeRepo, nil, nil, nil, log.Default())
+
+	mockRepo.On("GetByID", ctx, "tweet1").Return(&domain.Tweet{ID: "tweet1"}, nil)
+	mockLikeRepo.On("CountLikes", ctx, []string{"tweet1"}).Return(map[string]int{"tweet1": 2}, nil)
+
+	tweet, err := tweetService.GetTweet(ctx, "tweet1")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, tweet.LikeCount)
+
+	mockLikeRepo.ExpectedCalls = nil
+	mockLikeRepo.On("CountLikes", ctx, []string{"tweet1"}).Return(nil, errors.New("redis error"))
+
+	_, err = tweetService.GetTweet(ctx, "tweet1")
+	assert.Error(t, err)
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"ChallengeUALA/internal/application/ports"
+	"ChallengeUALA/internal/domain"
+)
+
+// maxNotificationListLimit es la cantidad máxima de notificaciones que se devuelven por página
+const maxNotificationListLimit = 100
+
+// NotificationService guarda en el inbox de cada usuario las notificaciones que se consumen de Kafka
+// y las devuelve. Las publican FollowService, LikeService y TweetService con publishNotification.
+type NotificationService struct {
+	notificationRepo ports.NotificationRepository
+}
+
+// NewNotificationService crea una nueva instancia de NotificationService
+func NewNotificationService(notificationRepo ports.NotificationRepository) *NotificationService {
+	return &NotificationService{
+		notificationRepo: notificationRepo,
+	}
+}
+
+// Deliver guarda una notificación consumida en el inbox de su destinatario.
+func (s *NotificationService) Deliver(ctx context.Context, notification *domain.Notification) error {
+	if err := s.notificationRepo.Add(ctx, notification); err != nil {
+		return fmt.Errorf("error in calling notificationRepo.Add: %w", err)
+	}
+
+	return nil
+}
+
+// GetNotifications devuelve una página del inbox de un usuario junto con la cantidad de no leídas.
+// El cursor es la fecha de la última notificación devuelta, codificada.
+func (s *NotificationService) GetNotifications(ctx context.Context, userID, cursor string, limit int) (*domain.NotificationList, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("userID is required")
+	}
+
+	if limit < 0 {
+		return nil, fmt.Errorf("limit must be positive")
+	}
+
+	if limit == 0 || limit > maxNotificationListLimit {
+		limit = maxNotificationListLimit
+	}
+
+	before, err := decodeNotificationCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	// Pedimos un elemento de más para saber si hay una página siguiente
+	notifications, err := s.notificationRepo.List(ctx, userID, before, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("error in calling notificationRepo.List: %w", err)
+	}
+
+	unread, err := s.notificationRepo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error in calling notificationRepo.CountUnread: %w", err)
+	}
+
+	result := &domain.NotificationList{
+		UserID:        userID,
+		Notifications: notifications,
+		UnreadCount:   unread,
+	}
+
+	if len(notifications) > limit {
+		result.Notifications = notifications[:limit]
+		last := notifications[limit-1].CreatedAt.UnixMicro()
+		result.NextCursor = encodeIDCursor(strconv.FormatInt(last, 10))
+	}
+
+	return result, nil
+}
+
+// MarkRead marca como leídas las notificaciones pedidas de un usuario, o todas si ids está vacío.
+func (s *NotificationService) MarkRead(ctx context.Context, userID string, ids []string) error {
+	if userID == "" {
+		return fmt.Errorf("userID is required")
+	}
+
+	if err := s.notificationRepo.MarkRead(ctx, userID, ids); err != nil {
+		return fmt.Errorf("error in calling notificationRepo.MarkRead: %w", err)
+	}
+
+	return nil
+}
+
+// decodeNotificationCursor devuelve la fecha de la última notificación de la página anterior, o
+// cero si no hay cursor.
+func decodeNotificationCursor(cursor string) (time.Time, error) {
+	decoded, err := decodeIDCursor(cursor)
+	if err != nil || decoded == "" {
+		return time.Time{}, err
+	}
+
+	micros, err := strconv.ParseInt(decoded, 10, 64)
+	if err != nil {
+		return time.Time{}, domain.ErrInvalidCursor
+	}
+	return time.UnixMicro(micros), nil
+}
+
+// publishNotifications publica las notificaciones en el tópico de notificaciones con una sola
+// escritura, con el destinatario como key. Son best-effort: la acción que las genera ya se guardó,
+// así que si no se pueden publicar solo se loguea. No se notifica a un usuario de sus propias acciones.
+func publishNotifications(ctx context.Context, producer ports.BatchEventProducer, notifications ...*domain.Notification) {
+	if producer == nil {
+		return
+	}
+
+	events := make([]ports.Event, 0, len(notifications))
+	for _, notification := range notifications {
+		if notification.UserID == notification.ActorID {
+			continue
+		}
+
+		payload, err := json.Marshal(notification)
+		if err != nil {
+			log.Printf("Error serializing %s notification for user %s: %v", notification.Type, notification.UserID, err)
+			continue
+		}
+		events = append(events, ports.Event{Key: notification.UserID, Value: payload})
+	}
+
+	if len(events) == 0 {
+		return
+	}
+
+	if err := producer.PublishEvents(ctx, events...); err != nil {
+		log.Printf("Error publishing %d notifications: %v", len(events), err)
+	}
+}
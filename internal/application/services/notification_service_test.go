@@ -0,0 +1,141 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"ChallengeUALA/internal/application/ports"
+	"ChallengeUALA/internal/application/services"
+	"ChallengeUALA/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockNotificationRepository simula el inbox de notificaciones.
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationRepository) Add(ctx context.Context, notification *domain.Notification) error {
+	args := m.Called(ctx, notification)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) List(ctx context.Context, userID string, before time.Time, limit int) ([]*domain.Notification, error) {
+	args := m.Called(ctx, userID, before, limit)
+	notifications, _ := args.Get(0).([]*domain.Notification)
+	return notifications, args.Error(1)
+}
+
+func (m *MockNotificationRepository) CountUnread(ctx context.Context, userID string) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockNotificationRepository) MarkRead(ctx context.Context, userID string, ids []string) error {
+	args := m.Called(ctx, userID, ids)
+	return args.Error(0)
+}
+
+// MockEventProducer guarda las notificaciones publicadas por los servicios.
+type MockEventProducer struct {
+	mock.Mock
+}
+
+func (m *MockEventProducer) PublishEvents(ctx context.Context, events ...ports.Event) error {
+	args := m.Called(ctx, events)
+	return args.Error(0)
+}
+
+// published devuelve las notificaciones que se publicaron, en orden
+func (m *MockEventProducer) published(t *testing.T) []*domain.Notification {
+	var notifications []*domain.Notification
+	for _, call := range m.Calls {
+		for _, event := range call.Arguments.Get(1).([]ports.Event) {
+			var notification domain.Notification
+			assert.NoError(t, json.Unmarshal(event.Value, &notification))
+			// La key es el destinatario, así sus notificaciones van a la misma partición
+			assert.Equal(t, notification.UserID, event.Key)
+			notifications = append(notifications, &notification)
+		}
+	}
+	return notifications
+}
+
+func TestDeliver(t *testing.T) {
+	ctx := context.Background()
+	mockNotificationRepo := new(MockNotificationRepository)
+	service := services.NewNotificationService(mockNotificationRepo)
+
+	notification := domain.NewNotification(domain.NotificationFollow, "user1", "user2", "")
+	mockNotificationRepo.On("Add", ctx, notification).Return(errors.New("redis error")).Once()
+	mockNotificationRepo.On("Add", ctx, notification).Return(nil).Once()
+
+	assert.Error(t, service.Deliver(ctx, notification))
+	assert.NoError(t, service.Deliver(ctx, notification))
+	mockNotificationRepo.AssertExpectations(t)
+}
+
+func TestGetNotifications_Paginates(t *testing.T) {
+	ctx := context.Background()
+	mockNotificationRepo := new(MockNotificationRepository)
+	service := services.NewNotificationService(mockNotificationRepo)
+
+	now := time.Now().UTC()
+	notifications := []*domain.Notification{
+		{ID: "n3", UserID: "user1", CreatedAt: now},
+		{ID: "n2", UserID: "user1", CreatedAt: now.Add(-time.Minute)},
+		{ID: "n1", UserID: "user1", CreatedAt: now.Add(-2 * time.Minute)},
+	}
+	mockNotificationRepo.On("List", ctx, "user1", time.Time{}, 3).Return(notifications, nil)
+	mockNotificationRepo.On("CountUnread", ctx, "user1").Return(2, nil)
+
+	result, err := service.GetNotifications(ctx, "user1", "", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, notifications[:2], result.Notifications)
+	assert.Equal(t, 2, result.UnreadCount)
+	assert.NotEmpty(t, result.NextCursor)
+
+	// La página siguiente arranca antes de la última notificación devuelta
+	olderThanSecond := mock.MatchedBy(func(before time.Time) bool {
+		return before.Equal(notifications[1].CreatedAt.Truncate(time.Microsecond))
+	})
+	mockNotificationRepo.On("List", ctx, "user1", olderThanSecond, 3).Return(notifications[2:], nil)
+
+	result, err = service.GetNotifications(ctx, "user1", result.NextCursor, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, notifications[2:], result.Notifications)
+	assert.Empty(t, result.NextCursor)
+	mockNotificationRepo.AssertExpectations(t)
+}
+
+func TestGetNotifications_InvalidRequest(t *testing.T) {
+	service := services.NewNotificationService(new(MockNotificationRepository))
+
+	_, err := service.GetNotifications(context.Background(), "", "", 0)
+	assert.Error(t, err)
+
+	_, err = service.GetNotifications(context.Background(), "user1", "", -1)
+	assert.Error(t, err)
+
+	_, err = service.GetNotifications(context.Background(), "user1", "bm90LWEtZGF0ZQ", 0)
+	assert.ErrorIs(t, err, domain.ErrInvalidCursor)
+}
+
+func TestMarkRead(t *testing.T) {
+	ctx := context.Background()
+	mockNotificationRepo := new(MockNotificationRepository)
+	service := services.NewNotificationService(mockNotificationRepo)
+
+	mockNotificationRepo.On("MarkRead", ctx, "user1", []string{"n1"}).Return(nil)
+	mockNotificationRepo.On("MarkRead", ctx, "user1", []string(nil)).Return(nil)
+
+	assert.NoError(t, service.MarkRead(ctx, "user1", []string{"n1"}))
+	assert.NoError(t, service.MarkRead(ctx, "user1", nil))
+	assert.Error(t, service.MarkRead(ctx, "", nil))
+	mockNotificationRepo.AssertExpectations(t)
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"ChallengeUALA/internal/application/ports"
+	"ChallengeUALA/internal/domain"
+)
+
+// SearchService indexa los tweets que se consumen de Kafka y resuelve las búsquedas.
+// Los retweets no se indexan: el tweet original ya aparece en los resultados.
+type SearchService struct {
+	index    ports.SearchIndex
+	userRepo ports.UserRepository
+	likeRepo ports.LikeRepository
+}
+
+// NewSearchService crea una nueva instancia de SearchService
+func NewSearchService(index ports.SearchIndex, userRepo ports.UserRepository, likeRepo ports.LikeRepository) *SearchService {
+	return &SearchService{
+		index:    index,
+		userRepo: userRepo,
+		likeRepo: likeRepo,
+	}
+}
+
+// IndexTweet agrega un tweet nuevo al índice.
+func (s *SearchService) IndexTweet(ctx context.Context, tweet *domain.Tweet) error {
+	if tweet.IsRetweet() {
+		return nil
+	}
+
+	if err := s.index.Index(ctx, tweet); err != nil {
+		return fmt.Errorf("error in calling index.Index: %w", err)
+	}
+
+	return nil
+}
+
+// ReindexTweet reemplaza la versión indexada de un tweet editado.
+func (s *SearchService) ReindexTweet(ctx context.Context, tweet *domain.Tweet) error {
+	return s.IndexTweet(ctx, tweet)
+}
+
+// UnindexTweet saca un tweet borrado del índice.
+func (s *SearchService) UnindexTweet(ctx context.Context, tweet *domain.Tweet) error {
+	if err := s.index.Remove(ctx, tweet.ID); err != nil {
+		return fmt.Errorf("error in calling index.Remove: %w", err)
+	}
+
+	return nil
+}
+
+// Search devuelve una página de los tweets que cumplen la búsqueda (ver domain.ParseSearchQuery),
+// del más nuevo al más viejo y con el mismo cursor y límite que GetTimeline. El handle de from: se
+// resuelve al ID del usuario; si no existe ningún usuario con ese handle no hay resultados.
+func (s *SearchService) Search(ctx context.Context, rawQuery, cursor string, limit int) (*domain.TimelinePage, error) {
+	query, err := domain.ParseSearchQuery(rawQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, err = validatePageRequest(rawQuery, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if query.AuthorHandle != "" {
+		author, err := s.userRepo.GetByHandle(ctx, query.AuthorHandle)
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return domain.PageTimeline(nil, cursor, limit)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error in calling userRepo.GetByHandle: %w", err)
+		}
+		query.AuthorID = author.ID
+	}
+
+	tweets, err := s.index.Search(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("error in calling index.Search: %w", err)
+	}
+
+	page, err := domain.PageTimeline(tweets, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := hydrateLikeCounts(ctx, s.likeRepo, page.Tweets); err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
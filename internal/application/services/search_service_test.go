@@ -0,0 +1,112 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ChallengeUALA/internal/application/services"
+	"ChallengeUALA/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSearchIndex simula el índice de búsqueda.
+type MockSearchIndex struct {
+	mock.Mock
+}
+
+func (m *MockSearchIndex) Index(ctx context.Context, tweet *domain.Tweet) error {
+	args := m.Called(ctx, tweet)
+	return args.Error(0)
+}
+
+func (m *MockSearchIndex) Remove(ctx context.Context, tweetID string) error {
+	args := m.Called(ctx, tweetID)
+	return args.Error(0)
+}
+
+func (m *MockSearchIndex) Search(ctx context.Context, query *domain.SearchQuery) ([]*domain.Tweet, error) {
+	args := m.Called(ctx, query)
+	tweets, _ := args.Get(0).([]*domain.Tweet)
+	return tweets, args.Error(1)
+}
+
+func TestSearchService_IndexTweet(t *testing.T) {
+	ctx := context.Background()
+	mockIndex := new(MockSearchIndex)
+	service := services.NewSearchService(mockIndex, nil, nil)
+
+	original := domain.NewTweet("user1", "Hola mundo")
+	mockIndex.On("Index", ctx, original).Return(nil).Twice()
+	mockIndex.On("Remove", ctx, original.ID).Return(nil)
+
+	assert.NoError(t, service.IndexTweet(ctx, original))
+	assert.NoError(t, service.ReindexTweet(ctx, original))
+	assert.NoError(t, service.UnindexTweet(ctx, original))
+
+	// Los retweets no se indexan
+	assert.NoError(t, service.IndexTweet(ctx, domain.NewRetweet("user2", original)))
+	mockIndex.AssertExpectations(t)
+}
+
+func TestSearchService_Search(t *testing.T) {
+	ctx := context.Background()
+	mockIndex := new(MockSearchIndex)
+	mockUserRepo := new(MockUserRepository)
+	mockLikeRepo := new(MockLikeRepository)
+	service := services.NewSearchService(mockIndex, mockUserRepo, mockLikeRepo)
+
+	now := time.Now()
+	older := &domain.Tweet{ID: "1", UserID: "user1", Content: "hola mundo", CreatedAt: now.Add(-time.Hour)}
+	newer := &domain.Tweet{ID: "2", UserID: "user1", Content: "hola otra vez", CreatedAt: now}
+
+	// El handle de from: se resuelve al ID del autor
+	query := mock.MatchedBy(func(query *domain.SearchQuery) bool {
+		return assert.ObjectsAreEqual([]string{"hola"}, query.Terms) &&
+			assert.ObjectsAreEqual([][]string{{"otra", "vez"}}, query.Phrases) &&
+			query.AuthorID == "user1"
+	})
+	mockUserRepo.On("GetByHandle", ctx, "ana").Return(&domain.User{ID: "user1", Handle: "ana"}, nil)
+	mockIndex.On("Search", ctx, query).Return([]*domain.Tweet{older, newer}, nil)
+	mockLikeRepo.On("CountLikes", ctx, []string{"2"}).Return(map[string]int{"2": 1}, nil)
+
+	page, err := service.Search(ctx, `hola "otra vez" from:@ana`, "", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []*domain.Tweet{newer}, page.Tweets)
+	assert.Equal(t, 1, page.Tweets[0].LikeCount)
+	assert.Equal(t, domain.EncodeTimelineCursor(newer), page.NextCursor)
+	mockLikeRepo.AssertExpectations(t)
+}
+
+func TestSearchService_UnknownAuthor(t *testing.T) {
+	ctx := context.Background()
+	mockIndex := new(MockSearchIndex)
+	mockUserRepo := new(MockUserRepository)
+	service := services.NewSearchService(mockIndex, mockUserRepo, nil)
+
+	mockUserRepo.On("GetByHandle", ctx, "nadie").Return(nil, domain.ErrUserNotFound)
+
+	// Si no existe el autor no hay resultados, sin consultar el índice
+	page, err := service.Search(ctx, "hola from:nadie", "", 0)
+	assert.NoError(t, err)
+	assert.Empty(t, page.Tweets)
+	assert.Empty(t, page.NextCursor)
+	mockIndex.AssertNotCalled(t, "Search", mock.Anything, mock.Anything)
+}
+
+func TestSearchService_InvalidQuery(t *testing.T) {
+	ctx := context.Background()
+	mockIndex := new(MockSearchIndex)
+	service := services.NewSearchService(mockIndex, nil, nil)
+
+	for _, rawQuery := range []string{"", "   ", "since:2024-01-01", "from:", "hola since:ayer", "hola since:2024-02-01 until:2024-01-01"} {
+		_, err := service.Search(ctx, rawQuery, "", 0)
+		assert.ErrorIs(t, err, domain.ErrInvalidSearchQuery, rawQuery)
+	}
+
+	_, err := service.Search(ctx, "hola", "bad", 0)
+	assert.ErrorIs(t, err, domain.ErrInvalidCursor)
+	mockIndex.AssertNotCalled(t, "Search", mock.Anything, mock.Anything)
+}
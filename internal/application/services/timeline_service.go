@@ -2,7 +2,6 @@ package services
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
 
@@ -10,65 +9,285 @@ import (
 	"ChallengeUALA/internal/domain"
 )
 
+// maxTimelineLimit es la cantidad máxima de tweets que se devuelven por página
+const maxTimelineLimit = 100
+
 type TimelineService struct {
-	tweetRepo  ports.TweetRepository
-	followRepo ports.FollowRepository
-	redisRepo  ports.RedisRepository
-	dlq        ports.DeadLetterQueue
-	logger     *log.Logger
+	tweetRepo          ports.TweetRepository
+	followRepo         ports.FollowRepository
+	redisRepo          ports.RedisRepository
+	likeRepo           ports.LikeRepository
+	celebrityThreshold int
+	logger             *log.Logger
 }
 
+// NewTimelineService crea una nueva instancia de TimelineService. Los autores con más de
+// celebrityThreshold seguidores no se distribuyen por fan-out (0 lo deshabilita).
 func NewTimelineService(
 	tweetRepo ports.TweetRepository,
 	followRepo ports.FollowRepository,
 	redisRepo ports.RedisRepository,
-	dlq ports.DeadLetterQueue,
+	likeRepo ports.LikeRepository,
+	celebrityThreshold int,
 	logger *log.Logger,
 ) *TimelineService {
 	return &TimelineService{
-		tweetRepo:  tweetRepo,
-		followRepo: followRepo,
-		redisRepo:  redisRepo,
-		dlq:        dlq,
-		logger:     logger,
+		tweetRepo:          tweetRepo,
+		followRepo:         followRepo,
+		redisRepo:          redisRepo,
+		likeRepo:           likeRepo,
+		celebrityThreshold: celebrityThreshold,
+		logger:             logger,
 	}
 }
 
+// UpdateTimeline agrega el tweet a los timelines de los seguidores del autor. Si el autor supera
+// el umbral de seguidores, el tweet solo se guarda en sus tweets recientes y se mezcla al leer.
+// Los retweets no se agregan a los timelines que ya muestran el tweet original.
 func (s *TimelineService) UpdateTimeline(ctx context.Context, tweet *domain.Tweet) error {
 
-	followers, err := s.followRepo.GetFollowers(ctx, tweet.UserID)
+	// Para las celebridades alcanza con contar, no hace falta traer todos los seguidores
+	isCelebrity, err := s.isCelebrity(ctx, tweet.UserID)
 	if err != nil {
-		payload, err := json.Marshal(tweet)
-		if err != nil {
-			return fmt.Errorf("error getting followers: %w", err)
+		return err
+	}
+
+	if isCelebrity {
+		if err := s.redisRepo.AddToCelebrityTweets(ctx, tweet); err != nil {
+			return fmt.Errorf("error adding tweet to celebrity tweets: %w", err)
 		}
+		return nil
+	}
+
+	followers, err := s.followRepo.GetFollowers(ctx, tweet.UserID)
+	if err != nil {
+		return fmt.Errorf("error getting followers: %w", err)
+	}
+
+	if len(followers) == 0 {
+		return nil
+	}
+
+	addToTimelines := s.redisRepo.AddToTimelines
+	if tweet.IsRetweet() {
+		addToTimelines = s.redisRepo.AddRetweetToTimelines
+	}
+
+	if err := addToTimelines(ctx, followers, tweet); err != nil {
+		return fmt.Errorf("error adding tweet to timeline: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveTweet saca un tweet borrado de los timelines de los seguidores del autor y de sus
+// tweets recientes. Se limpian los dos porque el autor pudo haber cruzado el umbral de celebridad
+// después de publicarlo, en cualquiera de los dos sentidos.
+func (s *TimelineService) RemoveTweet(ctx context.Context, tweet *domain.Tweet) error {
+	return s.rewriteTweet(ctx, tweet,
+		func() error {
+			return s.redisRepo.RemoveFromCelebrityTweets(ctx, tweet.UserID, tweet.ID)
+		},
+		func(followers []string) error {
+			return s.redisRepo.RemoveFromTimelines(ctx, followers, tweet.ID)
+		},
+	)
+}
+
+// ReplaceTweet reemplaza la versión de un tweet editado en los timelines de los seguidores del
+// autor y en sus tweets recientes, en vez de agregarlo como un tweet nuevo. Como al borrar, se
+// actualizan los dos sin importar si el autor es una celebridad ahora.
+func (s *TimelineService) ReplaceTweet(ctx context.Context, tweet *domain.Tweet) error {
+	return s.rewriteTweet(ctx, tweet,
+		func() error {
+			return s.redisRepo.ReplaceInCelebrityTweets(ctx, tweet)
+		},
+		func(followers []string) error {
+			return s.redisRepo.ReplaceInTimelines(ctx, followers, tweet)
+		},
+	)
+}
 
-		_ = s.dlq.StoreEvent(ctx, "timeline_events", payload)
+// rewriteTweet aplica un cambio sobre un tweet ya distribuido: primero en los tweets recientes del
+// autor y después en los timelines de sus seguidores. No se mira si el autor es una celebridad: que
+// lo sea ahora no quiere decir que lo fuera al publicar el tweet, y los timelines que no lo tienen
+// quedan igual. Si algo falla se devuelve el error y el FanoutConsumer reintenta el evento.
+func (s *TimelineService) rewriteTweet(
+	ctx context.Context,
+	tweet *domain.Tweet,
+	celebrityTweets func() error,
+	timelines func(followers []string) error,
+) error {
+	if err := celebrityTweets(); err != nil {
+		return fmt.Errorf("error updating celebrity tweets: %w", err)
+	}
 
+	followers, err := s.followRepo.GetFollowers(ctx, tweet.UserID)
+	if err != nil {
 		return fmt.Errorf("error getting followers: %w", err)
 	}
 
-	for _, followerID := range followers {
-		if err := s.redisRepo.AddToTimeline(ctx, followerID, tweet); err != nil {
-			payload, err := json.Marshal(tweet)
-			_ = s.dlq.StoreEvent(ctx, "timeline_events", payload)
-			return fmt.Errorf("error adding tweet to timeline: %w", err)
-		}
+	if len(followers) == 0 {
+		return nil
+	}
+
+	if err := timelines(followers); err != nil {
+		return fmt.Errorf("error updating timelines: %w", err)
 	}
 
 	return nil
 }
 
-// GetTimeline obtiene los últimos tweets del timeline de un usuario.
-func (s *TimelineService) GetTimeline(ctx context.Context, userID string) ([]*domain.Tweet, error) {
-	if userID == "" {
-		return nil, fmt.Errorf("userID is required")
+// isCelebrity indica si el autor supera el umbral de seguidores para el fan-out
+func (s *TimelineService) isCelebrity(ctx context.Context, userID string) (bool, error) {
+	if s.celebrityThreshold <= 0 {
+		return false, nil
+	}
+
+	count, err := s.followRepo.CountFollowers(ctx, userID)
+	if err != nil {
+		return false, fmt.Errorf("error counting followers: %w", err)
+	}
+
+	return count > s.celebrityThreshold, nil
+}
+
+// GetTimeline obtiene una página del timeline de un usuario a partir de un cursor.
+// Si limit es 0 o supera el máximo se usa maxTimelineLimit.
+func (s *TimelineService) GetTimeline(ctx context.Context, userID, cursor string, limit int) (*domain.TimelinePage, error) {
+	limit, err := validatePageRequest(userID, cursor, limit)
+	if err != nil {
+		return nil, err
 	}
 
-	tweets, err := s.redisRepo.GetTimeline(ctx, userID)
+	page, err := s.redisRepo.GetTimeline(ctx, userID, cursor, limit)
 	if err != nil {
 		return nil, fmt.Errorf("error in calling redisRepo.GetTimeLine: %w", err)
 	}
 
-	return tweets, nil
+	celebrityPages, err := s.getFollowedCelebrityTweets(ctx, userID, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(celebrityPages) > 0 {
+		page = mergeTimelinePages(append(celebrityPages, page), limit)
+	}
+
+	if err := hydrateLikeCounts(ctx, s.likeRepo, page.Tweets); err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// GetUserTweets obtiene una página de los tweets publicados por un usuario, del más nuevo al más viejo,
+// con el mismo cursor y límite que GetTimeline.
+func (s *TimelineService) GetUserTweets(ctx context.Context, userID, cursor string, limit int) (*domain.TimelinePage, error) {
+	limit, err := validatePageRequest(userID, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := s.tweetRepo.ListByAuthor(ctx, userID, cursor, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error in calling tweetRepo.ListByAuthor: %w", err)
+	}
+
+	if err := hydrateLikeCounts(ctx, s.likeRepo, page.Tweets); err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// validatePageRequest valida el pedido de una página y devuelve el límite a usar.
+// Si limit es 0 o supera el máximo se usa maxTimelineLimit.
+func validatePageRequest(userID, cursor string, limit int) (int, error) {
+	if userID == "" {
+		return 0, fmt.Errorf("userID is required")
+	}
+
+	if limit < 0 {
+		return 0, fmt.Errorf("limit must be positive")
+	}
+
+	if limit == 0 || limit > maxTimelineLimit {
+		limit = maxTimelineLimit
+	}
+
+	if cursor != "" {
+		if _, _, err := domain.DecodeTimelineCursor(cursor); err != nil {
+			return 0, err
+		}
+	}
+
+	return limit, nil
+}
+
+// getFollowedCelebrityTweets devuelve una página de tweets de cada celebridad que sigue el usuario.
+// Se parte de los usuarios que sigue, así que el costo depende de a cuántos sigue y no de cuántas
+// celebridades hay.
+func (s *TimelineService) getFollowedCelebrityTweets(ctx context.Context, userID, cursor string, limit int) ([]*domain.TimelinePage, error) {
+	following, err := s.followRepo.GetFollowing(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error in calling followRepo.GetFollowing: %w", err)
+	}
+
+	if len(following) == 0 {
+		return nil, nil
+	}
+
+	celebrities, err := s.redisRepo.GetCelebrities(ctx, following)
+	if err != nil {
+		return nil, fmt.Errorf("error in calling redisRepo.GetCelebrities: %w", err)
+	}
+
+	var pages []*domain.TimelinePage
+	for _, celebrityID := range celebrities {
+		page, err := s.redisRepo.GetCelebrityTweets(ctx, celebrityID, cursor, limit)
+		if err != nil {
+			return nil, fmt.Errorf("error in calling redisRepo.GetCelebrityTweets: %w", err)
+		}
+		pages = append(pages, page)
+	}
+
+	return pages, nil
+}
+
+// mergeTimelinePages mezcla páginas pedidas con el mismo cursor y se queda con los primeros limit tweets.
+// Hay página siguiente si sobraron tweets o si alguna de las páginas tenía más. Si un tweet y sus
+// retweets aparecen en más de una página, se muestra solo el más nuevo.
+func mergeTimelinePages(pages []*domain.TimelinePage, limit int) *domain.TimelinePage {
+	all := make([]*domain.Tweet, 0)
+	hasMore := false
+
+	for _, page := range pages {
+		hasMore = hasMore || page.NextCursor != ""
+		all = append(all, page.Tweets...)
+	}
+
+	domain.SortTimeline(all)
+
+	seen := make(map[string]bool)
+	tweets := make([]*domain.Tweet, 0, len(all))
+	for _, tweet := range all {
+		if seen[tweet.ContentID()] {
+			continue
+		}
+		seen[tweet.ContentID()] = true
+		tweets = append(tweets, tweet)
+	}
+
+	if len(tweets) > limit {
+		tweets = tweets[:limit]
+		hasMore = true
+	}
+
+	merged := &domain.TimelinePage{Tweets: tweets}
+	if hasMore && len(tweets) > 0 {
+		merged.NextCursor = domain.EncodeTimelineCursor(tweets[len(tweets)-1])
+	}
+
+	return merged
 }
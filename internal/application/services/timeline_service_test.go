@@ -2,10 +2,10 @@ package services_test
 
 import (
 	"ChallengeUALA/internal/application/services"
-	"ChallengeUALA/internal/infrastructure/dlq"
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"ChallengeUALA/internal/domain"
 
@@ -16,13 +16,17 @@ import (
 // Mocks
 type MockFollowRepository struct{ mock.Mock }
 type MockRedisRepository struct{ mock.Mock }
-type MockDLQ struct{ mock.Mock }
 
 func (m *MockFollowRepository) Follow(ctx context.Context, followerID string, followedID string) error {
 	args := m.Called(ctx, followerID, followedID)
 	return args.Error(0)
 }
 
+func (m *MockFollowRepository) Unfollow(ctx context.Context, followerID string, followedID string) error {
+	args := m.Called(ctx, followerID, followedID)
+	return args.Error(0)
+}
+
 func (m *MockFollowRepository) IsFollowing(ctx context.Context, followerID string, followedID string) (bool, error) {
 	args := m.Called(ctx, followerID, followedID)
 	return args.Bool(0), args.Error(1)
@@ -33,24 +37,89 @@ func (m *MockFollowRepository) GetFollowers(ctx context.Context, userID string)
 	return args.Get(0).([]string), args.Error(1)
 }
 
+func (m *MockFollowRepository) GetFollowing(ctx context.Context, userID string) ([]string, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockFollowRepository) CountFollowers(ctx context.Context, userID string) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockFollowRepository) CountFollowing(ctx context.Context, userID string) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockFollowRepository) ListFollowers(ctx context.Context, userID string, afterID string, limit int) ([]string, error) {
+	args := m.Called(ctx, userID, afterID, limit)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockFollowRepository) ListFollowing(ctx context.Context, userID string, afterID string, limit int) ([]string, error) {
+	args := m.Called(ctx, userID, afterID, limit)
+	return args.Get(0).([]string), args.Error(1)
+}
+
 func (m *MockRedisRepository) AddToTimeline(ctx context.Context, userID string, tweet *domain.Tweet) error {
 	args := m.Called(ctx, userID, tweet)
 	return args.Error(0)
 }
 
-func (m *MockRedisRepository) GetTimeline(ctx context.Context, userID string) ([]*domain.Tweet, error) {
-	args := m.Called(ctx, userID)
-	return args.Get(0).([]*domain.Tweet), args.Error(1)
+func (m *MockRedisRepository) AddToTimelines(ctx context.Context, userIDs []string, tweet *domain.Tweet) error {
+	args := m.Called(ctx, userIDs, tweet)
+	return args.Error(0)
+}
+
+func (m *MockRedisRepository) AddRetweetToTimelines(ctx context.Context, userIDs []string, retweet *domain.Tweet) error {
+	args := m.Called(ctx, userIDs, retweet)
+	return args.Error(0)
+}
+
+func (m *MockRedisRepository) GetTimeline(ctx context.Context, userID string, cursor string, limit int) (*domain.TimelinePage, error) {
+	args := m.Called(ctx, userID, cursor, limit)
+	return args.Get(0).(*domain.TimelinePage), args.Error(1)
+}
+
+func (m *MockRedisRepository) RemoveAuthorFromTimeline(ctx context.Context, userID string, authorID string) error {
+	args := m.Called(ctx, userID, authorID)
+	return args.Error(0)
+}
+
+func (m *MockRedisRepository) RemoveFromTimelines(ctx context.Context, userIDs []string, tweetID string) error {
+	args := m.Called(ctx, userIDs, tweetID)
+	return args.Error(0)
+}
+
+func (m *MockRedisRepository) RemoveFromCelebrityTweets(ctx context.Context, authorID string, tweetID string) error {
+	args := m.Called(ctx, authorID, tweetID)
+	return args.Error(0)
+}
+
+func (m *MockRedisRepository) ReplaceInTimelines(ctx context.Context, userIDs []string, tweet *domain.Tweet) error {
+	args := m.Called(ctx, userIDs, tweet)
+	return args.Error(0)
 }
 
-func (m *MockDLQ) StoreEvent(ctx context.Context, queue string, payload []byte) error {
-	args := m.Called(ctx, queue, payload)
+func (m *MockRedisRepository) ReplaceInCelebrityTweets(ctx context.Context, tweet *domain.Tweet) error {
+	args := m.Called(ctx, tweet)
 	return args.Error(0)
 }
 
-func (m *MockDLQ) RetrieveEvents(ctx context.Context) ([]dlq.Message, error) {
-	args := m.Called(ctx)
-	return args.Get(0).([]dlq.Message), args.Error(1)
+func (m *MockRedisRepository) AddToCelebrityTweets(ctx context.Context, tweet *domain.Tweet) error {
+	args := m.Called(ctx, tweet)
+	return args.Error(0)
+}
+
+func (m *MockRedisRepository) GetCelebrities(ctx context.Context, userIDs []string) ([]string, error) {
+	args := m.Called(ctx, userIDs)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockRedisRepository) GetCelebrityTweets(ctx context.Context, authorID string, cursor string, limit int) (*domain.TimelinePage, error) {
+	args := m.Called(ctx, authorID, cursor, limit)
+	return args.Get(0).(*domain.TimelinePage), args.Error(1)
 }
 
 // 🔹 Test UpdateTimeline (success)
@@ -58,15 +127,13 @@ func TestUpdateTimeline_Success(t *testing.T) {
 	ctx := context.Background()
 	mockFollowRepo := new(MockFollowRepository)
 	mockRedisRepo := new(MockRedisRepository)
-	mockDLQ := new(MockDLQ)
 
 	tweet := &domain.Tweet{UserID: "user123", Content: "Hello world"}
 
 	mockFollowRepo.On("GetFollowers", ctx, "user123").Return([]string{"follower1", "follower2"}, nil)
-	mockRedisRepo.On("AddToTimeline", ctx, "follower1", tweet).Return(nil)
-	mockRedisRepo.On("AddToTimeline", ctx, "follower2", tweet).Return(nil)
+	mockRedisRepo.On("AddToTimelines", ctx, []string{"follower1", "follower2"}, tweet).Return(nil)
 
-	err := services.NewTimelineService(nil, mockFollowRepo, mockRedisRepo, mockDLQ, nil).
+	err := services.NewTimelineService(nil, mockFollowRepo, mockRedisRepo, nil, 0, nil).
 		UpdateTimeline(ctx, tweet)
 
 	assert.NoError(t, err)
@@ -74,22 +141,55 @@ func TestUpdateTimeline_Success(t *testing.T) {
 	mockRedisRepo.AssertExpectations(t)
 }
 
+// 🔹 Test UpdateTimeline - los retweets no se repiten en los timelines que ya tienen el original
+func TestUpdateTimeline_Retweet(t *testing.T) {
+	ctx := context.Background()
+	mockFollowRepo := new(MockFollowRepository)
+	mockRedisRepo := new(MockRedisRepository)
+
+	retweet := &domain.Tweet{ID: "2", UserID: "user123", Kind: domain.TweetKindRetweet, ReferencedTweetID: "1"}
+
+	mockFollowRepo.On("GetFollowers", ctx, "user123").Return([]string{"follower1"}, nil)
+	mockRedisRepo.On("AddRetweetToTimelines", ctx, []string{"follower1"}, retweet).Return(nil)
+
+	err := services.NewTimelineService(nil, mockFollowRepo, mockRedisRepo, nil, 0, nil).
+		UpdateTimeline(ctx, retweet)
+
+	assert.NoError(t, err)
+	mockRedisRepo.AssertExpectations(t)
+	mockRedisRepo.AssertNotCalled(t, "AddToTimelines", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// 🔹 Test UpdateTimeline - autor sin seguidores
+func TestUpdateTimeline_NoFollowers(t *testing.T) {
+	ctx := context.Background()
+	mockFollowRepo := new(MockFollowRepository)
+	mockRedisRepo := new(MockRedisRepository)
+
+	tweet := &domain.Tweet{UserID: "user123", Content: "Hello world"}
+
+	mockFollowRepo.On("GetFollowers", ctx, "user123").Return([]string{}, nil)
+
+	err := services.NewTimelineService(nil, mockFollowRepo, mockRedisRepo, nil, 0, nil).
+		UpdateTimeline(ctx, tweet)
+
+	assert.NoError(t, err)
+	mockRedisRepo.AssertNotCalled(t, "AddToTimelines", mock.Anything, mock.Anything, mock.Anything)
+}
+
 // 🔹 Test UpdateTimeline - GetFollowers fails
 func TestUpdateTimeline_GetFollowersFails(t *testing.T) {
 	ctx := context.Background()
 	mockFollowRepo := new(MockFollowRepository)
 	mockRedisRepo := new(MockRedisRepository)
-	mockDLQ := new(MockDLQ)
 
-	service := services.NewTimelineService(nil, mockFollowRepo, mockRedisRepo, mockDLQ, nil)
+	service := services.NewTimelineService(nil, mockFollowRepo, mockRedisRepo, nil, 0, nil)
 
 	tweet := &domain.Tweet{UserID: "user123", Content: "Hello world"}
 	mockFollowRepo.On("GetFollowers", ctx, "user123").Return([]string{}, errors.New("DB error"))
-	mockDLQ.On("StoreEvent", ctx, "timeline_events", mock.Anything).Return(nil)
 
 	err := service.UpdateTimeline(ctx, tweet)
 	assert.Error(t, err)
-	mockDLQ.AssertExpectations(t)
 }
 
 // 🔹 Test UpdateTimeline - AddToTimeline fails
@@ -97,46 +197,154 @@ func TestUpdateTimeline_AddToTimelineFails(t *testing.T) {
 	ctx := context.Background()
 	mockFollowRepo := new(MockFollowRepository)
 	mockRedisRepo := new(MockRedisRepository)
-	mockDLQ := new(MockDLQ)
 
-	service := services.NewTimelineService(nil, mockFollowRepo, mockRedisRepo, mockDLQ, nil)
+	service := services.NewTimelineService(nil, mockFollowRepo, mockRedisRepo, nil, 0, nil)
 
 	tweet := &domain.Tweet{UserID: "user123", Content: "Hello world"}
 
 	mockFollowRepo.On("GetFollowers", ctx, "user123").Return([]string{"follower1"}, nil)
-	mockRedisRepo.On("AddToTimeline", ctx, "follower1", tweet).Return(errors.New("Redis error"))
-	mockDLQ.On("StoreEvent", ctx, "timeline_events", mock.Anything).Return(nil)
+	mockRedisRepo.On("AddToTimelines", ctx, []string{"follower1"}, tweet).Return(errors.New("Redis error"))
 
 	err := service.UpdateTimeline(ctx, tweet)
 	assert.Error(t, err)
-	mockDLQ.AssertExpectations(t)
 }
 
-// 🔹 Test GetTimeline - Success
-func TestGetTimeline_Success(t *testing.T) {
+// 🔹 Test RemoveTweet (success)
+func TestRemoveTweet_Success(t *testing.T) {
 	ctx := context.Background()
+	mockFollowRepo := new(MockFollowRepository)
 	mockRedisRepo := new(MockRedisRepository)
-	service := services.NewTimelineService(nil, nil, mockRedisRepo, nil, nil)
 
-	tweets := []*domain.Tweet{{UserID: "user123", Content: "Hello world"}}
-	mockRedisRepo.On("GetTimeline", ctx, "user123").Return(tweets, nil)
+	tweet := &domain.Tweet{ID: "tweet1", UserID: "user123", Content: "Hello world"}
+
+	mockRedisRepo.On("RemoveFromCelebrityTweets", ctx, "user123", "tweet1").Return(nil)
+	mockFollowRepo.On("GetFollowers", ctx, "user123").Return([]string{"follower1", "follower2"}, nil)
+	mockRedisRepo.On("RemoveFromTimelines", ctx, []string{"follower1", "follower2"}, "tweet1").Return(nil)
+
+	err := services.NewTimelineService(nil, mockFollowRepo, mockRedisRepo, nil, 0, nil).
+		RemoveTweet(ctx, tweet)
 
-	result, err := service.GetTimeline(ctx, "user123")
 	assert.NoError(t, err)
-	assert.Equal(t, tweets, result)
+	mockFollowRepo.AssertExpectations(t)
+	mockRedisRepo.AssertExpectations(t)
+}
 
+// 🔹 Test RemoveTweet - a las celebridades también se les limpian los timelines, por los tweets
+// que se distribuyeron antes de que cruzaran el umbral
+func TestRemoveTweet_Celebrity(t *testing.T) {
+	ctx := context.Background()
+	mockFollowRepo := new(MockFollowRepository)
+	mockRedisRepo := new(MockRedisRepository)
+
+	tweet := &domain.Tweet{ID: "tweet1", UserID: "celebrity", Content: "Hello world"}
+
+	mockRedisRepo.On("RemoveFromCelebrityTweets", ctx, "celebrity", "tweet1").Return(nil)
+	mockFollowRepo.On("GetFollowers", ctx, "celebrity").Return([]string{"follower1", "follower2", "follower3"}, nil)
+	mockRedisRepo.On("RemoveFromTimelines", ctx, []string{"follower1", "follower2", "follower3"}, "tweet1").Return(nil)
+
+	err := services.NewTimelineService(nil, mockFollowRepo, mockRedisRepo, nil, 2, nil).
+		RemoveTweet(ctx, tweet)
+
+	assert.NoError(t, err)
 	mockRedisRepo.AssertExpectations(t)
+	mockFollowRepo.AssertExpectations(t)
+}
+
+// 🔹 Test RemoveTweet - RemoveFromTimelines fails
+func TestRemoveTweet_RemoveFromTimelinesFails(t *testing.T) {
+	ctx := context.Background()
+	mockFollowRepo := new(MockFollowRepository)
+	mockRedisRepo := new(MockRedisRepository)
+
+	tweet := &domain.Tweet{ID: "tweet1", UserID: "user123", Content: "Hello world"}
+
+	mockRedisRepo.On("RemoveFromCelebrityTweets", ctx, "user123", "tweet1").Return(nil)
+	mockFollowRepo.On("GetFollowers", ctx, "user123").Return([]string{"follower1"}, nil)
+	mockRedisRepo.On("RemoveFromTimelines", ctx, []string{"follower1"}, "tweet1").Return(errors.New("Redis error"))
+
+	err := services.NewTimelineService(nil, mockFollowRepo, mockRedisRepo, nil, 0, nil).
+		RemoveTweet(ctx, tweet)
+
+	assert.Error(t, err)
+}
+
+// 🔹 Test ReplaceTweet (success)
+func TestReplaceTweet_Success(t *testing.T) {
+	ctx := context.Background()
+	mockFollowRepo := new(MockFollowRepository)
+	mockRedisRepo := new(MockRedisRepository)
+
+	tweet := &domain.Tweet{ID: "tweet1", UserID: "user123", Content: "Hello world"}
+
+	mockRedisRepo.On("ReplaceInCelebrityTweets", ctx, tweet).Return(nil)
+	mockFollowRepo.On("GetFollowers", ctx, "user123").Return([]string{"follower1"}, nil)
+	mockRedisRepo.On("ReplaceInTimelines", ctx, []string{"follower1"}, tweet).Return(nil)
+
+	err := services.NewTimelineService(nil, mockFollowRepo, mockRedisRepo, nil, 0, nil).
+		ReplaceTweet(ctx, tweet)
+
+	assert.NoError(t, err)
+	mockFollowRepo.AssertExpectations(t)
+	mockRedisRepo.AssertExpectations(t)
+	mockRedisRepo.AssertNotCalled(t, "AddToTimelines", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// 🔹 Test ReplaceTweet - la edición de una celebridad llega a los timelines que ya tenían el tweet
+func TestReplaceTweet_Celebrity(t *testing.T) {
+	ctx := context.Background()
+	mockFollowRepo := new(MockFollowRepository)
+	mockRedisRepo := new(MockRedisRepository)
+
+	tweet := &domain.Tweet{ID: "tweet1", UserID: "celebrity", Content: "Hello world (edited)"}
+
+	mockRedisRepo.On("ReplaceInCelebrityTweets", ctx, tweet).Return(nil)
+	mockFollowRepo.On("GetFollowers", ctx, "celebrity").Return([]string{"follower1", "follower2", "follower3"}, nil)
+	mockRedisRepo.On("ReplaceInTimelines", ctx, []string{"follower1", "follower2", "follower3"}, tweet).Return(nil)
+
+	err := services.NewTimelineService(nil, mockFollowRepo, mockRedisRepo, nil, 2, nil).
+		ReplaceTweet(ctx, tweet)
+
+	assert.NoError(t, err)
+	mockFollowRepo.AssertExpectations(t)
+	mockRedisRepo.AssertExpectations(t)
+}
+
+// 🔹 Test GetTimeline - Success
+func TestGetTimeline_Success(t *testing.T) {
+	ctx := context.Background()
+	mockFollowRepo := new(MockFollowRepository)
+	mockRedisRepo := new(MockRedisRepository)
+	mockLikeRepo := new(MockLikeRepository)
+	service := services.NewTimelineService(nil, mockFollowRepo, mockRedisRepo, mockLikeRepo, 0, nil)
+
+	cursor := domain.EncodeTimelineCursor(&domain.Tweet{ID: "tweet1", CreatedAt: time.Now()})
+	page := &domain.TimelinePage{
+		Tweets:     []*domain.Tweet{{ID: "tweet2", UserID: "user123", Content: "Hello world"}},
+		NextCursor: "cursor2",
+	}
+	mockRedisRepo.On("GetTimeline", ctx, "user123", cursor, 10).Return(page, nil)
+	mockFollowRepo.On("GetFollowing", ctx, "user123").Return([]string{"friend"}, nil)
+	mockRedisRepo.On("GetCelebrities", ctx, []string{"friend"}).Return([]string{}, nil)
+	mockLikeRepo.On("CountLikes", ctx, []string{"tweet2"}).Return(map[string]int{"tweet2": 3}, nil)
+
+	result, err := service.GetTimeline(ctx, "user123", cursor, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, page, result)
+	assert.Equal(t, 3, result.Tweets[0].LikeCount)
+
+	mockRedisRepo.AssertExpectations(t)
+	mockLikeRepo.AssertExpectations(t)
 }
 
 // 🔹 Test GetTimeline - Redis failure
 func TestGetTimeline_RedisFails(t *testing.T) {
 	ctx := context.Background()
 	mockRedisRepo := new(MockRedisRepository)
-	service := services.NewTimelineService(nil, nil, mockRedisRepo, nil, nil)
+	service := services.NewTimelineService(nil, nil, mockRedisRepo, nil, 0, nil)
 
-	mockRedisRepo.On("GetTimeline", ctx, "user123").Return([]*domain.Tweet{}, errors.New("Redis error"))
+	mockRedisRepo.On("GetTimeline", ctx, "user123", "", 100).Return(&domain.TimelinePage{}, errors.New("Redis error"))
 
-	result, err := service.GetTimeline(ctx, "user123")
+	result, err := service.GetTimeline(ctx, "user123", "", 0)
 	assert.Error(t, err)
 	assert.Nil(t, result)
 
@@ -146,9 +354,178 @@ func TestGetTimeline_RedisFails(t *testing.T) {
 // 🔹 Test GetTimeline - UserID empty
 func TestGetTimeline_UserIDEmpty(t *testing.T) {
 	ctx := context.Background()
-	service := services.NewTimelineService(nil, nil, nil, nil, nil)
+	service := services.NewTimelineService(nil, nil, nil, nil, 0, nil)
+
+	result, err := service.GetTimeline(ctx, "", "", 0)
+	assert.Error(t, err)
+	assert.Nil(t, result)
+}
+
+// 🔹 Test GetTimeline - limit mayor al máximo
+func TestGetTimeline_LimitClamped(t *testing.T) {
+	ctx := context.Background()
+	mockFollowRepo := new(MockFollowRepository)
+	mockRedisRepo := new(MockRedisRepository)
+	service := services.NewTimelineService(nil, mockFollowRepo, mockRedisRepo, nil, 0, nil)
+
+	page := &domain.TimelinePage{Tweets: []*domain.Tweet{}}
+	mockRedisRepo.On("GetTimeline", ctx, "user123", "", 100).Return(page, nil)
+	// Sin usuarios seguidos no hace falta buscar celebridades
+	mockFollowRepo.On("GetFollowing", ctx, "user123").Return([]string{}, nil)
+
+	result, err := service.GetTimeline(ctx, "user123", "", 1000)
+	assert.NoError(t, err)
+	assert.Equal(t, page, result)
+
+	mockRedisRepo.AssertExpectations(t)
+}
+
+// 🔹 Test GetTimeline - limit negativo
+func TestGetTimeline_NegativeLimit(t *testing.T) {
+	ctx := context.Background()
+	service := services.NewTimelineService(nil, nil, nil, nil, 0, nil)
 
-	result, err := service.GetTimeline(ctx, "")
+	result, err := service.GetTimeline(ctx, "user123", "", -1)
 	assert.Error(t, err)
 	assert.Nil(t, result)
 }
+
+// 🔹 Test GetTimeline - cursor inválido
+func TestGetTimeline_InvalidCursor(t *testing.T) {
+	ctx := context.Background()
+	mockRedisRepo := new(MockRedisRepository)
+	service := services.NewTimelineService(nil, nil, mockRedisRepo, nil, 0, nil)
+
+	result, err := service.GetTimeline(ctx, "user123", "bad", 0)
+	assert.ErrorIs(t, err, domain.ErrInvalidCursor)
+	assert.Nil(t, result)
+	mockRedisRepo.AssertNotCalled(t, "GetTimeline", ctx, "user123", "bad", 100)
+}
+
+// 🔹 Test UpdateTimeline - autor con más seguidores que el umbral
+func TestUpdateTimeline_CelebritySkipsFanout(t *testing.T) {
+	ctx := context.Background()
+	mockFollowRepo := new(MockFollowRepository)
+	mockRedisRepo := new(MockRedisRepository)
+
+	tweet := &domain.Tweet{UserID: "celebrity", Content: "Hello fans"}
+
+	mockFollowRepo.On("CountFollowers", ctx, "celebrity").Return(3, nil)
+	mockRedisRepo.On("AddToCelebrityTweets", ctx, tweet).Return(nil)
+
+	err := services.NewTimelineService(nil, mockFollowRepo, mockRedisRepo, nil, 2, nil).
+		UpdateTimeline(ctx, tweet)
+
+	assert.NoError(t, err)
+	mockRedisRepo.AssertExpectations(t)
+	mockFollowRepo.AssertNotCalled(t, "GetFollowers", ctx, "celebrity")
+	mockRedisRepo.AssertNotCalled(t, "AddToTimelines", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// 🔹 Test UpdateTimeline - autor por debajo del umbral
+func TestUpdateTimeline_BelowCelebrityThreshold(t *testing.T) {
+	ctx := context.Background()
+	mockFollowRepo := new(MockFollowRepository)
+	mockRedisRepo := new(MockRedisRepository)
+
+	tweet := &domain.Tweet{UserID: "user123", Content: "Hello world"}
+
+	mockFollowRepo.On("CountFollowers", ctx, "user123").Return(2, nil)
+	mockFollowRepo.On("GetFollowers", ctx, "user123").Return([]string{"follower1", "follower2"}, nil)
+	mockRedisRepo.On("AddToTimelines", ctx, []string{"follower1", "follower2"}, tweet).Return(nil)
+
+	err := services.NewTimelineService(nil, mockFollowRepo, mockRedisRepo, nil, 2, nil).
+		UpdateTimeline(ctx, tweet)
+
+	assert.NoError(t, err)
+	mockFollowRepo.AssertExpectations(t)
+	mockRedisRepo.AssertExpectations(t)
+}
+
+// 🔹 Test GetTimeline - mezcla los tweets de las celebridades que sigue el usuario
+func TestGetTimeline_MergesCelebrityTweets(t *testing.T) {
+	ctx := context.Background()
+	mockFollowRepo := new(MockFollowRepository)
+	mockRedisRepo := new(MockRedisRepository)
+	mockLikeRepo := new(MockLikeRepository)
+	service := services.NewTimelineService(nil, mockFollowRepo, mockRedisRepo, mockLikeRepo, 2, nil)
+
+	now := time.Now()
+	own := &domain.Tweet{ID: "1", UserID: "friend", CreatedAt: now.Add(-1 * time.Minute)}
+	celebrityNewest := &domain.Tweet{ID: "2", UserID: "celebrity", CreatedAt: now}
+	celebrityOldest := &domain.Tweet{ID: "3", UserID: "celebrity", CreatedAt: now.Add(-2 * time.Minute)}
+
+	mockRedisRepo.On("GetTimeline", ctx, "user123", "", 2).
+		Return(&domain.TimelinePage{Tweets: []*domain.Tweet{own}}, nil)
+	mockFollowRepo.On("GetFollowing", ctx, "user123").Return([]string{"friend", "celebrity"}, nil)
+	mockRedisRepo.On("GetCelebrities", ctx, []string{"friend", "celebrity"}).Return([]string{"celebrity"}, nil)
+	mockRedisRepo.On("GetCelebrityTweets", ctx, "celebrity", "", 2).
+		Return(&domain.TimelinePage{Tweets: []*domain.Tweet{celebrityNewest, celebrityOldest}}, nil)
+	mockLikeRepo.On("CountLikes", ctx, []string{"2", "1"}).Return(map[string]int{}, nil)
+
+	result, err := service.GetTimeline(ctx, "user123", "", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []*domain.Tweet{celebrityNewest, own}, result.Tweets)
+	assert.Equal(t, domain.EncodeTimelineCursor(own), result.NextCursor)
+
+	mockRedisRepo.AssertExpectations(t)
+	mockFollowRepo.AssertExpectations(t)
+}
+
+// 🔹 Test GetUserTweets - Success
+func TestGetUserTweets_Success(t *testing.T) {
+	ctx := context.Background()
+	mockTweetRepo := new(MockTweetRepository)
+	mockLikeRepo := new(MockLikeRepository)
+	service := services.NewTimelineService(mockTweetRepo, nil, nil, mockLikeRepo, 0, nil)
+
+	page := &domain.TimelinePage{Tweets: []*domain.Tweet{{ID: "tweet1", UserID: "user123"}}}
+	mockTweetRepo.On("ListByAuthor", ctx, "user123", "", 100).Return(page, nil)
+	mockLikeRepo.On("CountLikes", ctx, []string{"tweet1"}).Return(map[string]int{"tweet1": 1}, nil)
+
+	result, err := service.GetUserTweets(ctx, "user123", "", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, page, result)
+	assert.Equal(t, 1, result.Tweets[0].LikeCount)
+	mockTweetRepo.AssertExpectations(t)
+	mockLikeRepo.AssertExpectations(t)
+}
+
+// 🔹 Test GetUserTweets - cursor inválido
+func TestGetUserTweets_InvalidCursor(t *testing.T) {
+	ctx := context.Background()
+	mockTweetRepo := new(MockTweetRepository)
+	service := services.NewTimelineService(mockTweetRepo, nil, nil, nil, 0, nil)
+
+	_, err := service.GetUserTweets(ctx, "user123", "not-a-cursor", 10)
+	assert.ErrorIs(t, err, domain.ErrInvalidCursor)
+	mockTweetRepo.AssertNotCalled(t, "ListByAuthor", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// 🔹 Test GetTimeline - un tweet de una celebridad y su retweet se muestran una sola vez
+func TestGetTimeline_MergeDeduplicatesRetweets(t *testing.T) {
+	ctx := context.Background()
+	mockFollowRepo := new(MockFollowRepository)
+	mockRedisRepo := new(MockRedisRepository)
+	mockLikeRepo := new(MockLikeRepository)
+	service := services.NewTimelineService(nil, mockFollowRepo, mockRedisRepo, mockLikeRepo, 2, nil)
+
+	now := time.Now()
+	original := &domain.Tweet{ID: "1", UserID: "celebrity", CreatedAt: now.Add(-2 * time.Minute)}
+	retweet := &domain.Tweet{ID: "2", UserID: "friend", CreatedAt: now.Add(-1 * time.Minute), Kind: domain.TweetKindRetweet, ReferencedTweetID: "1"}
+
+	mockRedisRepo.On("GetTimeline", ctx, "user123", "", 10).
+		Return(&domain.TimelinePage{Tweets: []*domain.Tweet{retweet}}, nil)
+	mockFollowRepo.On("GetFollowing", ctx, "user123").Return([]string{"friend", "celebrity"}, nil)
+	mockRedisRepo.On("GetCelebrities", ctx, []string{"friend", "celebrity"}).Return([]string{"celebrity"}, nil)
+	mockRedisRepo.On("GetCelebrityTweets", ctx, "celebrity", "", 10).
+		Return(&domain.TimelinePage{Tweets: []*domain.Tweet{original}}, nil)
+	// El retweet muestra los likes del tweet original
+	mockLikeRepo.On("CountLikes", ctx, []string{"1"}).Return(map[string]int{"1": 4}, nil)
+
+	result, err := service.GetTimeline(ctx, "user123", "", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []*domain.Tweet{retweet}, result.Tweets)
+	assert.Equal(t, 4, result.Tweets[0].LikeCount)
+	assert.Empty(t, result.NextCursor)
+}
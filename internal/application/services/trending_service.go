@@ -0,0 +1,218 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"ChallengeUALA/internal/application/ports"
+	"ChallengeUALA/internal/domain"
+)
+
+// Las tendencias se calculan sobre los últimos trendWindow, sumando buckets de trendBucketSize
+// para que la ventana avance de a un bucket sin recontar los tweets.
+const (
+	trendBucketSize = 5 * time.Minute
+	trendWindow     = time.Hour
+)
+
+// defaultTrendsLimit y maxTrendsLimit son la cantidad de tendencias que se devuelven por defecto y como máximo
+const (
+	defaultTrendsLimit = 10
+	maxTrendsLimit     = 50
+)
+
+// TrendingService cuenta los hashtags de los tweets que se consumen de Kafka y arma las tendencias.
+// Los retweets suman a las tendencias pero no se listan en los tweets del hashtag, que ya tiene el original.
+type TrendingService struct {
+	trendRepo ports.TrendRepository
+	likeRepo  ports.LikeRepository
+	logger    *log.Logger
+}
+
+// NewTrendingService crea una nueva instancia de TrendingService
+func NewTrendingService(trendRepo ports.TrendRepository, likeRepo ports.LikeRepository, logger *log.Logger) *TrendingService {
+	return &TrendingService{
+		trendRepo: trendRepo,
+		likeRepo:  likeRepo,
+		logger:    logger,
+	}
+}
+
+// IndexTweet suma los hashtags de un tweet nuevo en el bucket de su fecha de creación y lo agrega a
+// los tweets de cada hashtag. Los tweets que llegan cuando ya salieron de la ventana no suman.
+func (s *TrendingService) IndexTweet(ctx context.Context, tweet *domain.Tweet) error {
+	tags := domain.HashtagTags(tweet.Content)
+	if len(tags) == 0 {
+		return nil
+	}
+
+	if err := s.countHashtags(ctx, tweet.ID, tags, tweet.CreatedAt); err != nil {
+		return err
+	}
+
+	if tweet.IsRetweet() {
+		return nil
+	}
+
+	if err := s.trendRepo.AddToHashtags(ctx, tags, tweet); err != nil {
+		return fmt.Errorf("error in calling trendRepo.AddToHashtags: %w", err)
+	}
+
+	return nil
+}
+
+// ReindexTweet actualiza un tweet editado: los hashtags que se sacaron dejan de listarlo, los que
+// se mantienen guardan la versión nueva y los que se agregaron lo suman como un uso más. Los
+// retweets se ignoran: copian la edición del original sin sus revisiones, así que todos sus hashtags
+// parecerían nuevos, y nunca se listan en los tweets del hashtag.
+func (s *TrendingService) ReindexTweet(ctx context.Context, tweet *domain.Tweet) error {
+	if tweet.IsRetweet() {
+		return nil
+	}
+
+	var previous []string
+	if len(tweet.Revisions) > 0 {
+		previous = domain.HashtagTags(tweet.Revisions[len(tweet.Revisions)-1].Content)
+	}
+	current := domain.HashtagTags(tweet.Content)
+
+	removed := tagsNotIn(previous, current)
+	kept := tagsNotIn(previous, removed)
+	added := tagsNotIn(current, previous)
+
+	if len(removed) > 0 {
+		if err := s.trendRepo.RemoveFromHashtags(ctx, removed, tweet.ID); err != nil {
+			return fmt.Errorf("error in calling trendRepo.RemoveFromHashtags: %w", err)
+		}
+	}
+
+	if len(kept) > 0 {
+		if err := s.trendRepo.ReplaceInHashtags(ctx, kept, tweet); err != nil {
+			return fmt.Errorf("error in calling trendRepo.ReplaceInHashtags: %w", err)
+		}
+	}
+
+	if len(added) == 0 {
+		return nil
+	}
+
+	editedAt := tweet.CreatedAt
+	if tweet.EditedAt != nil {
+		editedAt = *tweet.EditedAt
+	}
+	if err := s.countHashtags(ctx, tweet.ID, added, editedAt); err != nil {
+		return err
+	}
+
+	if err := s.trendRepo.AddToHashtags(ctx, added, tweet); err != nil {
+		return fmt.Errorf("error in calling trendRepo.AddToHashtags: %w", err)
+	}
+
+	return nil
+}
+
+// UnindexTweet saca un tweet borrado de los tweets de sus hashtags. Los usos ya contados quedan
+// en las tendencias hasta que su bucket sale de la ventana.
+func (s *TrendingService) UnindexTweet(ctx context.Context, tweet *domain.Tweet) error {
+	tags := domain.HashtagTags(tweet.Content)
+	if tweet.IsRetweet() || len(tags) == 0 {
+		return nil
+	}
+
+	if err := s.trendRepo.RemoveFromHashtags(ctx, tags, tweet.ID); err != nil {
+		return fmt.Errorf("error in calling trendRepo.RemoveFromHashtags: %w", err)
+	}
+
+	return nil
+}
+
+// GetTrends devuelve los hashtags más usados en la última hora, del más usado al menos usado.
+func (s *TrendingService) GetTrends(ctx context.Context, limit int) ([]domain.Trend, error) {
+	if limit < 0 {
+		return nil, fmt.Errorf("limit must be positive")
+	}
+
+	if limit == 0 {
+		limit = defaultTrendsLimit
+	}
+	if limit > maxTrendsLimit {
+		limit = maxTrendsLimit
+	}
+
+	trends, err := s.trendRepo.TopHashtags(ctx, trendBuckets(time.Now()), limit)
+	if err != nil {
+		return nil, fmt.Errorf("error in calling trendRepo.TopHashtags: %w", err)
+	}
+
+	return trends, nil
+}
+
+// GetHashtagTweets devuelve una página de los tweets recientes con un hashtag, con el mismo
+// cursor y límite que GetTimeline. El tag se acepta con o sin '#' y sin importar mayúsculas.
+func (s *TrendingService) GetHashtagTweets(ctx context.Context, tag, cursor string, limit int) (*domain.TimelinePage, error) {
+	tag = domain.NormalizeHashtag(tag)
+	if tag == "" {
+		return nil, fmt.Errorf("tag is required")
+	}
+
+	limit, err := validatePageRequest(tag, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := s.trendRepo.GetHashtagTweets(ctx, tag, cursor, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error in calling trendRepo.GetHashtagTweets: %w", err)
+	}
+
+	if err := hydrateLikeCounts(ctx, s.likeRepo, page.Tweets); err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// countHashtags suma un uso de tweetID a cada tag en el bucket de at, salvo que at ya esté fuera de
+// la ventana. El repositorio ignora los usos ya contados, así que un evento entregado dos veces suma una.
+func (s *TrendingService) countHashtags(ctx context.Context, tweetID string, tags []string, at time.Time) error {
+	if time.Since(at) > trendWindow {
+		return nil
+	}
+
+	bucket := at.UTC().Truncate(trendBucketSize)
+	if err := s.trendRepo.IncrementHashtags(ctx, tweetID, tags, bucket, trendWindow+trendBucketSize); err != nil {
+		return fmt.Errorf("error in calling trendRepo.IncrementHashtags: %w", err)
+	}
+
+	return nil
+}
+
+// trendBuckets devuelve el inicio de los buckets que forman la ventana que termina en now
+func trendBuckets(now time.Time) []time.Time {
+	current := now.UTC().Truncate(trendBucketSize)
+	count := int(trendWindow / trendBucketSize)
+
+	buckets := make([]time.Time, 0, count)
+	for i := count - 1; i >= 0; i-- {
+		buckets = append(buckets, current.Add(-time.Duration(i)*trendBucketSize))
+	}
+	return buckets
+}
+
+// tagsNotIn devuelve los tags de tags que no están en exclude
+func tagsNotIn(tags, exclude []string) []string {
+	excluded := make(map[string]bool, len(exclude))
+	for _, tag := range exclude {
+		excluded[tag] = true
+	}
+
+	var result []string
+	for _, tag := range tags {
+		if !excluded[tag] {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
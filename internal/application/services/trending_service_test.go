@@ -0,0 +1,194 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"ChallengeUALA/internal/application/services"
+	"ChallengeUALA/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTrendRepository simula el almacenamiento de tendencias.
+type MockTrendRepository struct {
+	mock.Mock
+}
+
+func (m *MockTrendRepository) IncrementHashtags(ctx context.Context, tweetID string, tags []string, bucket time.Time, ttl time.Duration) error {
+	args := m.Called(ctx, tweetID, tags, bucket, ttl)
+	return args.Error(0)
+}
+
+func (m *MockTrendRepository) TopHashtags(ctx context.Context, buckets []time.Time, limit int) ([]domain.Trend, error) {
+	args := m.Called(ctx, buckets, limit)
+	trends, _ := args.Get(0).([]domain.Trend)
+	return trends, args.Error(1)
+}
+
+func (m *MockTrendRepository) AddToHashtags(ctx context.Context, tags []string, tweet *domain.Tweet) error {
+	args := m.Called(ctx, tags, tweet)
+	return args.Error(0)
+}
+
+func (m *MockTrendRepository) GetHashtagTweets(ctx context.Context, tag string, cursor string, limit int) (*domain.TimelinePage, error) {
+	args := m.Called(ctx, tag, cursor, limit)
+	page, _ := args.Get(0).(*domain.TimelinePage)
+	return page, args.Error(1)
+}
+
+func (m *MockTrendRepository) RemoveFromHashtags(ctx context.Context, tags []string, tweetID string) error {
+	args := m.Called(ctx, tags, tweetID)
+	return args.Error(0)
+}
+
+func (m *MockTrendRepository) ReplaceInHashtags(ctx context.Context, tags []string, tweet *domain.Tweet) error {
+	args := m.Called(ctx, tags, tweet)
+	return args.Error(0)
+}
+
+func TestIndexTweet_CountsHashtags(t *testing.T) {
+	ctx := context.Background()
+	mockTrendRepo := new(MockTrendRepository)
+	service := services.NewTrendingService(mockTrendRepo, nil, nil)
+
+	tweet := domain.NewTweet("user1", "Hola #Go y #golang, otra vez #go")
+	bucket := tweet.CreatedAt.Truncate(5 * time.Minute)
+
+	mockTrendRepo.On("IncrementHashtags", ctx, tweet.ID, []string{"go", "golang"}, bucket, 65*time.Minute).Return(nil)
+	mockTrendRepo.On("AddToHashtags", ctx, []string{"go", "golang"}, tweet).Return(nil)
+
+	assert.NoError(t, service.IndexTweet(ctx, tweet))
+	mockTrendRepo.AssertExpectations(t)
+}
+
+func TestIndexTweet_RetweetOnlyCounts(t *testing.T) {
+	ctx := context.Background()
+	mockTrendRepo := new(MockTrendRepository)
+	service := services.NewTrendingService(mockTrendRepo, nil, nil)
+
+	retweet := domain.NewRetweet("user2", domain.NewTweet("user1", "#go"))
+	mockTrendRepo.On("IncrementHashtags", ctx, retweet.ID, []string{"go"}, mock.Anything, mock.Anything).Return(nil)
+
+	assert.NoError(t, service.IndexTweet(ctx, retweet))
+	mockTrendRepo.AssertExpectations(t)
+	mockTrendRepo.AssertNotCalled(t, "AddToHashtags", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestIndexTweet_OutsideWindow(t *testing.T) {
+	ctx := context.Background()
+	mockTrendRepo := new(MockTrendRepository)
+	service := services.NewTrendingService(mockTrendRepo, nil, nil)
+
+	// Un tweet viejo se lista en el hashtag pero ya no suma a las tendencias
+	tweet := &domain.Tweet{ID: "1", UserID: "user1", Content: "#go", CreatedAt: time.Now().Add(-2 * time.Hour)}
+	mockTrendRepo.On("AddToHashtags", ctx, []string{"go"}, tweet).Return(nil)
+
+	assert.NoError(t, service.IndexTweet(ctx, tweet))
+	mockTrendRepo.AssertNotCalled(t, "IncrementHashtags", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestIndexTweet_NoHashtags(t *testing.T) {
+	service := services.NewTrendingService(new(MockTrendRepository), nil, nil)
+
+	assert.NoError(t, service.IndexTweet(context.Background(), domain.NewTweet("user1", "Sin hashtags")))
+}
+
+func TestReindexTweet(t *testing.T) {
+	ctx := context.Background()
+	mockTrendRepo := new(MockTrendRepository)
+	service := services.NewTrendingService(mockTrendRepo, nil, nil)
+
+	tweet := domain.NewTweet("user1", "#go #java")
+	assert.NoError(t, tweet.Edit("#go #rust", time.Now()))
+
+	mockTrendRepo.On("RemoveFromHashtags", ctx, []string{"java"}, tweet.ID).Return(nil)
+	mockTrendRepo.On("ReplaceInHashtags", ctx, []string{"go"}, tweet).Return(nil)
+	mockTrendRepo.On("IncrementHashtags", ctx, tweet.ID, []string{"rust"}, mock.Anything, mock.Anything).Return(nil)
+	mockTrendRepo.On("AddToHashtags", ctx, []string{"rust"}, tweet).Return(nil)
+
+	assert.NoError(t, service.ReindexTweet(ctx, tweet))
+	mockTrendRepo.AssertExpectations(t)
+}
+
+func TestUnindexTweet(t *testing.T) {
+	ctx := context.Background()
+	mockTrendRepo := new(MockTrendRepository)
+	service := services.NewTrendingService(mockTrendRepo, nil, nil)
+
+	tweet := domain.NewTweet("user1", "#go")
+	mockTrendRepo.On("RemoveFromHashtags", ctx, []string{"go"}, tweet.ID).Return(errors.New("redis error")).Once()
+	mockTrendRepo.On("RemoveFromHashtags", ctx, []string{"go"}, tweet.ID).Return(nil).Once()
+
+	assert.Error(t, service.UnindexTweet(ctx, tweet))
+	assert.NoError(t, service.UnindexTweet(ctx, tweet))
+	mockTrendRepo.AssertExpectations(t)
+}
+
+func TestGetTrends(t *testing.T) {
+	ctx := context.Background()
+	mockTrendRepo := new(MockTrendRepository)
+	service := services.NewTrendingService(mockTrendRepo, nil, nil)
+
+	trends := []domain.Trend{{Hashtag: "go", Count: 3}}
+	// La ventana de una hora son 12 buckets de 5 minutos, del más viejo al actual
+	oneHourOfBuckets := mock.MatchedBy(func(buckets []time.Time) bool {
+		return len(buckets) == 12 && buckets[11].Sub(buckets[0]) == 55*time.Minute
+	})
+	mockTrendRepo.On("TopHashtags", ctx, oneHourOfBuckets, 10).Return(trends, nil)
+	mockTrendRepo.On("TopHashtags", ctx, oneHourOfBuckets, 50).Return(trends, nil)
+
+	result, err := service.GetTrends(ctx, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, trends, result)
+
+	_, err = service.GetTrends(ctx, 1000)
+	assert.NoError(t, err)
+	mockTrendRepo.AssertExpectations(t)
+
+	_, err = service.GetTrends(ctx, -1)
+	assert.Error(t, err)
+}
+
+func TestGetHashtagTweets(t *testing.T) {
+	ctx := context.Background()
+	mockTrendRepo := new(MockTrendRepository)
+	mockLikeRepo := new(MockLikeRepository)
+	service := services.NewTrendingService(mockTrendRepo, mockLikeRepo, nil)
+
+	page := &domain.TimelinePage{Tweets: []*domain.Tweet{{ID: "1", Content: "#Go"}}}
+	mockTrendRepo.On("GetHashtagTweets", ctx, "go", "", 100).Return(page, nil)
+	mockLikeRepo.On("CountLikes", ctx, []string{"1"}).Return(map[string]int{"1": 2}, nil)
+
+	// El tag se normaliza igual que al guardarlo
+	result, err := service.GetHashtagTweets(ctx, "#GO", "", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Tweets[0].LikeCount)
+
+	_, err = service.GetHashtagTweets(ctx, "go", "bad", 0)
+	assert.ErrorIs(t, err, domain.ErrInvalidCursor)
+
+	_, err = service.GetHashtagTweets(ctx, "#", "", 0)
+	assert.Error(t, err)
+}
+
+func TestReindexTweet_IgnoresRetweets(t *testing.T) {
+	ctx := context.Background()
+	mockTrendRepo := new(MockTrendRepository)
+	service := services.NewTrendingService(mockTrendRepo, nil, nil)
+
+	// Editar el original le copia el contenido a sus retweets, que emiten su propio tweet_edited
+	original := domain.NewTweet("user1", "#go")
+	retweet := domain.NewRetweet("user2", original)
+	assert.NoError(t, original.Edit("#go #rust", time.Now()))
+	assert.True(t, retweet.SyncRetweet(original))
+
+	assert.NoError(t, service.ReindexTweet(ctx, retweet))
+	mockTrendRepo.AssertNotCalled(t, "IncrementHashtags", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockTrendRepo.AssertNotCalled(t, "AddToHashtags", mock.Anything, mock.Anything, mock.Anything)
+	mockTrendRepo.AssertNotCalled(t, "ReplaceInHashtags", mock.Anything, mock.Anything, mock.Anything)
+	mockTrendRepo.AssertNotCalled(t, "RemoveFromHashtags", mock.Anything, mock.Anything, mock.Anything)
+}
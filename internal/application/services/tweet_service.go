@@ -4,85 +4,422 @@ import (
 	"ChallengeUALA/internal/application/ports"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
-	"math"
 	"time"
 
 	"ChallengeUALA/internal/domain"
 )
 
+// Una Idempotency-Key queda reservada idempotencyLockTTL mientras se procesa el pedido y, una vez
+// creado el tweet, apunta a él durante idempotencyKeyTTL
+const (
+	idempotencyLockTTL = time.Minute
+	idempotencyKeyTTL  = 24 * time.Hour
+)
+
 // TweetService es un servicio de aplicación que maneja la lógica de negocio relacionada con los tweets.
 type TweetService struct {
-	tweetRepo       ports.TweetRepository
-	eventProducer   ports.EventProducer
-	deadLetterQueue ports.DeadLetterQueue
-	logger          *log.Logger
+	tweetRepo     ports.TweetRepository
+	likeRepo      ports.LikeRepository
+	userRepo      ports.UserRepository
+	notifications ports.BatchEventProducer
+	idempotency   ports.IdempotencyRepository
+	logger        *log.Logger
 }
 
 // NewTweetService crea una nueva instancia de TweetService
 func NewTweetService(
 	tr ports.TweetRepository,
-	ep ports.EventProducer,
-	dlq ports.DeadLetterQueue,
+	lr ports.LikeRepository,
+	ur ports.UserRepository,
+	notifications ports.BatchEventProducer,
+	idempotency ports.IdempotencyRepository,
 	logger *log.Logger,
 ) *TweetService {
 	return &TweetService{
-		tweetRepo:       tr,
-		eventProducer:   ep,
-		deadLetterQueue: dlq,
-		logger:          logger,
+		tweetRepo:     tr,
+		likeRepo:      lr,
+		userRepo:      ur,
+		notifications: notifications,
+		idempotency:   idempotency,
+		logger:        logger,
 	}
 }
 
-// PostTweet crea un nuevo tweet y lo guarda en la base de datos (En este caso, en memoria, pero sería POSTGRES)
-// También envía un evento a Kafka para notificar que se creó un nuevo tweet.
-func (s *TweetService) PostTweet(ctx context.Context, userID, content string) error {
+// PostTweet crea un nuevo tweet y lo guarda junto con su evento en el outbox.
+// El worker.OutboxRelay se encarga después de publicar el evento en Kafka,
+// así que si el proceso se cae el evento no se pierde. Si inReplyToID no está
+// vacío el tweet es una respuesta y el tweet padre tiene que existir. Los usuarios mencionados y
+// el autor del tweet padre reciben una notificación.
+//
+// Si idempotencyKey no está vacía, un reintento con la misma key del mismo usuario en las 24 horas
+// siguientes no crea otro tweet: devuelve el tweet que creó el pedido original, o
+// domain.ErrIdempotencyKeyInUse si todavía se está procesando. Reusar la key con otro contenido u
+// otro tweet padre devuelve domain.ErrIdempotencyKeyMismatch.
+func (s *TweetService) PostTweet(ctx context.Context, userID, content, inReplyToID, idempotencyKey string) (*domain.Tweet, error) {
 
-	if len(content) > 280 {
-		return fmt.Errorf("tweet content is too long")
+	if err := validateContent(content); err != nil {
+		return nil, err
 	}
 
-	if len(content) == 0 {
-		return fmt.Errorf("tweet content is empty")
+	if idempotencyKey == "" {
+		return s.postTweet(ctx, userID, content, inReplyToID)
+	}
+
+	if len(idempotencyKey) > domain.MaxIdempotencyKeyLength {
+		return nil, domain.ErrInvalidIdempotencyKey
+	}
+
+	// Las keys son de cada usuario: dos usuarios pueden usar la misma sin pisarse
+	key := userID + ":" + idempotencyKey
+	fingerprint := domain.TweetRequestFingerprint(content, inReplyToID)
+	tweetID, err := s.idempotency.Reserve(ctx, key, fingerprint, idempotencyLockTTL)
+	if err != nil {
+		return nil, fmt.Errorf("error in calling idempotency.Reserve: %w", err)
+	}
+	if tweetID != "" {
+		tweet, err := s.tweetRepo.GetByID(ctx, tweetID)
+		if err != nil {
+			return nil, fmt.Errorf("error in calling tweetRepo.GetByID: %w", err)
+		}
+		return tweet, nil
 	}
 
+	tweet, err := s.postTweet(ctx, userID, content, inReplyToID)
+	if err != nil {
+		if releaseErr := s.idempotency.Release(ctx, key); releaseErr != nil {
+			s.logger.Printf("Error releasing idempotency key of user %s: %v", userID, releaseErr)
+		}
+		return nil, err
+	}
+
+	// El tweet ya se guardó, pero si la key no queda asociada a él un reintento pasada la reserva lo
+	// duplicaría. Se devuelve el error para que el cliente lo sepa; mientras dure la reserva sus
+	// reintentos reciben domain.ErrIdempotencyKeyInUse.
+	if err := s.idempotency.Complete(ctx, key, fingerprint, tweet.ID, idempotencyKeyTTL); err != nil {
+		return nil, fmt.Errorf("error in calling idempotency.Complete: %w", err)
+	}
+
+	return tweet, nil
+}
+
+// postTweet arma el tweet o la respuesta y lo guarda
+func (s *TweetService) postTweet(ctx context.Context, userID, content, inReplyToID string) (*domain.Tweet, error) {
+
 	tweet := domain.NewTweet(userID, content)
-	if err := s.tweetRepo.Save(ctx, tweet); err != nil {
+	var repliedUserID string
+	if inReplyToID != "" {
+		parent, err := s.tweetRepo.GetByID(ctx, inReplyToID)
+		if errors.Is(err, domain.ErrTweetNotFound) {
+			return nil, domain.ErrParentTweetNotFound
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error in calling tweetRepo.GetByID: %w", err)
+		}
+		tweet = domain.NewReply(userID, content, parent)
+		repliedUserID = parent.UserID
+	}
+
+	if err := s.saveNewTweet(ctx, tweet, repliedUserID); err != nil {
+		return nil, err
+	}
+
+	return tweet, nil
+}
+
+// Retweet publica un retweet de tweetID hecho por userID, o una cita si comment no está vacío.
+// Retwitear un retweet equivale a retwitear el tweet original.
+func (s *TweetService) Retweet(ctx context.Context, userID, tweetID, comment string) error {
+	if comment != "" {
+		if err := validateContent(comment); err != nil {
+			return err
+		}
+	}
+
+	original, err := s.tweetRepo.GetByID(ctx, tweetID)
+	if err != nil {
+		return fmt.Errorf("error in calling tweetRepo.GetByID: %w", err)
+	}
+
+	if original.IsRetweet() {
+		original, err = s.tweetRepo.GetByID(ctx, original.ReferencedTweetID)
+		if err != nil {
+			return fmt.Errorf("error in calling tweetRepo.GetByID: %w", err)
+		}
+	}
+
+	tweet := domain.NewRetweet(userID, original)
+	if comment != "" {
+		tweet = domain.NewQuote(userID, comment, original)
+	}
+
+	return s.saveNewTweet(ctx, tweet, "")
+}
+
+// saveNewTweet guarda un tweet nuevo junto con su evento tweet_created y publica sus notificaciones.
+// Los retweets ya traen las menciones y los hashtags del tweet original, así que no notifican.
+func (s *TweetService) saveNewTweet(ctx context.Context, tweet *domain.Tweet, repliedUserID string) error {
+	if !tweet.IsRetweet() {
+		if err := s.extractEntities(ctx, tweet); err != nil {
+			return err
+		}
+	}
+
+	event, err := newTweetOutboxEvent(domain.EventTweetCreated, tweet)
+	if err != nil {
+		return err
+	}
+
+	if err := s.tweetRepo.SaveWithEvent(ctx, tweet, event); err != nil {
 		return fmt.Errorf("error saving tweet: %w", err)
 	}
 
-	go func() {
-		// Nuevo contexto para el evento, con un timeout de 10 segundos.
-		// Incluso si el cliente cancela la solicitud HTTP original con un timeout o aborta,
-		// el evento se envia de todas formas.
-		eventCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
-		const maxRetries = 3
-		for i := 0; i < maxRetries; i++ {
+	if !tweet.IsRetweet() {
+		s.notifyTweet(ctx, tweet, repliedUserID, nil)
+	}
 
-			serializedTweet, _ := json.Marshal(tweet)
-			err := s.eventProducer.PublishEvent(eventCtx, userID, serializedTweet)
+	return nil
+}
+
+// GetTweet devuelve un tweet por su ID con su cantidad de likes, o domain.ErrTweetNotFound si no existe.
+func (s *TweetService) GetTweet(ctx context.Context, tweetID string) (*domain.Tweet, error) {
+	tweet, err := s.tweetRepo.GetByID(ctx, tweetID)
+	if err != nil {
+		return nil, fmt.Errorf("error in calling tweetRepo.GetByID: %w", err)
+	}
+
+	if err := hydrateLikeCounts(ctx, s.likeRepo, []*domain.Tweet{tweet}); err != nil {
+		return nil, err
+	}
+
+	return tweet, nil
+}
+
+// GetThread devuelve la conversación alrededor de un tweet: los tweets a los que responde
+// y el árbol de respuestas que tiene debajo.
+func (s *TweetService) GetThread(ctx context.Context, tweetID string) (*domain.Thread, error) {
+	tweet, err := s.tweetRepo.GetByID(ctx, tweetID)
+	if err != nil {
+		return nil, fmt.Errorf("error in calling tweetRepo.GetByID: %w", err)
+	}
+
+	conversation, err := s.tweetRepo.ListByConversation(ctx, tweet.Conversation())
+	if err != nil {
+		return nil, fmt.Errorf("error in calling tweetRepo.ListByConversation: %w", err)
+	}
+
+	return domain.BuildThread(tweet, conversation), nil
+}
+
+// EditTweet cambia el contenido de un tweet dentro de la ventana de edición, guardando la versión
+// anterior, y guarda en el outbox el evento tweet_edited para reemplazarlo en los timelines.
+// Después copia el contenido nuevo a sus retweets. Solo se notifica a los usuarios que la edición
+// menciona por primera vez. Solo el autor puede editarlo: si userID es otro se devuelve
+// domain.ErrNotTweetOwner. Si otro pedido lo editó mientras tanto devuelve domain.ErrTweetConflict.
+func (s *TweetService) EditTweet(ctx context.Context, userID, tweetID, content string) (*domain.Tweet, error) {
+	if err := validateContent(content); err != nil {
+		return nil, err
+	}
+
+	tweet, err := s.tweetRepo.GetByID(ctx, tweetID)
+	if err != nil {
+		return nil, fmt.Errorf("error in calling tweetRepo.GetByID: %w", err)
+	}
+
+	if tweet.UserID != userID {
+		return nil, domain.ErrNotTweetOwner
+	}
+
+	// Si el contenido no cambia no se guarda otra versión: es el reintento de una edición que falló
+	// al actualizar los retweets, así que solo se termina eso
+	if tweet.Content == content && !tweet.IsRetweet() {
+		if err := s.syncRetweets(ctx, tweet); err != nil {
+			return nil, err
+		}
+		return tweet, nil
+	}
+
+	if err := tweet.Edit(content, time.Now()); err != nil {
+		return nil, err
+	}
+
+	alreadyMentioned := tweet.MentionedUserIDs()
+
+	if err := s.extractEntities(ctx, tweet); err != nil {
+		return nil, err
+	}
+
+	event, err := newTweetOutboxEvent(domain.EventTweetEdited, tweet)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.tweetRepo.UpdateWithEvent(ctx, tweet, event); err != nil {
+		return nil, fmt.Errorf("error updating tweet: %w", err)
+	}
+
+	s.notifyTweet(ctx, tweet, "", alreadyMentioned)
+
+	if err := s.syncRetweets(ctx, tweet); err != nil {
+		return nil, err
+	}
+
+	return tweet, nil
+}
+
+// syncRetweets copia el contenido de original a sus retweets, guardando para cada uno el evento
+// tweet_edited que lo reemplaza en los timelines. Los retweets que ya estaban al día se saltean,
+// así que se puede reintentar.
+func (s *TweetService) syncRetweets(ctx context.Context, original *domain.Tweet) error {
+	retweets, err := s.tweetRepo.ListRetweets(ctx, original.ID)
+	if err != nil {
+		return fmt.Errorf("error in calling tweetRepo.ListRetweets: %w", err)
+	}
+
+	for _, retweet := range retweets {
+		if !retweet.SyncRetweet(original) {
+			continue
+		}
+
+		event, err := newTweetOutboxEvent(domain.EventTweetEdited, retweet)
+		if err != nil {
+			return err
+		}
+
+		// Un retweet que se borró mientras tanto ya no hace falta actualizarlo
+		if err := s.tweetRepo.UpdateWithEvent(ctx, retweet, event); err != nil && !errors.Is(err, domain.ErrTweetNotFound) {
+			return fmt.Errorf("error updating retweet: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// DeleteTweet borra un tweet y guarda en el outbox el evento tweet_deleted, que el
+// FanoutConsumer usa para sacarlo de los timelines de los seguidores. Sus retweets se borran con
+// él; las citas quedan, porque tienen su propio contenido. Como al editar, solo lo puede borrar
+// su autor.
+func (s *TweetService) DeleteTweet(ctx context.Context, userID, tweetID string) error {
+	tweet, err := s.tweetRepo.GetByID(ctx, tweetID)
+	if err != nil {
+		return fmt.Errorf("error in calling tweetRepo.GetByID: %w", err)
+	}
+
+	if tweet.UserID != userID {
+		return domain.ErrNotTweetOwner
+	}
+
+	// Los retweets se borran antes que el original: si algo falla, el original sigue existiendo y
+	// reintentar el borrado termina de limpiarlos
+	if !tweet.IsRetweet() {
+		retweets, err := s.tweetRepo.ListRetweets(ctx, tweet.ID)
+		if err != nil {
+			return fmt.Errorf("error in calling tweetRepo.ListRetweets: %w", err)
+		}
+
+		for _, retweet := range retweets {
+			if err := s.deleteWithEvent(ctx, retweet); err != nil {
+				return err
+			}
+		}
+	}
+
+	return s.deleteWithEvent(ctx, tweet)
+}
+
+// deleteWithEvent borra el tweet junto con su evento tweet_deleted. Si ya no existe no hace nada.
+func (s *TweetService) deleteWithEvent(ctx context.Context, tweet *domain.Tweet) error {
+	event, err := newTweetOutboxEvent(domain.EventTweetDeleted, tweet)
+	if err != nil {
+		return err
+	}
+
+	if err := s.tweetRepo.DeleteWithEvent(ctx, tweet.ID, event); err != nil && !errors.Is(err, domain.ErrTweetNotFound) {
+		return fmt.Errorf("error deleting tweet: %w", err)
+	}
+
+	return nil
+}
+
+// extractEntities completa las menciones y los hashtags del tweet a partir de su contenido.
+// Cada mención se resuelve al usuario con ese handle; las menciones a handles que no existen se
+// descartan: quedan como texto pero no se enlazan.
+func (s *TweetService) extractEntities(ctx context.Context, tweet *domain.Tweet) error {
+	mentions, hashtags := domain.ParseEntities(tweet.Content)
+
+	userIDs := make(map[string]string)
+	tweet.Mentions = nil
+	for _, mention := range mentions {
+		handle := domain.NormalizeHandle(mention.Handle)
+		userID, checked := userIDs[handle]
+		if !checked {
+			user, err := s.userRepo.GetByHandle(ctx, handle)
+			if err != nil && !errors.Is(err, domain.ErrUserNotFound) {
+				return fmt.Errorf("error in calling userRepo.GetByHandle: %w", err)
+			}
 			if err == nil {
-				s.logger.Printf("Tweet event published")
-				return
+				userID = user.ID
 			}
-			// Backoff exponencial, en este caso lo puse porque sirve como mecanismo de reintentos
-			// sin embargo, no es la mejor práctica para manejar errores de red.
-			// En un sistema real, se debería implementar un mecanismo de reintentos más robusto.
-			// Por ejemplo, usando un circuit breaker o un retry con un backoff más inteligente.
-			time.Sleep(time.Second * time.Duration(math.Pow(2, float64(i))))
+			userIDs[handle] = userID
 		}
 
-		// Si falla después de reintentos, guardar en DLQ
-		// Un marshall a una struct no deberia fallar siempre y cuando la struct sea correcta
-		payload, _ := json.Marshal(tweet)
+		if userID != "" {
+			mention.UserID = userID
+			tweet.Mentions = append(tweet.Mentions, mention)
+		}
+	}
+	tweet.Hashtags = hashtags
+
+	return nil
+}
+
+// notifyTweet avisa a los usuarios mencionados en el tweet y, si es una respuesta, al autor del tweet
+// padre, que no recibe además la mención. Los usuarios de skip no se notifican. Todas las
+// notificaciones se publican juntas, así un tweet con muchas menciones no demora la respuesta.
+func (s *TweetService) notifyTweet(ctx context.Context, tweet *domain.Tweet, repliedUserID string, skip []string) {
+	notified := make(map[string]bool, len(skip))
+	for _, userID := range skip {
+		notified[userID] = true
+	}
 
-		if err := s.deadLetterQueue.StoreEvent(ctx, "tweet_events", payload); err != nil {
-			s.logger.Printf("Error storing event in DLQ: %v", err)
-			return
+	var notifications []*domain.Notification
+	if repliedUserID != "" && !notified[repliedUserID] {
+		notified[repliedUserID] = true
+		notifications = append(notifications, domain.NewNotification(domain.NotificationReply, repliedUserID, tweet.UserID, tweet.ID))
+	}
+
+	for _, userID := range tweet.MentionedUserIDs() {
+		if notified[userID] {
+			continue
 		}
-	}()
+		notified[userID] = true
+		notifications = append(notifications, domain.NewNotification(domain.NotificationMention, userID, tweet.UserID, tweet.ID))
+	}
+
+	publishNotifications(ctx, s.notifications, notifications...)
+}
+
+func validateContent(content string) error {
+	if len(content) > 280 {
+		return fmt.Errorf("tweet content is too long")
+	}
+
+	if len(content) == 0 {
+		return fmt.Errorf("tweet content is empty")
+	}
 
 	return nil
 }
+
+// newTweetOutboxEvent arma el evento del outbox con el tweet envuelto en un domain.TweetEvent
+func newTweetOutboxEvent(eventType string, tweet *domain.Tweet) (*domain.OutboxEvent, error) {
+	// Un marshall a una struct no deberia fallar siempre y cuando la struct sea correcta
+	payload, err := json.Marshal(domain.NewTweetEvent(eventType, tweet))
+	if err != nil {
+		return nil, fmt.Errorf("error serializing tweet: %w", err)
+	}
+
+	return domain.NewOutboxEvent(eventType, tweet.UserID, payload), nil
+}
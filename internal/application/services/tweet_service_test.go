@@ -3,12 +3,14 @@ package services_test
 import (
 	"ChallengeUALA/internal/application/services"
 	"ChallengeUALA/internal/domain"
-	"ChallengeUALA/internal/infrastructure/dlq"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
-	"sync"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -24,33 +26,68 @@ func (m *MockTweetRepository) Save(ctx context.Context, tweet *domain.Tweet) err
 	return args.Error(0)
 }
 
-// MockEventProducer simula la publicación de eventos en Kafka.
-type MockEventProducer struct {
-	mock.Mock
-	wg *sync.WaitGroup // Para sincronizar la goroutine
+func (m *MockTweetRepository) SaveWithEvent(ctx context.Context, tweet *domain.Tweet, event *domain.OutboxEvent) error {
+	args := m.Called(ctx, tweet, event)
+	return args.Error(0)
 }
 
-func (m *MockEventProducer) PublishEvent(ctx context.Context, userID string, payload []byte) error {
-	defer m.wg.Done() // Reduce el contador cuando se ejecuta
-	args := m.Called(ctx, userID, payload)
+func (m *MockTweetRepository) GetByID(ctx context.Context, id string) (*domain.Tweet, error) {
+	args := m.Called(ctx, id)
+	tweet, _ := args.Get(0).(*domain.Tweet)
+	return tweet, args.Error(1)
+}
+
+func (m *MockTweetRepository) ListByAuthor(ctx context.Context, authorID string, cursor string, limit int) (*domain.TimelinePage, error) {
+	args := m.Called(ctx, authorID, cursor, limit)
+	page, _ := args.Get(0).(*domain.TimelinePage)
+	return page, args.Error(1)
+}
+
+func (m *MockTweetRepository) ListByConversation(ctx context.Context, conversationID string) ([]*domain.Tweet, error) {
+	args := m.Called(ctx, conversationID)
+	tweets, _ := args.Get(0).([]*domain.Tweet)
+	return tweets, args.Error(1)
+}
+
+func (m *MockTweetRepository) ListRetweets(ctx context.Context, tweetID string) ([]*domain.Tweet, error) {
+	args := m.Called(ctx, tweetID)
+	tweets, _ := args.Get(0).([]*domain.Tweet)
+	return tweets, args.Error(1)
+}
+
+func (m *MockTweetRepository) UpdateWithEvent(ctx context.Context, tweet *domain.Tweet, event *domain.OutboxEvent) error {
+	args := m.Called(ctx, tweet, event)
+	return args.Error(0)
+}
+
+func (m *MockTweetRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
-// MockDeadLetterQueue simula una cola de eventos fallidos.
-type MockDeadLetterQueue struct {
+func (m *MockTweetRepository) DeleteWithEvent(ctx context.Context, id string, event *domain.OutboxEvent) error {
+	args := m.Called(ctx, id, event)
+	return args.Error(0)
+}
+
+// MockIdempotencyRepository simula el almacenamiento de las Idempotency-Key.
+type MockIdempotencyRepository struct {
 	mock.Mock
-	wg *sync.WaitGroup // Para sincronizar la goroutine
 }
 
-func (m *MockDeadLetterQueue) StoreEvent(ctx context.Context, eventType string, payload []byte) error {
-	defer m.wg.Done() // Reduce el contador cuando se ejecuta
-	args := m.Called(ctx, eventType, payload)
+func (m *MockIdempotencyRepository) Reserve(ctx context.Context, key, fingerprint string, lockTTL time.Duration) (string, error) {
+	args := m.Called(ctx, key, fingerprint, lockTTL)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockIdempotencyRepository) Complete(ctx context.Context, key, fingerprint, tweetID string, ttl time.Duration) error {
+	args := m.Called(ctx, key, fingerprint, tweetID, ttl)
 	return args.Error(0)
 }
 
-func (m *MockDeadLetterQueue) RetrieveEvents(ctx context.Context) ([]dlq.Message, error) {
-	args := m.Called(ctx)
-	return args.Get(0).([]dlq.Message), args.Error(1)
+func (m *MockIdempotencyRepository) Release(ctx context.Context, key string) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
 }
 
 func TestPostTweet_Success(t *testing.T) {
@@ -60,123 +97,173 @@ func TestPostTweet_Success(t *testing.T) {
 	logger := log.Default()
 
 	mockRepo := new(MockTweetRepository)
-	mockProducer := new(MockEventProducer)
-	mockDLQ := new(MockDeadLetterQueue)
-
-	var wg sync.WaitGroup
-	wg.Add(1) // Se espera que al menos una goroutine termine
 
-	mockProducer.wg = &wg
-	mockDLQ.wg = &wg
+	var savedTweet *domain.Tweet
+	var savedEvent *domain.OutboxEvent
+	mockRepo.On("SaveWithEvent", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			savedTweet = args.Get(1).(*domain.Tweet)
+			savedEvent = args.Get(2).(*domain.OutboxEvent)
+		}).
+		Return(nil)
 
-	mockRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
-	mockProducer.On("PublishEvent", mock.Anything, userID, mock.Anything).Return(nil)
+	tweetService := services.NewTweetService(mockRepo, nil, nil, nil, nil, logger)
 
-	tweetService := services.NewTweetService(mockRepo, mockProducer, mockDLQ, logger)
-
-	err := tweetService.PostTweet(ctx, userID, content)
+	tweet, err := tweetService.PostTweet(ctx, userID, content, "", "")
 	assert.NoError(t, err)
 
-	wg.Wait()
-
 	mockRepo.AssertExpectations(t)
-	mockProducer.AssertExpectations(t)
-	mockDLQ.AssertNotCalled(t, "StoreEvent") // DLQ no debería ser llamado en éxito
+	assert.Same(t, savedTweet, tweet)
+	assert.Equal(t, userID, savedTweet.UserID)
+	assert.Equal(t, content, savedTweet.Content)
+
+	// El evento del outbox lleva el tweet serializado junto con el tipo de evento
+	assert.Equal(t, domain.EventTweetCreated, savedEvent.EventType)
+	assert.Equal(t, userID, savedEvent.Key)
+	var payload domain.TweetEvent
+	assert.NoError(t, json.Unmarshal(savedEvent.Payload, &payload))
+	assert.Equal(t, domain.EventTweetCreated, payload.Type)
+	assert.Equal(t, savedTweet.ID, payload.Tweet.ID)
 }
 
-func TestPostTweet_EventProducerFails(t *testing.T) {
+func TestPostTweet_MentionsAndHashtags(t *testing.T) {
 	ctx := context.Background()
-	userID := "user123"
-	content := "Hello, world!"
-	logger := log.Default()
-
 	mockRepo := new(MockTweetRepository)
-	mockProducer := new(MockEventProducer)
-	mockDLQ := new(MockDeadLetterQueue)
-
-	var wg sync.WaitGroup
-	wg.Add(4) // Se espera 3 intentos + 1 guardado en DLQ
+	mockUserRepo := new(MockUserRepository)
 
-	mockProducer.wg = &wg
-	mockDLQ.wg = &wg
+	var savedEvent *domain.OutboxEvent
+	mockRepo.On("SaveWithEvent", ctx, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			savedEvent = args.Get(2).(*domain.OutboxEvent)
+		}).
+		Return(nil)
+	// Cada handle se busca una sola vez aunque se lo mencione dos veces
+	mockUserRepo.On("GetByHandle", ctx, "ana").Return(&domain.User{ID: "ana-id", Handle: "Ana"}, nil).Once()
+	mockUserRepo.On("GetByHandle", ctx, "ghost").Return(nil, domain.ErrUserNotFound).Once()
 
-	mockRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
-	mockProducer.On("PublishEvent", mock.Anything, userID, mock.Anything).Return(errors.New("publish failed")).Times(3)
-	mockDLQ.On("StoreEvent", mock.Anything, "tweet_events", mock.Anything).Return(nil)
+	tweetService := services.NewTweetService(mockRepo, nil, mockUserRepo, nil, nil, log.Default())
 
-	tweetService := services.NewTweetService(mockRepo, mockProducer, mockDLQ, logger)
+	_, err := tweetService.PostTweet(ctx, "user123", "Hola @ana y @ghost #GoLang @ANA", "", "")
+	assert.NoError(t, err)
+	mockUserRepo.AssertExpectations(t)
 
-	err := tweetService.PostTweet(ctx, userID, content)
+	// Las menciones y los hashtags viajan en el evento que se publica en Kafka
+	event, err := domain.DecodeTweetEvent(savedEvent.Payload)
 	assert.NoError(t, err)
+	assert.Equal(t, []domain.Mention{
+		{UserID: "ana-id", Handle: "ana", Start: 5, End: 9},
+		{UserID: "ana-id", Handle: "ANA", Start: 27, End: 31},
+	}, event.Tweet.Mentions)
+	assert.Equal(t, []domain.Hashtag{{Tag: "golang", Start: 19, End: 26}}, event.Tweet.Hashtags)
+}
+
+func TestPostTweet_MentionLookupFails(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockTweetRepository)
+	mockUserRepo := new(MockUserRepository)
+	mockUserRepo.On("GetByHandle", ctx, "ana").Return(nil, errors.New("db error"))
 
-	wg.Wait()
+	tweetService := services.NewTweetService(mockRepo, nil, mockUserRepo, nil, nil, log.Default())
 
-	mockRepo.AssertExpectations(t)
-	mockProducer.AssertExpectations(t)
-	mockDLQ.AssertExpectations(t)
+	_, err := tweetService.PostTweet(ctx, "user123", "Hola @ana", "", "")
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "SaveWithEvent", mock.Anything, mock.Anything, mock.Anything)
 }
 
-func TestPostTweet_JsonMarshalFail(t *testing.T) {
+func TestPostTweet_Reply(t *testing.T) {
 	ctx := context.Background()
-	userID := "user123"
-	content := "Hello, world!"
-	logger := log.Default()
+	parent := &domain.Tweet{ID: "tweet2", UserID: "user1", Content: "Reply", InReplyToID: "tweet1", ConversationID: "tweet1"}
 
 	mockRepo := new(MockTweetRepository)
-	mockProducer := new(MockEventProducer)
-	mockDLQ := new(MockDeadLetterQueue)
+	mockRepo.On("GetByID", ctx, "tweet2").Return(parent, nil)
 
-	var wg sync.WaitGroup
-	wg.Add(1) // Se espera 1 intento
+	var savedTweet *domain.Tweet
+	mockRepo.On("SaveWithEvent", ctx, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			savedTweet = args.Get(1).(*domain.Tweet)
+		}).
+		Return(nil)
 
-	mockProducer.wg = &wg
-	mockDLQ.wg = &wg
+	tweetService := services.NewTweetService(mockRepo, nil, nil, nil, nil, log.Default())
 
-	mockRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
-	mockProducer.On("PublishEvent", mock.Anything, userID, mock.Anything).Return(nil)
+	_, err := tweetService.PostTweet(ctx, "user123", "Reply to reply", "tweet2", "")
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
 
-	tweetService := services.NewTweetService(mockRepo, mockProducer, mockDLQ, logger)
+	// La respuesta queda en la conversación del primer tweet
+	assert.Equal(t, "tweet2", savedTweet.InReplyToID)
+	assert.Equal(t, "tweet1", savedTweet.ConversationID)
+}
 
-	err := tweetService.PostTweet(ctx, userID, content)
-	assert.NoError(t, err)
+func TestPostTweet_NotifiesReplyAndMentions(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockTweetRepository)
+	mockUserRepo := new(MockUserRepository)
+	mockProducer := new(MockEventProducer)
 
-	wg.Wait()
+	mockRepo.On("GetByID", ctx, "tweet1").Return(&domain.Tweet{ID: "tweet1", UserID: "ana", ConversationID: "tweet1"}, nil)
+	mockRepo.On("SaveWithEvent", ctx, mock.Anything, mock.Anything).Return(nil)
+	for _, handle := range []string{"ana", "beto", "user123"} {
+		mockUserRepo.On("GetByHandle", ctx, handle).Return(&domain.User{ID: handle, Handle: handle}, nil)
+	}
+	mockProducer.On("PublishEvents", ctx, mock.Anything).Return(nil)
 
-	mockRepo.AssertExpectations(t)
-	mockProducer.AssertExpectations(t)
-	mockDLQ.AssertNotCalled(t, "StoreEvent") // DLQ no debería ser llamado en éxito
+	tweetService := services.NewTweetService(mockRepo, nil, mockUserRepo, mockProducer, nil, log.Default())
+
+	// ana recibe la respuesta pero no además la mención, y el autor no se notifica a sí mismo
+	_, err := tweetService.PostTweet(ctx, "user123", "@ana @beto @user123 mirá esto", "tweet1", "")
+	assert.NoError(t, err)
+
+	// La respuesta y las menciones se publican en una sola escritura
+	mockProducer.AssertNumberOfCalls(t, "PublishEvents", 1)
+	published := mockProducer.published(t)
+	if assert.Len(t, published, 2) {
+		assert.Equal(t, domain.NotificationReply, published[0].Type)
+		assert.Equal(t, "ana", published[0].UserID)
+		assert.Equal(t, domain.NotificationMention, published[1].Type)
+		assert.Equal(t, "beto", published[1].UserID)
+		assert.Equal(t, "user123", published[1].ActorID)
+	}
 }
 
-func TestPostTweet_StoreEventFails(t *testing.T) {
+func TestPostTweet_ReplyParentNotFound(t *testing.T) {
 	ctx := context.Background()
-	userID := "user123"
-	content := "Hello, world!"
-	logger := log.Default()
-
 	mockRepo := new(MockTweetRepository)
-	mockProducer := new(MockEventProducer)
-	mockDLQ := new(MockDeadLetterQueue)
+	mockRepo.On("GetByID", ctx, "tweet1").Return(nil, domain.ErrTweetNotFound)
 
-	var wg sync.WaitGroup
-	wg.Add(4) // 3 intentos de PublishEvent + 1 intento de StoreEvent
+	tweetService := services.NewTweetService(mockRepo, nil, nil, nil, nil, log.Default())
+
+	_, err := tweetService.PostTweet(ctx, "user123", "Reply", "tweet1", "")
+	assert.ErrorIs(t, err, domain.ErrParentTweetNotFound)
+	mockRepo.AssertNotCalled(t, "SaveWithEvent", mock.Anything, mock.Anything, mock.Anything)
+}
 
-	mockProducer.wg = &wg
-	mockDLQ.wg = &wg
+func TestGetThread(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	root := &domain.Tweet{ID: "1", UserID: "user1", CreatedAt: now, ConversationID: "1"}
+	reply := &domain.Tweet{ID: "2", UserID: "user2", CreatedAt: now.Add(time.Second), InReplyToID: "1", ConversationID: "1"}
+	firstAnswer := &domain.Tweet{ID: "3", UserID: "user1", CreatedAt: now.Add(2 * time.Second), InReplyToID: "2", ConversationID: "1"}
+	secondAnswer := &domain.Tweet{ID: "4", UserID: "user3", CreatedAt: now.Add(3 * time.Second), InReplyToID: "2", ConversationID: "1"}
+	// La respuesta a un tweet borrado no se puede colgar del árbol
+	orphan := &domain.Tweet{ID: "5", UserID: "user3", CreatedAt: now, InReplyToID: "deleted", ConversationID: "1"}
 
-	mockRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
-	mockProducer.On("PublishEvent", mock.Anything, userID, mock.Anything).Return(errors.New("publish failed")).Times(3)
-	mockDLQ.On("StoreEvent", mock.Anything, "tweet_events", mock.Anything).Return(errors.New("store event failed"))
+	mockRepo := new(MockTweetRepository)
+	mockRepo.On("GetByID", ctx, "2").Return(reply, nil)
+	mockRepo.On("ListByConversation", ctx, "1").
+		Return([]*domain.Tweet{secondAnswer, root, orphan, reply, firstAnswer}, nil)
 
-	tweetService := services.NewTweetService(mockRepo, mockProducer, mockDLQ, logger)
+	tweetService := services.NewTweetService(mockRepo, nil, nil, nil, nil, log.Default())
 
-	err := tweetService.PostTweet(ctx, userID, content)
+	thread, err := tweetService.GetThread(ctx, "2")
 	assert.NoError(t, err)
 
-	wg.Wait()
-
-	mockRepo.AssertExpectations(t)
-	mockProducer.AssertExpectations(t)
-	mockDLQ.AssertExpectations(t)
+	assert.Equal(t, []*domain.Tweet{root}, thread.Ancestors)
+	assert.Equal(t, reply, thread.Root.Tweet)
+	assert.Len(t, thread.Root.Replies, 2)
+	assert.Equal(t, firstAnswer, thread.Root.Replies[0].Tweet)
+	assert.Equal(t, secondAnswer, thread.Root.Replies[1].Tweet)
+	assert.Empty(t, thread.Root.Replies[0].Replies)
 }
 
 func TestPostTweet_NewTweetFails(t *testing.T) {
@@ -186,19 +273,15 @@ func TestPostTweet_NewTweetFails(t *testing.T) {
 	logger := log.Default()
 
 	mockRepo := new(MockTweetRepository)
-	mockProducer := new(MockEventProducer)
-	mockDLQ := new(MockDeadLetterQueue)
 
-	tweetService := services.NewTweetService(mockRepo, mockProducer, mockDLQ, logger)
+	tweetService := services.NewTweetService(mockRepo, nil, nil, nil, nil, logger)
 
-	err := tweetService.PostTweet(ctx, userID, invalidContent)
+	_, err := tweetService.PostTweet(ctx, userID, invalidContent, "", "")
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "tweet content is empty")
 
-	mockRepo.AssertNotCalled(t, "Save")
-	mockProducer.AssertNotCalled(t, "PublishEvent")
-	mockDLQ.AssertNotCalled(t, "StoreEvent")
+	mockRepo.AssertNotCalled(t, "SaveWithEvent")
 }
 
 func TestPostTweet_NewTeetExceedsLength(t *testing.T) {
@@ -211,19 +294,15 @@ func TestPostTweet_NewTeetExceedsLength(t *testing.T) {
 	logger := log.Default()
 
 	mockRepo := new(MockTweetRepository)
-	mockProducer := new(MockEventProducer)
-	mockDLQ := new(MockDeadLetterQueue)
 
-	tweetService := services.NewTweetService(mockRepo, mockProducer, mockDLQ, logger)
+	tweetService := services.NewTweetService(mockRepo, nil, nil, nil, nil, logger)
 
-	err := tweetService.PostTweet(ctx, userID, invalidContent)
+	_, err := tweetService.PostTweet(ctx, userID, invalidContent, "", "")
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "tweet content is too long")
 
-	mockRepo.AssertNotCalled(t, "Save")
-	mockProducer.AssertNotCalled(t, "PublishEvent")
-	mockDLQ.AssertNotCalled(t, "StoreEvent")
+	mockRepo.AssertNotCalled(t, "SaveWithEvent")
 }
 
 func TestPostTweet_SaveFails(t *testing.T) {
@@ -233,18 +312,509 @@ func TestPostTweet_SaveFails(t *testing.T) {
 	logger := log.Default()
 
 	mockRepo := new(MockTweetRepository)
-	mockProducer := new(MockEventProducer)
-	mockDLQ := new(MockDeadLetterQueue)
 
-	mockRepo.On("Save", mock.Anything, mock.Anything).Return(errors.New("db error"))
+	mockRepo.On("SaveWithEvent", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("db error"))
 
-	tweetService := services.NewTweetService(mockRepo, mockProducer, mockDLQ, logger)
+	tweetService := services.NewTweetService(mockRepo, nil, nil, nil, nil, logger)
 
-	err := tweetService.PostTweet(ctx, userID, content)
+	_, err := tweetService.PostTweet(ctx, userID, content, "", "")
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "error saving tweet")
+}
+
+func TestPostTweet_IdempotencyKey(t *testing.T) {
+	ctx := context.Background()
+	fingerprint := domain.TweetRequestFingerprint("Hello, world!", "")
+
+	mockRepo := new(MockTweetRepository)
+	mockIdempotency := new(MockIdempotencyRepository)
+
+	var savedTweet *domain.Tweet
+	mockRepo.On("SaveWithEvent", ctx, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			savedTweet = args.Get(1).(*domain.Tweet)
+		}).
+		Return(nil)
+	mockIdempotency.On("Reserve", ctx, "user123:key1", fingerprint, time.Minute).Return("", nil)
+	mockIdempotency.On("Complete", ctx, "user123:key1", fingerprint, mock.Anything, 24*time.Hour).Return(nil)
+
+	tweetService := services.NewTweetService(mockRepo, nil, nil, nil, mockIdempotency, log.Default())
+
+	tweet, err := tweetService.PostTweet(ctx, "user123", "Hello, world!", "", "key1")
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockIdempotency.AssertExpectations(t)
+	assert.Same(t, savedTweet, tweet)
+
+	// La key queda asociada al tweet creado
+	assert.Equal(t, tweet.ID, mockIdempotency.Calls[1].Arguments.String(3))
+}
+
+func TestPostTweet_IdempotencyKeyReplay(t *testing.T) {
+	ctx := context.Background()
+	fingerprint := domain.TweetRequestFingerprint("Hello, world!", "")
+
+	mockRepo := new(MockTweetRepository)
+	mockIdempotency := new(MockIdempotencyRepository)
+	original := &domain.Tweet{ID: "tweet1", UserID: "user123", Content: "Hello, world!"}
+	mockRepo.On("GetByID", ctx, "tweet1").Return(original, nil)
+	mockIdempotency.On("Reserve", ctx, "user123:key1", fingerprint, time.Minute).Return("tweet1", nil)
+
+	tweetService := services.NewTweetService(mockRepo, nil, nil, nil, mockIdempotency, log.Default())
+
+	// El reintento devuelve el tweet del pedido original sin crear otro
+	tweet, err := tweetService.PostTweet(ctx, "user123", "Hello, world!", "", "key1")
+	assert.NoError(t, err)
+	assert.Equal(t, original, tweet)
+	mockRepo.AssertNotCalled(t, "SaveWithEvent", mock.Anything, mock.Anything, mock.Anything)
+	mockIdempotency.AssertNotCalled(t, "Complete", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPostTweet_IdempotencyKeyInUse(t *testing.T) {
+	ctx := context.Background()
+	fingerprint := domain.TweetRequestFingerprint("Hello, world!", "")
+
+	mockRepo := new(MockTweetRepository)
+	mockIdempotency := new(MockIdempotencyRepository)
+	mockIdempotency.On("Reserve", ctx, "user123:key1", fingerprint, time.Minute).Return("", domain.ErrIdempotencyKeyInUse)
+
+	tweetService := services.NewTweetService(mockRepo, nil, nil, nil, mockIdempotency, log.Default())
+
+	_, err := tweetService.PostTweet(ctx, "user123", "Hello, world!", "", "key1")
+	assert.ErrorIs(t, err, domain.ErrIdempotencyKeyInUse)
+	mockRepo.AssertNotCalled(t, "SaveWithEvent", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPostTweet_IdempotencyKeyReleasedOnFailure(t *testing.T) {
+	ctx := context.Background()
+	fingerprint := domain.TweetRequestFingerprint("Hello, world!", "")
+
+	mockRepo := new(MockTweetRepository)
+	mockIdempotency := new(MockIdempotencyRepository)
+	mockRepo.On("SaveWithEvent", ctx, mock.Anything, mock.Anything).Return(errors.New("database error"))
+	mockIdempotency.On("Reserve", ctx, "user123:key1", fingerprint, time.Minute).Return("", nil)
+	mockIdempotency.On("Release", ctx, "user123:key1").Return(nil)
+
+	tweetService := services.NewTweetService(mockRepo, nil, nil, nil, mockIdempotency, log.Default())
+
+	// Si el tweet no se pudo guardar, el cliente puede reintentar con la misma key
+	_, err := tweetService.PostTweet(ctx, "user123", "Hello, world!", "", "key1")
+	assert.Error(t, err)
+	mockIdempotency.AssertExpectations(t)
+	mockIdempotency.AssertNotCalled(t, "Complete", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPostTweet_IdempotencyKeyMismatch(t *testing.T) {
+	ctx := context.Background()
+
+	mockRepo := new(MockTweetRepository)
+	mockIdempotency := new(MockIdempotencyRepository)
+	mockIdempotency.On("Reserve", ctx, "user123:key1", domain.TweetRequestFingerprint("Other", ""), time.Minute).
+		Return("", domain.ErrIdempotencyKeyMismatch)
+
+	tweetService := services.NewTweetService(mockRepo, nil, nil, nil, mockIdempotency, log.Default())
+
+	// Reusar la key con otro contenido no devuelve el tweet original
+	_, err := tweetService.PostTweet(ctx, "user123", "Other", "", "key1")
+	assert.ErrorIs(t, err, domain.ErrIdempotencyKeyMismatch)
+	mockRepo.AssertNotCalled(t, "SaveWithEvent", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPostTweet_IdempotencyKeyCompleteFails(t *testing.T) {
+	ctx := context.Background()
+	fingerprint := domain.TweetRequestFingerprint("Hello, world!", "")
+
+	mockRepo := new(MockTweetRepository)
+	mockIdempotency := new(MockIdempotencyRepository)
+	mockRepo.On("SaveWithEvent", ctx, mock.Anything, mock.Anything).Return(nil)
+	mockIdempotency.On("Reserve", ctx, "user123:key1", fingerprint, time.Minute).Return("", nil)
+	mockIdempotency.On("Complete", ctx, "user123:key1", fingerprint, mock.Anything, 24*time.Hour).Return(errors.New("redis error"))
+
+	tweetService := services.NewTweetService(mockRepo, nil, nil, nil, mockIdempotency, log.Default())
+
+	// Si la key no queda asociada al tweet se avisa en vez de responder como si nada
+	_, err := tweetService.PostTweet(ctx, "user123", "Hello, world!", "", "key1")
+	assert.Error(t, err)
+	mockIdempotency.AssertNotCalled(t, "Release", mock.Anything, mock.Anything)
+}
+
+func TestPostTweet_IdempotencyKeyTooLong(t *testing.T) {
+	ctx := context.Background()
+
+	tweetService := services.NewTweetService(new(MockTweetRepository), nil, nil, nil, new(MockIdempotencyRepository), log.Default())
+
+	_, err := tweetService.PostTweet(ctx, "user123", "Hello, world!", "", strings.Repeat("k", domain.MaxIdempotencyKeyLength+1))
+	assert.ErrorIs(t, err, domain.ErrInvalidIdempotencyKey)
+}
+
+func TestGetTweet_NotFound(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockTweetRepository)
+	mockRepo.On("GetByID", ctx, "tweet1").Return(nil, domain.ErrTweetNotFound)
+
+	tweetService := services.NewTweetService(mockRepo, nil, nil, nil, nil, log.Default())
+
+	_, err := tweetService.GetTweet(ctx, "tweet1")
+	assert.ErrorIs(t, err, domain.ErrTweetNotFound)
+}
+
+func TestDeleteTweet_Success(t *testing.T) {
+	ctx := context.Background()
+	tweet := &domain.Tweet{ID: "tweet1", UserID: "user123", Content: "Hello, world!"}
+
+	mockRepo := new(MockTweetRepository)
+	mockRepo.On("GetByID", ctx, "tweet1").Return(tweet, nil)
+	mockRepo.On("ListRetweets", ctx, "tweet1").Return(nil, nil)
+
+	var savedEvent *domain.OutboxEvent
+	mockRepo.On("DeleteWithEvent", ctx, "tweet1", mock.Anything).
+		Run(func(args mock.Arguments) {
+			savedEvent = args.Get(2).(*domain.OutboxEvent)
+		}).
+		Return(nil)
+
+	tweetService := services.NewTweetService(mockRepo, nil, nil, nil, nil, log.Default())
+
+	err := tweetService.DeleteTweet(ctx, "user123", "tweet1")
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+
+	// El evento lleva el tweet completo para que el consumer sepa de quién son los seguidores
+	assert.Equal(t, domain.EventTweetDeleted, savedEvent.EventType)
+	assert.Equal(t, "user123", savedEvent.Key)
+	event, err := domain.DecodeTweetEvent(savedEvent.Payload)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.EventTweetDeleted, event.Type)
+	assert.Equal(t, tweet, event.Tweet)
+}
+
+func TestDeleteTweet_DeletesRetweets(t *testing.T) {
+	ctx := context.Background()
+	tweet := &domain.Tweet{ID: "tweet1", UserID: "user123", Content: "Hello, world!"}
+	retweets := []*domain.Tweet{domain.NewRetweet("user456", tweet), domain.NewRetweet("user789", tweet)}
+
+	mockRepo := new(MockTweetRepository)
+	mockRepo.On("GetByID", ctx, "tweet1").Return(tweet, nil)
+	mockRepo.On("ListRetweets", ctx, "tweet1").Return(retweets, nil)
+	// Un retweet que ya se borró no corta el borrado
+	mockRepo.On("DeleteWithEvent", ctx, retweets[0].ID, mock.Anything).Return(domain.ErrTweetNotFound)
+	mockRepo.On("DeleteWithEvent", ctx, retweets[1].ID, mock.Anything).Return(nil)
+	mockRepo.On("DeleteWithEvent", ctx, "tweet1", mock.Anything).Return(nil)
+
+	tweetService := services.NewTweetService(mockRepo, nil, nil, nil, nil, log.Default())
+
+	assert.NoError(t, tweetService.DeleteTweet(ctx, "user123", "tweet1"))
+	mockRepo.AssertExpectations(t)
+
+	// Cada retweet se saca de los timelines con su propio evento
+	event, err := domain.DecodeTweetEvent(mockRepo.Calls[3].Arguments.Get(2).(*domain.OutboxEvent).Payload)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.EventTweetDeleted, event.Type)
+	assert.Equal(t, retweets[1].ID, event.Tweet.ID)
+}
+
+func TestDeleteTweet_RetweetsError(t *testing.T) {
+	ctx := context.Background()
+	tweet := &domain.Tweet{ID: "tweet1", UserID: "user123", Content: "Hello, world!"}
+
+	mockRepo := new(MockTweetRepository)
+	mockRepo.On("GetByID", ctx, "tweet1").Return(tweet, nil)
+	mockRepo.On("ListRetweets", ctx, "tweet1").Return(nil, errors.New("db error"))
+
+	tweetService := services.NewTweetService(mockRepo, nil, nil, nil, nil, log.Default())
+
+	// Si no se pueden borrar los retweets el original queda, para poder reintentar
+	assert.Error(t, tweetService.DeleteTweet(ctx, "user123", "tweet1"))
+	mockRepo.AssertNotCalled(t, "DeleteWithEvent", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDeleteTweet_NotFound(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockTweetRepository)
+	mockRepo.On("GetByID", ctx, "tweet1").Return(nil, domain.ErrTweetNotFound)
+
+	tweetService := services.NewTweetService(mockRepo, nil, nil, nil, nil, log.Default())
+
+	err := tweetService.DeleteTweet(ctx, "user123", "tweet1")
+	assert.ErrorIs(t, err, domain.ErrTweetNotFound)
+	mockRepo.AssertNotCalled(t, "DeleteWithEvent", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDeleteTweet_NotOwner(t *testing.T) {
+	ctx := context.Background()
+	tweet := &domain.Tweet{ID: "tweet1", UserID: "user123", Content: "Hello, world!"}
+
+	mockRepo := new(MockTweetRepository)
+	mockRepo.On("GetByID", ctx, "tweet1").Return(tweet, nil)
+
+	tweetService := services.NewTweetService(mockRepo, nil, nil, nil, nil, log.Default())
+
+	err := tweetService.DeleteTweet(ctx, "user456", "tweet1")
+	assert.ErrorIs(t, err, domain.ErrNotTweetOwner)
+	mockRepo.AssertNotCalled(t, "DeleteWithEvent", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestEditTweet_Success(t *testing.T) {
+	ctx := context.Background()
+	createdAt := time.Now().UTC().Add(-time.Minute)
+	tweet := &domain.Tweet{ID: "tweet1", UserID: "user123", Content: "Helo, world!", CreatedAt: createdAt}
+
+	mockRepo := new(MockTweetRepository)
+	mockRepo.On("GetByID", ctx, "tweet1").Return(tweet, nil)
+	mockRepo.On("ListRetweets", ctx, "tweet1").Return(nil, nil)
+
+	var savedEvent *domain.OutboxEvent
+	mockRepo.On("UpdateWithEvent", ctx, tweet, mock.Anything).
+		Run(func(args mock.Arguments) {
+			savedEvent = args.Get(2).(*domain.OutboxEvent)
+		}).
+		Return(nil)
+
+	tweetService := services.NewTweetService(mockRepo, nil, nil, nil, nil, log.Default())
+
+	edited, err := tweetService.EditTweet(ctx, "user123", "tweet1", "Hello, world!")
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+
+	assert.Equal(t, "Hello, world!", edited.Content)
+	assert.NotNil(t, edited.EditedAt)
+	assert.Equal(t, []domain.TweetRevision{{Content: "Helo, world!", CreatedAt: createdAt}}, edited.Revisions)
+
+	event, err := domain.DecodeTweetEvent(savedEvent.Payload)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.EventTweetEdited, event.Type)
+	assert.Equal(t, "Hello, world!", event.Tweet.Content)
+}
+
+func TestEditTweet_SyncsRetweets(t *testing.T) {
+	ctx := context.Background()
+	tweet := &domain.Tweet{ID: "tweet1", UserID: "user123", Content: "Helo, #mundo", CreatedAt: time.Now().UTC()}
+	retweet := domain.NewRetweet("user456", tweet)
+
+	mockRepo := new(MockTweetRepository)
+	mockRepo.On("GetByID", ctx, "tweet1").Return(tweet, nil)
+	mockRepo.On("UpdateWithEvent", ctx, tweet, mock.Anything).Return(nil)
+	mockRepo.On("ListRetweets", ctx, "tweet1").Return([]*domain.Tweet{retweet}, nil)
+
+	var savedEvent *domain.OutboxEvent
+	mockRepo.On("UpdateWithEvent", ctx, retweet, mock.Anything).
+		Run(func(args mock.Arguments) {
+			savedEvent = args.Get(2).(*domain.OutboxEvent)
+		}).
+		Return(nil)
+
+	tweetService := services.NewTweetService(mockRepo, nil, nil, nil, nil, log.Default())
+
+	_, err := tweetService.EditTweet(ctx, "user123", "tweet1", "Hola, #mundo")
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+
+	event, err := domain.DecodeTweetEvent(savedEvent.Payload)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.EventTweetEdited, event.Type)
+	assert.Equal(t, retweet.ID, event.Tweet.ID)
+	assert.Equal(t, "Hola, #mundo", event.Tweet.Content)
+	assert.Equal(t, []domain.Hashtag{{Tag: "mundo", Start: 6, End: 12}}, event.Tweet.Hashtags)
+}
+
+func TestEditTweet_RetrySyncsRetweets(t *testing.T) {
+	ctx := context.Background()
+	editedAt := time.Now().UTC()
+	tweet := &domain.Tweet{
+		ID:        "tweet1",
+		UserID:    "user123",
+		Content:   "Hola, mundo",
+		CreatedAt: editedAt.Add(-time.Minute),
+		EditedAt:  &editedAt,
+		Revisions: []domain.TweetRevision{{Content: "Helo, mundo", CreatedAt: editedAt.Add(-time.Minute)}},
+	}
+	// Un retweet quedó con el contenido anterior porque la edición falló a medias
+	stale := &domain.Tweet{ID: "rt1", UserID: "user456", Content: "Helo, mundo", Kind: domain.TweetKindRetweet, ReferencedTweetID: "tweet1"}
+	synced := domain.NewRetweet("user789", tweet)
+
+	mockRepo := new(MockTweetRepository)
+	mockRepo.On("GetByID", ctx, "tweet1").Return(tweet, nil)
+	mockRepo.On("ListRetweets", ctx, "tweet1").Return([]*domain.Tweet{stale, synced}, nil)
+	mockRepo.On("UpdateWithEvent", ctx, stale, mock.Anything).Return(nil)
+
+	tweetService := services.NewTweetService(mockRepo, nil, nil, nil, nil, log.Default())
+
+	// Reintentar con el mismo contenido no guarda otra versión, solo termina de copiarlo
+	edited, err := tweetService.EditTweet(ctx, "user123", "tweet1", "Hola, mundo")
+	assert.NoError(t, err)
+	assert.Len(t, edited.Revisions, 1)
+	assert.Equal(t, "Hola, mundo", stale.Content)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNumberOfCalls(t, "UpdateWithEvent", 1)
+}
+
+func TestEditTweet_ReparsesEntities(t *testing.T) {
+	ctx := context.Background()
+	tweet := &domain.Tweet{
+		ID:        "tweet1",
+		UserID:    "user123",
+		Content:   "Hola #viejo",
+		CreatedAt: time.Now().UTC(),
+		Hashtags:  []domain.Hashtag{{Tag: "viejo", Start: 5, End: 11}},
+	}
+
+	mockRepo := new(MockTweetRepository)
+	mockRepo.On("GetByID", ctx, "tweet1").Return(tweet, nil)
+	mockRepo.On("UpdateWithEvent", ctx, tweet, mock.Anything).Return(nil)
+	mockRepo.On("ListRetweets", ctx, "tweet1").Return(nil, nil)
+
+	tweetService := services.NewTweetService(mockRepo, nil, nil, nil, nil, log.Default())
+
+	edited, err := tweetService.EditTweet(ctx, "user123", "tweet1", "Hola #nuevo")
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Hashtag{{Tag: "nuevo", Start: 5, End: 11}}, edited.Hashtags)
+}
+
+func TestEditTweet_NotifiesNewMentions(t *testing.T) {
+	ctx := context.Background()
+	tweet := &domain.Tweet{
+		ID:        "tweet1",
+		UserID:    "user123",
+		Content:   "Hola @ana",
+		CreatedAt: time.Now().UTC(),
+		Mentions:  []domain.Mention{{UserID: "ana", Start: 5, End: 9}},
+	}
+
+	mockRepo := new(MockTweetRepository)
+	mockUserRepo := new(MockUserRepository)
+	mockProducer := new(MockEventProducer)
+	mockRepo.On("GetByID", ctx, "tweet1").Return(tweet, nil)
+	mockRepo.On("UpdateWithEvent", ctx, tweet, mock.Anything).Return(nil)
+	mockRepo.On("ListRetweets", ctx, "tweet1").Return(nil, nil)
+	for _, handle := range []string{"ana", "beto", "user123"} {
+		mockUserRepo.On("GetByHandle", ctx, handle).Return(&domain.User{ID: handle, Handle: handle}, nil)
+	}
+	mockProducer.On("PublishEvents", ctx, mock.Anything).Return(nil)
+
+	tweetService := services.NewTweetService(mockRepo, nil, mockUserRepo, mockProducer, nil, log.Default())
+
+	// ana ya había sido notificada al publicar el tweet
+	_, err := tweetService.EditTweet(ctx, "user123", "tweet1", "Hola @ana y @beto")
+	assert.NoError(t, err)
+	published := mockProducer.published(t)
+	if assert.Len(t, published, 1) {
+		assert.Equal(t, "beto", published[0].UserID)
+	}
+}
+
+func TestEditTweet_NotOwner(t *testing.T) {
+	ctx := context.Background()
+	tweet := &domain.Tweet{ID: "tweet1", UserID: "user123", Content: "Helo, world!", CreatedAt: time.Now().UTC()}
+
+	mockRepo := new(MockTweetRepository)
+	mockRepo.On("GetByID", ctx, "tweet1").Return(tweet, nil)
+
+	tweetService := services.NewTweetService(mockRepo, nil, nil, nil, nil, log.Default())
+
+	_, err := tweetService.EditTweet(ctx, "user456", "tweet1", "Hello, world!")
+	assert.ErrorIs(t, err, domain.ErrNotTweetOwner)
+	assert.Equal(t, "Helo, world!", tweet.Content)
+	mockRepo.AssertNotCalled(t, "UpdateWithEvent", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestEditTweet_WindowExpired(t *testing.T) {
+	ctx := context.Background()
+	tweet := &domain.Tweet{
+		ID:        "tweet1",
+		UserID:    "user123",
+		Content:   "Helo, world!",
+		CreatedAt: time.Now().UTC().Add(-domain.TweetEditWindow - time.Minute),
+	}
+
+	mockRepo := new(MockTweetRepository)
+	mockRepo.On("GetByID", ctx, "tweet1").Return(tweet, nil)
+
+	tweetService := services.NewTweetService(mockRepo, nil, nil, nil, nil, log.Default())
+
+	_, err := tweetService.EditTweet(ctx, "user123", "tweet1", "Hello, world!")
+	assert.ErrorIs(t, err, domain.ErrEditWindowExpired)
+	mockRepo.AssertNotCalled(t, "UpdateWithEvent", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestEditTweet_LimitReached(t *testing.T) {
+	ctx := context.Background()
+	tweet := &domain.Tweet{ID: "tweet1", UserID: "user123", Content: "v0", CreatedAt: time.Now().UTC()}
+	for i := 1; i <= domain.MaxTweetEdits; i++ {
+		assert.NoError(t, tweet.Edit(fmt.Sprintf("v%d", i), time.Now()))
+	}
+
+	mockRepo := new(MockTweetRepository)
+	mockRepo.On("GetByID", ctx, "tweet1").Return(tweet, nil)
+
+	tweetService := services.NewTweetService(mockRepo, nil, nil, nil, nil, log.Default())
+
+	_, err := tweetService.EditTweet(ctx, "user123", "tweet1", "one more")
+	assert.ErrorIs(t, err, domain.ErrEditLimitReached)
+	assert.Len(t, tweet.Revisions, domain.MaxTweetEdits)
+	assert.Equal(t, "v0", tweet.Revisions[0].Content)
+}
+
+func TestRetweet_OfRetweetReferencesOriginal(t *testing.T) {
+	ctx := context.Background()
+	original := &domain.Tweet{ID: "tweet1", UserID: "user1", Content: "Hello", Kind: domain.TweetKindOriginal}
+	retweet := &domain.Tweet{ID: "tweet2", UserID: "user2", Content: "Hello", Kind: domain.TweetKindRetweet, ReferencedTweetID: "tweet1"}
+
+	mockRepo := new(MockTweetRepository)
+	mockRepo.On("GetByID", ctx, "tweet2").Return(retweet, nil)
+	mockRepo.On("GetByID", ctx, "tweet1").Return(original, nil)
+
+	var savedTweet *domain.Tweet
+	mockRepo.On("SaveWithEvent", ctx, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			savedTweet = args.Get(1).(*domain.Tweet)
+		}).
+		Return(nil)
+
+	tweetService := services.NewTweetService(mockRepo, nil, nil, nil, nil, log.Default())
+
+	err := tweetService.Retweet(ctx, "user3", "tweet2", "")
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+
+	assert.Equal(t, "user3", savedTweet.UserID)
+	assert.Equal(t, domain.TweetKindRetweet, savedTweet.Kind)
+	assert.Equal(t, "tweet1", savedTweet.ReferencedTweetID)
+	assert.Equal(t, "Hello", savedTweet.Content)
+}
+
+func TestRetweet_Quote(t *testing.T) {
+	ctx := context.Background()
+	original := &domain.Tweet{ID: "tweet1", UserID: "user1", Content: "Hello", Kind: domain.TweetKindOriginal}
+
+	mockRepo := new(MockTweetRepository)
+	mockRepo.On("GetByID", ctx, "tweet1").Return(original, nil)
+
+	var savedTweet *domain.Tweet
+	mockRepo.On("SaveWithEvent", ctx, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			savedTweet = args.Get(1).(*domain.Tweet)
+		}).
+		Return(nil)
+
+	tweetService := services.NewTweetService(mockRepo, nil, nil, nil, nil, log.Default())
+
+	err := tweetService.Retweet(ctx, "user2", "tweet1", "So true")
+	assert.NoError(t, err)
+
+	assert.Equal(t, domain.TweetKindQuote, savedTweet.Kind)
+	assert.Equal(t, "tweet1", savedTweet.ReferencedTweetID)
+	assert.Equal(t, "So true", savedTweet.Content)
+}
+
+func TestRetweet_NotFound(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockTweetRepository)
+	mockRepo.On("GetByID", ctx, "tweet1").Return(nil, domain.ErrTweetNotFound)
+
+	tweetService := services.NewTweetService(mockRepo, nil, nil, nil, nil, log.Default())
 
-	mockProducer.AssertNotCalled(t, "PublishEvent")
-	mockDLQ.AssertNotCalled(t, "StoreEvent")
+	err := tweetService.Retweet(ctx, "user2", "tweet1", "")
+	assert.ErrorIs(t, err, domain.ErrTweetNotFound)
+	mockRepo.AssertNotCalled(t, "SaveWithEvent", mock.Anything, mock.Anything, mock.Anything)
 }
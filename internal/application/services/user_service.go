@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"ChallengeUALA/internal/application/ports"
+	"ChallengeUALA/internal/domain"
+)
+
+// UserService maneja el registro y los perfiles de los usuarios.
+type UserService struct {
+	userRepo  ports.UserRepository
+	passwords ports.PasswordHasher
+}
+
+// NewUserService crea una nueva instancia de UserService
+func NewUserService(userRepo ports.UserRepository, passwords ports.PasswordHasher) *UserService {
+	return &UserService{
+		userRepo:  userRepo,
+		passwords: passwords,
+	}
+}
+
+// Register crea un usuario con un handle único y la contraseña con la que va a iniciar sesión.
+// Devuelve domain.ErrInvalidUser si el perfil o la contraseña no son válidos y
+// domain.ErrHandleTaken si otro usuario ya usa el handle.
+func (s *UserService) Register(ctx context.Context, handle, displayName, bio, password string) (*domain.User, error) {
+	user, err := domain.NewUser(handle, displayName, bio)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := domain.ValidatePassword(password); err != nil {
+		return nil, err
+	}
+
+	user.PasswordHash, err = s.passwords.Hash(password)
+	if err != nil {
+		return nil, fmt.Errorf("error in calling passwords.Hash: %w", err)
+	}
+
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, fmt.Errorf("error in calling userRepo.Create: %w", err)
+	}
+
+	return user, nil
+}
+
+// GetUser devuelve un usuario por su ID, o domain.ErrUserNotFound si no existe.
+func (s *UserService) GetUser(ctx context.Context, userID string) (*domain.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error in calling userRepo.GetByID: %w", err)
+	}
+
+	return user, nil
+}
+
+// GetUserByHandle devuelve el usuario con ese handle, o domain.ErrUserNotFound si no existe.
+func (s *UserService) GetUserByHandle(ctx context.Context, handle string) (*domain.User, error) {
+	user, err := s.userRepo.GetByHandle(ctx, handle)
+	if err != nil {
+		return nil, fmt.Errorf("error in calling userRepo.GetByHandle: %w", err)
+	}
+
+	return user, nil
+}
@@ -0,0 +1,88 @@
+package services_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"ChallengeUALA/internal/application/services"
+	"ChallengeUALA/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRegister_Success(t *testing.T) {
+	ctx := context.Background()
+	mockUserRepo := new(MockUserRepository)
+	mockHasher := new(MockPasswordHasher)
+	service := services.NewUserService(mockUserRepo, mockHasher)
+
+	mockHasher.On("Hash", "secreto123").Return("hash", nil)
+	mockUserRepo.On("Create", ctx, mock.Anything).Return(nil)
+
+	user, err := service.Register(ctx, "@ana_dev", "", "Hola", "secreto123")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, user.ID)
+	assert.Equal(t, "ana_dev", user.Handle)
+	// Sin display name se muestra el handle
+	assert.Equal(t, "ana_dev", user.DisplayName)
+	// Se guarda el hash, nunca la contraseña
+	assert.Equal(t, "hash", user.PasswordHash)
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestRegister_HandleTaken(t *testing.T) {
+	ctx := context.Background()
+	mockUserRepo := new(MockUserRepository)
+	mockHasher := new(MockPasswordHasher)
+	service := services.NewUserService(mockUserRepo, mockHasher)
+
+	mockHasher.On("Hash", "secreto123").Return("hash", nil)
+	mockUserRepo.On("Create", ctx, mock.Anything).Return(domain.ErrHandleTaken)
+
+	_, err := service.Register(ctx, "ana", "Ana", "", "secreto123")
+	assert.ErrorIs(t, err, domain.ErrHandleTaken)
+}
+
+func TestRegister_InvalidProfile(t *testing.T) {
+	service := services.NewUserService(new(MockUserRepository), new(MockPasswordHasher))
+
+	for _, handle := range []string{"", "@", "ana dev", "ana-dev", strings.Repeat("a", domain.MaxHandleLength+1)} {
+		_, err := service.Register(context.Background(), handle, "", "", "secreto123")
+		assert.ErrorIs(t, err, domain.ErrInvalidUser, handle)
+	}
+
+	_, err := service.Register(context.Background(), "ana", strings.Repeat("a", domain.MaxDisplayNameLength+1), "", "secreto123")
+	assert.ErrorIs(t, err, domain.ErrInvalidUser)
+
+	_, err = service.Register(context.Background(), "ana", "", strings.Repeat("a", domain.MaxBioLength+1), "secreto123")
+	assert.ErrorIs(t, err, domain.ErrInvalidUser)
+
+	for _, password := range []string{"", "corta", strings.Repeat("a", domain.MaxPasswordLength+1)} {
+		_, err = service.Register(context.Background(), "ana", "", "", password)
+		assert.ErrorIs(t, err, domain.ErrInvalidUser, password)
+	}
+}
+
+func TestGetUser(t *testing.T) {
+	ctx := context.Background()
+	mockUserRepo := new(MockUserRepository)
+	service := services.NewUserService(mockUserRepo, nil)
+
+	user := &domain.User{ID: "user1", Handle: "ana"}
+	mockUserRepo.On("GetByID", ctx, "user1").Return(user, nil)
+	mockUserRepo.On("GetByID", ctx, "user2").Return(nil, domain.ErrUserNotFound)
+	mockUserRepo.On("GetByHandle", ctx, "@ana").Return(user, nil)
+
+	found, err := service.GetUser(ctx, "user1")
+	assert.NoError(t, err)
+	assert.Equal(t, user, found)
+
+	_, err = service.GetUser(ctx, "user2")
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+
+	found, err = service.GetUserByHandle(ctx, "@ana")
+	assert.NoError(t, err)
+	assert.Equal(t, user, found)
+}
@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// Mention es una mención a un usuario dentro del contenido de un tweet. Handle es el texto
+// después del '@' y UserID el usuario con ese handle, que se resuelve al guardar el tweet.
+// Start y End son las posiciones en bytes de "@handle" dentro de Content (End no incluido),
+// para poder resaltarla.
+type Mention struct {
+	UserID string
+	Handle string `json:",omitempty"`
+	Start  int
+	End    int
+}
+
+// Hashtag es un hashtag dentro del contenido de un tweet. Tag se guarda sin el '#' y en
+// minúsculas para que #Go y #go sean el mismo; Start y End son como en Mention.
+type Hashtag struct {
+	Tag   string
+	Start int
+	End   int
+}
+
+// ParseEntities extrae las menciones y los hashtags de content en el orden en que aparecen.
+// Solo se reconocen al principio del texto o después de un carácter que no forma parte de una
+// palabra, así "mail@dominio" no es una mención. Un hashtag necesita al menos una letra.
+// Las menciones vuelven solo con Handle: buscar el usuario es responsabilidad de quien llama.
+func ParseEntities(content string) ([]Mention, []Hashtag) {
+	var mentions []Mention
+	var hashtags []Hashtag
+
+	for i := 0; i < len(content); {
+		sigil := content[i]
+		if (sigil != '@' && sigil != '#') || !startsEntity(content, i) {
+			_, size := utf8.DecodeRuneInString(content[i:])
+			i += size
+			continue
+		}
+
+		end := i + 1
+		hasLetter := false
+		for end < len(content) {
+			r, size := utf8.DecodeRuneInString(content[end:])
+			if !isWordRune(r) {
+				break
+			}
+			hasLetter = hasLetter || unicode.IsLetter(r)
+			end += size
+		}
+
+		name := content[i+1 : end]
+		switch {
+		case name == "":
+		case sigil == '@':
+			mentions = append(mentions, Mention{Handle: name, Start: i, End: end})
+		case hasLetter:
+			hashtags = append(hashtags, Hashtag{Tag: NormalizeHashtag(name), Start: i, End: end})
+		}
+
+		i = end
+		if name == "" {
+			i++
+		}
+	}
+
+	return mentions, hashtags
+}
+
+// MentionedUserIDs devuelve los usuarios mencionados en el tweet sin repetir
+func (t *Tweet) MentionedUserIDs() []string {
+	seen := make(map[string]bool, len(t.Mentions))
+	var userIDs []string
+	for _, mention := range t.Mentions {
+		if !seen[mention.UserID] {
+			seen[mention.UserID] = true
+			userIDs = append(userIDs, mention.UserID)
+		}
+	}
+	return userIDs
+}
+
+// startsEntity indica si el '@' o '#' en la posición i puede empezar una mención o un hashtag
+func startsEntity(content string, i int) bool {
+	if i == 0 {
+		return true
+	}
+	r, _ := utf8.DecodeLastRuneInString(content[:i])
+	return !isWordRune(r) && r != '@' && r != '#'
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
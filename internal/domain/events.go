@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Tipos de eventos de tweets que se publican en Kafka
+const (
+	EventTweetCreated = "tweet_created"
+	EventTweetEdited  = "tweet_edited"
+	EventTweetDeleted = "tweet_deleted"
+)
+
+// TweetEvent es el mensaje que se publica en el tópico de tweets
+type TweetEvent struct {
+	Type  string `json:"type"`
+	Tweet *Tweet `json:"tweet"`
+}
+
+func NewTweetEvent(eventType string, tweet *Tweet) *TweetEvent {
+	return &TweetEvent{
+		Type:  eventType,
+		Tweet: tweet,
+	}
+}
+
+// DecodeTweetEvent decodifica un mensaje del tópico de tweets. Los mensajes viejos (y los que
+// reprocesa la DLQ desde antes del cambio) son el tweet serializado sin envoltorio, y se
+// interpretan como tweet_created.
+func DecodeTweetEvent(data []byte) (*TweetEvent, error) {
+	var event TweetEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, fmt.Errorf("error unmarshalling tweet event: %w", err)
+	}
+
+	if event.Tweet != nil {
+		return &event, nil
+	}
+
+	var tweet Tweet
+	if err := json.Unmarshal(data, &tweet); err != nil {
+		return nil, fmt.Errorf("error unmarshalling tweet: %w", err)
+	}
+
+	if tweet.ID == "" {
+		return nil, fmt.Errorf("message is not a tweet event")
+	}
+
+	return NewTweetEvent(EventTweetCreated, &tweet), nil
+}
@@ -0,0 +1,15 @@
+package domain
+
+import "errors"
+
+// ErrNotFollowing se devuelve al dejar de seguir a un usuario que no se sigue
+var ErrNotFollowing = errors.New("not following user")
+
+// FollowList es una página de los seguidores o seguidos de un usuario. Count es el total
+// de la lista completa y NextCursor viene vacío en la última página.
+type FollowList struct {
+	UserID     string   `json:"user_id"`
+	Users      []string `json:"users"`
+	Count      int      `json:"count"`
+	NextCursor string   `json:"next_cursor"`
+}
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+)
+
+// MaxIdempotencyKeyLength es el largo máximo del header Idempotency-Key
+const MaxIdempotencyKeyLength = 255
+
+var (
+	// ErrInvalidIdempotencyKey se devuelve cuando la Idempotency-Key supera MaxIdempotencyKeyLength
+	ErrInvalidIdempotencyKey = errors.New("invalid idempotency key")
+	// ErrIdempotencyKeyInUse se devuelve cuando llega un reintento mientras el pedido original con la
+	// misma Idempotency-Key todavía se está procesando
+	ErrIdempotencyKeyInUse = errors.New("a request with this idempotency key is in progress")
+	// ErrIdempotencyKeyMismatch se devuelve cuando se reusa una Idempotency-Key con otro contenido
+	ErrIdempotencyKeyMismatch = errors.New("idempotency key was already used for a different request")
+)
+
+// TweetRequestFingerprint identifica el pedido de un tweet nuevo, para que una Idempotency-Key solo
+// se pueda repetir con el mismo contenido y el mismo tweet padre.
+func TweetRequestFingerprint(content, inReplyToID string) string {
+	sum := sha256.Sum256([]byte(content + "\x00" + inReplyToID))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,10 @@
+package domain
+
+// LikeList es una página de los usuarios que le dieron like a un tweet. Count es el total
+// de likes y NextCursor viene vacío en la última página.
+type LikeList struct {
+	TweetID    string   `json:"tweet_id"`
+	Users      []string `json:"users"`
+	Count      int      `json:"count"`
+	NextCursor string   `json:"next_cursor"`
+}
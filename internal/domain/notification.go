@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tipos de notificación
+const (
+	NotificationFollow  = "follow"
+	NotificationMention = "mention"
+	NotificationLike    = "like"
+	NotificationReply   = "reply"
+)
+
+// Notification le avisa a UserID que ActorID lo siguió, lo mencionó, le dio like a un tweet o le
+// respondió. TweetID es el tweet que la generó, vacío en los follows.
+type Notification struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	UserID    string    `json:"user_id"`
+	ActorID   string    `json:"actor_id"`
+	TweetID   string    `json:"tweet_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	// Read se publica siempre en false: el repositorio lo completa al listar el inbox
+	Read bool `json:"read"`
+}
+
+func NewNotification(notificationType, userID, actorID, tweetID string) *Notification {
+	return &Notification{
+		ID:        uuid.NewString(),
+		Type:      notificationType,
+		UserID:    userID,
+		ActorID:   actorID,
+		TweetID:   tweetID,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+// DecodeNotification decodifica un mensaje del tópico de notificaciones
+func DecodeNotification(data []byte) (*Notification, error) {
+	var notification Notification
+	if err := json.Unmarshal(data, &notification); err != nil {
+		return nil, fmt.Errorf("error unmarshalling notification: %w", err)
+	}
+
+	if notification.ID == "" || notification.UserID == "" || notification.Type == "" {
+		return nil, fmt.Errorf("message is not a notification")
+	}
+
+	return &notification, nil
+}
+
+// NotificationList es una página del inbox de un usuario, de la notificación más nueva a la más
+// vieja. UnreadCount es el total de no leídas del inbox y NextCursor viene vacío en la última página.
+type NotificationList struct {
+	UserID        string          `json:"user_id"`
+	Notifications []*Notification `json:"notifications"`
+	UnreadCount   int             `json:"unread_count"`
+	NextCursor    string          `json:"next_cursor"`
+}
@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutboxEvent es un evento pendiente de publicar que se guarda junto con el cambio que lo origina
+type OutboxEvent struct {
+	ID        string
+	EventType string
+	Key       string
+	Payload   []byte
+	CreatedAt time.Time
+}
+
+func NewOutboxEvent(eventType, key string, payload []byte) *OutboxEvent {
+	return &OutboxEvent{
+		ID:        uuid.NewString(),
+		EventType: eventType,
+		Key:       key,
+		Payload:   payload,
+		CreatedAt: time.Now().UTC(),
+	}
+}
@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+// RateLimitDecision es el resultado de consumir un pedido de un rate limit
+type RateLimitDecision struct {
+	Allowed bool
+	Limit   int
+	// Remaining es cuántos pedidos quedan en la ventana después de este
+	Remaining int
+	// ResetAfter es cuánto falta para que se libere un lugar en la ventana
+	ResetAfter time.Duration
+}
@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ErrInvalidSearchQuery se devuelve cuando la búsqueda no tiene texto ni autor o tiene un filtro inválido
+var ErrInvalidSearchQuery = errors.New("invalid search query")
+
+// searchDateLayout es el formato de las fechas de los filtros since: y until:
+const searchDateLayout = "2006-01-02"
+
+// SearchQuery es una búsqueda de tweets. Un tweet la cumple si tiene todos los Terms, todas las
+// Phrases (palabras seguidas en ese orden) y pasa los filtros de autor y fechas.
+type SearchQuery struct {
+	Terms   []string
+	Phrases [][]string
+	// AuthorHandle es el handle de from: (sin '@') y AuthorID el ID de ese usuario, que completa
+	// quien resuelve el handle antes de buscar
+	AuthorHandle string
+	AuthorID     string
+	// Since y Until limitan la fecha de creación: desde Since inclusive hasta Until exclusive
+	Since *time.Time
+	Until *time.Time
+}
+
+// ParseSearchQuery interpreta una búsqueda con la sintaxis de Twitter: las palabras sueltas tienen
+// que estar todas, "entre comillas" busca la frase exacta, from:handle filtra por autor y
+// since:AAAA-MM-DD / until:AAAA-MM-DD por fecha de creación (until incluye el día completo).
+func ParseSearchQuery(raw string) (*SearchQuery, error) {
+	query := &SearchQuery{}
+
+	for _, part := range splitSearchQuery(raw) {
+		if part.quoted {
+			if words := Tokenize(part.text); len(words) > 0 {
+				query.Phrases = append(query.Phrases, words)
+			}
+			continue
+		}
+
+		operator, value, found := strings.Cut(part.text, ":")
+		switch {
+		case found && operator == "from":
+			query.AuthorHandle = strings.TrimPrefix(value, "@")
+			if query.AuthorHandle == "" {
+				return nil, ErrInvalidSearchQuery
+			}
+		case found && (operator == "since" || operator == "until"):
+			date, err := time.Parse(searchDateLayout, value)
+			if err != nil {
+				return nil, ErrInvalidSearchQuery
+			}
+			if operator == "since" {
+				query.Since = &date
+			} else {
+				end := date.AddDate(0, 0, 1)
+				query.Until = &end
+			}
+		default:
+			query.Terms = append(query.Terms, Tokenize(part.text)...)
+		}
+	}
+
+	if len(query.Terms) == 0 && len(query.Phrases) == 0 && query.AuthorHandle == "" {
+		return nil, ErrInvalidSearchQuery
+	}
+
+	if query.Since != nil && query.Until != nil && !query.Since.Before(*query.Until) {
+		return nil, ErrInvalidSearchQuery
+	}
+
+	return query, nil
+}
+
+// MatchesFilters indica si el tweet pasa los filtros de autor y fecha de la búsqueda
+func (q *SearchQuery) MatchesFilters(tweet *Tweet) bool {
+	if q.AuthorID != "" && tweet.UserID != q.AuthorID {
+		return false
+	}
+	if q.Since != nil && tweet.CreatedAt.Before(*q.Since) {
+		return false
+	}
+	if q.Until != nil && !tweet.CreatedAt.Before(*q.Until) {
+		return false
+	}
+	return true
+}
+
+// Tokenize separa un texto en las palabras que se indexan: en minúsculas y sin puntuación,
+// así "#Go," y "go" son la misma palabra.
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !isWordRune(r)
+	})
+}
+
+type searchPart struct {
+	text   string
+	quoted bool
+}
+
+// splitSearchQuery separa la búsqueda por espacios respetando las partes entre comillas.
+// Unas comillas sin cerrar toman el resto del texto.
+func splitSearchQuery(raw string) []searchPart {
+	var parts []searchPart
+	for {
+		raw = strings.TrimLeftFunc(raw, unicode.IsSpace)
+		if raw == "" {
+			return parts
+		}
+
+		if raw[0] == '"' {
+			phrase, rest, _ := strings.Cut(raw[1:], `"`)
+			parts = append(parts, searchPart{text: phrase, quoted: true})
+			raw = rest
+			continue
+		}
+
+		end := strings.IndexFunc(raw, func(r rune) bool { return unicode.IsSpace(r) || r == '"' })
+		if end == -1 {
+			end = len(raw)
+		}
+		parts = append(parts, searchPart{text: raw[:end]})
+		raw = raw[end:]
+	}
+}
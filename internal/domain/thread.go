@@ -0,0 +1,68 @@
+package domain
+
+import "sort"
+
+// Thread es la conversación alrededor de un tweet: los tweets a los que responde, del primero
+// de la conversación al padre directo, y el árbol de respuestas que cuelga de él.
+type Thread struct {
+	Ancestors []*Tweet   `json:"ancestors"`
+	Root      ThreadNode `json:"thread"`
+}
+
+// ThreadNode es un tweet con sus respuestas, de la más vieja a la más nueva
+type ThreadNode struct {
+	Tweet   *Tweet        `json:"tweet"`
+	Replies []*ThreadNode `json:"replies"`
+}
+
+// BuildThread arma el hilo de tweet a partir de los tweets de su conversación. Las respuestas a
+// tweets que ya no existen quedan afuera porque no se pueden colgar del árbol.
+func BuildThread(tweet *Tweet, conversation []*Tweet) *Thread {
+	byID := make(map[string]*Tweet, len(conversation)+1)
+	replies := make(map[string][]*Tweet)
+	for _, t := range conversation {
+		byID[t.ID] = t
+	}
+	byID[tweet.ID] = tweet
+
+	for _, t := range byID {
+		if t.InReplyToID != "" {
+			replies[t.InReplyToID] = append(replies[t.InReplyToID], t)
+		}
+	}
+
+	ancestors := make([]*Tweet, 0)
+	seen := map[string]bool{tweet.ID: true}
+	for parentID := tweet.InReplyToID; parentID != "" && !seen[parentID]; {
+		parent, ok := byID[parentID]
+		if !ok {
+			break
+		}
+		seen[parentID] = true
+		ancestors = append(ancestors, parent)
+		parentID = parent.InReplyToID
+	}
+	for i, j := 0, len(ancestors)-1; i < j; i, j = i+1, j-1 {
+		ancestors[i], ancestors[j] = ancestors[j], ancestors[i]
+	}
+
+	return &Thread{
+		Ancestors: ancestors,
+		Root:      buildThreadNode(tweet, replies),
+	}
+}
+
+func buildThreadNode(tweet *Tweet, replies map[string][]*Tweet) ThreadNode {
+	children := replies[tweet.ID]
+	sort.Slice(children, func(i, j int) bool {
+		return TimelineBefore(children[j], children[i])
+	})
+
+	node := ThreadNode{Tweet: tweet, Replies: make([]*ThreadNode, 0, len(children))}
+	for _, child := range children {
+		childNode := buildThreadNode(child, replies)
+		node.Replies = append(node.Replies, &childNode)
+	}
+
+	return node
+}
@@ -1,6 +1,95 @@
 package domain
 
+import (
+	"encoding/base64"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCursor se devuelve cuando el cursor de paginación no se puede decodificar
+var ErrInvalidCursor = errors.New("invalid cursor")
+
 type Timeline struct {
 	UserID string
 	Tweets []Tweet
 }
+
+// TimelinePage es una página del timeline de un usuario. NextCursor viene vacío
+// cuando no hay más tweets para paginar.
+type TimelinePage struct {
+	Tweets     []*Tweet `json:"tweets"`
+	NextCursor string   `json:"next_cursor"`
+}
+
+// Los timelines se ordenan del tweet más nuevo al más viejo (por segundo de creación)
+// y los empates se desempatan por ID descendente. El cursor apunta al último tweet
+// devuelto, así que la página siguiente empieza en el primer tweet posterior en ese orden.
+
+// EncodeTimelineCursor arma el cursor opaco que apunta a un tweet.
+func EncodeTimelineCursor(tweet *Tweet) string {
+	raw := strconv.FormatInt(tweet.CreatedAt.Unix(), 10) + ":" + tweet.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeTimelineCursor devuelve el segundo de creación y el ID del tweet al que apunta el cursor.
+func DecodeTimelineCursor(cursor string) (int64, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, "", ErrInvalidCursor
+	}
+
+	unixPart, tweetID, found := strings.Cut(string(raw), ":")
+	if !found || tweetID == "" {
+		return 0, "", ErrInvalidCursor
+	}
+
+	unix, err := strconv.ParseInt(unixPart, 10, 64)
+	if err != nil {
+		return 0, "", ErrInvalidCursor
+	}
+
+	return unix, tweetID, nil
+}
+
+// TimelineBefore indica si el tweet a va antes que b en un timeline.
+func TimelineBefore(a, b *Tweet) bool {
+	if a.CreatedAt.Unix() != b.CreatedAt.Unix() {
+		return a.CreatedAt.Unix() > b.CreatedAt.Unix()
+	}
+	return a.ID > b.ID
+}
+
+// SortTimeline ordena los tweets en el orden del timeline.
+func SortTimeline(tweets []*Tweet) {
+	sort.SliceStable(tweets, func(i, j int) bool {
+		return TimelineBefore(tweets[i], tweets[j])
+	})
+}
+
+// PageTimeline ordena los tweets como un timeline y devuelve hasta limit a partir del cursor.
+func PageTimeline(tweets []*Tweet, cursor string, limit int) (*TimelinePage, error) {
+	SortTimeline(tweets)
+
+	start := 0
+	if cursor != "" {
+		unix, tweetID, err := DecodeTimelineCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		after := &Tweet{ID: tweetID, CreatedAt: time.Unix(unix, 0)}
+		start = sort.Search(len(tweets), func(i int) bool {
+			return TimelineBefore(after, tweets[i])
+		})
+	}
+
+	page := &TimelinePage{Tweets: tweets[start:]}
+	if len(page.Tweets) > limit {
+		page.Tweets = page.Tweets[:limit]
+		page.NextCursor = EncodeTimelineCursor(page.Tweets[len(page.Tweets)-1])
+	}
+
+	return page, nil
+}
@@ -0,0 +1,29 @@
+package domain
+
+import "strings"
+
+// Trend es un hashtag y la cantidad de veces que se usó dentro de la ventana de tendencias
+type Trend struct {
+	Hashtag string `json:"hashtag"`
+	Count   int    `json:"count"`
+}
+
+// NormalizeHashtag lleva un hashtag a la forma en que se guarda en Hashtag.Tag: sin '#' y en minúsculas
+func NormalizeHashtag(tag string) string {
+	return strings.ToLower(strings.TrimPrefix(tag, "#"))
+}
+
+// HashtagTags devuelve los hashtags de content sin repetir, en el orden en que aparecen
+func HashtagTags(content string) []string {
+	_, hashtags := ParseEntities(content)
+
+	seen := make(map[string]bool, len(hashtags))
+	var tags []string
+	for _, hashtag := range hashtags {
+		if !seen[hashtag.Tag] {
+			seen[hashtag.Tag] = true
+			tags = append(tags, hashtag.Tag)
+		}
+	}
+	return tags
+}
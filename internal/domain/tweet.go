@@ -1,23 +1,175 @@
 package domain
 
 import (
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// Tipos de tweet. Los retweets repiten el contenido del tweet original y las citas
+// (quote) tienen contenido propio; los dos apuntan al original con ReferencedTweetID.
+const (
+	TweetKindOriginal = "original"
+	TweetKindRetweet  = "retweet"
+	TweetKindQuote    = "quote"
+)
+
+// Un tweet se puede editar hasta TweetEditWindow después de publicado y como mucho MaxTweetEdits veces
+const (
+	TweetEditWindow = 30 * time.Minute
+	MaxTweetEdits   = 5
+)
+
+var (
+	// ErrTweetNotFound se devuelve cuando el tweet pedido no existe
+	ErrTweetNotFound = errors.New("tweet not found")
+	// ErrEditWindowExpired se devuelve al editar un tweet después de TweetEditWindow
+	ErrEditWindowExpired = errors.New("tweet edit window has expired")
+	// ErrEditLimitReached se devuelve al editar un tweet que ya se editó MaxTweetEdits veces
+	ErrEditLimitReached = errors.New("tweet edit limit reached")
+	// ErrParentTweetNotFound se devuelve al responder a un tweet que no existe
+	ErrParentTweetNotFound = errors.New("parent tweet not found")
+	// ErrRetweetNotEditable se devuelve al editar un retweet, que no tiene contenido propio
+	ErrRetweetNotEditable = errors.New("retweets can't be edited")
+	// ErrNotTweetOwner se devuelve al editar o borrar un tweet de otro usuario
+	ErrNotTweetOwner = errors.New("tweet belongs to another user")
+	// ErrTweetConflict se devuelve al guardar un tweet que otro pedido modificó después de leerlo
+	ErrTweetConflict = errors.New("tweet was modified concurrently")
+)
+
 type Tweet struct {
 	ID        string
 	UserID    string
 	Content   string
 	CreatedAt time.Time
+	// InReplyToID es el tweet al que responde, vacío si no es una respuesta
+	InReplyToID string `json:",omitempty"`
+	// ConversationID es el ID del primer tweet de la conversación (el propio ID si no es una respuesta)
+	ConversationID string `json:",omitempty"`
+	// Kind es uno de los TweetKind; vacío en los tweets guardados antes de que existieran los retweets
+	Kind string `json:",omitempty"`
+	// ReferencedTweetID es el tweet original de un retweet o de una cita
+	ReferencedTweetID string `json:",omitempty"`
+	// Mentions y Hashtags se extraen del contenido al publicar o editar el tweet. Solo quedan
+	// las menciones a usuarios que existen.
+	Mentions []Mention `json:",omitempty"`
+	Hashtags []Hashtag `json:",omitempty"`
+	// LikeCount no se guarda con el tweet: los servicios lo completan al devolverlo
+	LikeCount int `json:",omitempty"`
+	// EditedAt es la fecha de la última edición, nil si el tweet nunca se editó
+	EditedAt *time.Time `json:",omitempty"`
+	// Revisions son las versiones anteriores del tweet, de la más vieja a la más nueva
+	Revisions []TweetRevision `json:",omitempty"`
+	// Version cuenta las veces que se actualizó el tweet guardado; los repositorios la usan para no
+	// pisar una actualización hecha después de leerlo
+	Version int `json:"-"`
+}
+
+// TweetRevision es una versión anterior del contenido de un tweet y la fecha en que se escribió
+type TweetRevision struct {
+	Content   string
+	CreatedAt time.Time
 }
 
 func NewTweet(userID, content string) *Tweet {
+	id := uuid.NewString()
 	return &Tweet{
-		ID:        uuid.NewString(),
-		UserID:    userID,
-		Content:   content,
-		CreatedAt: time.Now().UTC(),
+		ID:             id,
+		UserID:         userID,
+		Content:        content,
+		CreatedAt:      time.Now().UTC(),
+		ConversationID: id,
+		Kind:           TweetKindOriginal,
+	}
+}
+
+// NewRetweet crea el retweet de original hecho por userID. original no puede ser a su vez un retweet.
+func NewRetweet(userID string, original *Tweet) *Tweet {
+	tweet := NewTweet(userID, original.Content)
+	tweet.Kind = TweetKindRetweet
+	tweet.ReferencedTweetID = original.ID
+	tweet.Mentions = append([]Mention(nil), original.Mentions...)
+	tweet.Hashtags = append([]Hashtag(nil), original.Hashtags...)
+	return tweet
+}
+
+// SyncRetweet copia en el retweet el contenido actual de original, para que una edición del
+// original se vea también en sus retweets. Devuelve false si el retweet ya estaba al día.
+func (t *Tweet) SyncRetweet(original *Tweet) bool {
+	if t.Content == original.Content {
+		return false
+	}
+
+	t.Content = original.Content
+	t.EditedAt = original.EditedAt
+	t.Mentions = append([]Mention(nil), original.Mentions...)
+	t.Hashtags = append([]Hashtag(nil), original.Hashtags...)
+	return true
+}
+
+// NewQuote crea una cita de quoted con el comentario content.
+func NewQuote(userID, content string, quoted *Tweet) *Tweet {
+	tweet := NewTweet(userID, content)
+	tweet.Kind = TweetKindQuote
+	tweet.ReferencedTweetID = quoted.ID
+	return tweet
+}
+
+// IsRetweet indica si el tweet es un retweet
+func (t *Tweet) IsRetweet() bool {
+	return t.Kind == TweetKindRetweet
+}
+
+// ContentID identifica el contenido que muestra el tweet en un timeline: el original en el
+// caso de los retweets y el propio tweet en el resto. Sirve para no repetir un tweet que ya
+// está en el timeline cuando llega un retweet.
+func (t *Tweet) ContentID() string {
+	if t.IsRetweet() {
+		return t.ReferencedTweetID
+	}
+	return t.ID
+}
+
+// NewReply crea un tweet que responde a parent, dentro de la misma conversación.
+func NewReply(userID, content string, parent *Tweet) *Tweet {
+	tweet := NewTweet(userID, content)
+	tweet.InReplyToID = parent.ID
+	tweet.ConversationID = parent.Conversation()
+	return tweet
+}
+
+// Conversation devuelve el ID de la conversación del tweet. Los tweets guardados antes de
+// que existieran las respuestas no tienen ConversationID y son su propia conversación.
+func (t *Tweet) Conversation() string {
+	if t.ConversationID == "" {
+		return t.ID
 	}
+	return t.ConversationID
+}
+
+// Edit reemplaza el contenido del tweet y guarda el anterior en Revisions.
+func (t *Tweet) Edit(content string, now time.Time) error {
+	if t.IsRetweet() {
+		return ErrRetweetNotEditable
+	}
+
+	if now.Sub(t.CreatedAt) > TweetEditWindow {
+		return ErrEditWindowExpired
+	}
+
+	if len(t.Revisions) >= MaxTweetEdits {
+		return ErrEditLimitReached
+	}
+
+	writtenAt := t.CreatedAt
+	if t.EditedAt != nil {
+		writtenAt = *t.EditedAt
+	}
+	t.Revisions = append(t.Revisions, TweetRevision{Content: t.Content, CreatedAt: writtenAt})
+
+	editedAt := now.UTC()
+	t.Content = content
+	t.EditedAt = &editedAt
+	return nil
 }
@@ -1,8 +1,102 @@
 package domain
 
-// User es una estructura que representa a un usuario
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Límites de los datos del perfil, en caracteres
+const (
+	MaxHandleLength      = 15
+	MaxDisplayNameLength = 50
+	MaxBioLength         = 160
+)
+
+// Límites de la contraseña, en bytes. bcrypt ignora lo que pasa de 72 bytes.
+const (
+	MinPasswordLength = 8
+	MaxPasswordLength = 72
+)
+
+var (
+	// ErrUserNotFound se devuelve cuando el usuario pedido no existe
+	ErrUserNotFound = errors.New("user not found")
+	// ErrInvalidUserID se devuelve cuando un ID de usuario no es un UUID
+	ErrInvalidUserID = errors.New("invalid UUID")
+	// ErrHandleTaken se devuelve al registrar un handle que ya usa otro usuario
+	ErrHandleTaken = errors.New("handle already taken")
+	// ErrInvalidUser se devuelve cuando los datos del perfil no son válidos
+	ErrInvalidUser = errors.New("invalid user")
+	// ErrInvalidCredentials se devuelve al iniciar sesión con un handle o una contraseña incorrectos
+	ErrInvalidCredentials = errors.New("invalid handle or password")
+	// ErrInvalidSession se devuelve cuando el token no es válido, venció o su sesión se cerró
+	ErrInvalidSession = errors.New("invalid session")
+)
+
+// User es un usuario registrado. Handle es el nombre con el que se lo menciona (@handle): es único
+// sin importar mayúsculas, pero se guarda como lo eligió el usuario.
 type User struct {
-	ID        string
-	Following []string
-	Tweets    []Tweet
+	ID          string    `json:"id"`
+	Handle      string    `json:"handle"`
+	DisplayName string    `json:"display_name"`
+	Bio         string    `json:"bio"`
+	CreatedAt   time.Time `json:"created_at"`
+	// PasswordHash es el hash de la contraseña; vacío en los usuarios registrados antes de que
+	// existieran, que no pueden iniciar sesión
+	PasswordHash string `json:"-"`
+}
+
+// NewUser valida los datos del perfil y crea un usuario nuevo. El handle se acepta con o sin '@'
+// y tiene las mismas letras, números y '_' que se reconocen en una mención. Sin displayName se
+// muestra el handle.
+func NewUser(handle, displayName, bio string) (*User, error) {
+	handle = strings.TrimPrefix(strings.TrimSpace(handle), "@")
+	displayName = strings.TrimSpace(displayName)
+	bio = strings.TrimSpace(bio)
+
+	if handle == "" || utf8.RuneCountInString(handle) > MaxHandleLength {
+		return nil, fmt.Errorf("%w: handle must have between 1 and %d characters", ErrInvalidUser, MaxHandleLength)
+	}
+	for _, r := range handle {
+		if !isWordRune(r) {
+			return nil, fmt.Errorf("%w: handle can only have letters, numbers and '_'", ErrInvalidUser)
+		}
+	}
+
+	if displayName == "" {
+		displayName = handle
+	}
+	if utf8.RuneCountInString(displayName) > MaxDisplayNameLength {
+		return nil, fmt.Errorf("%w: display name can't have more than %d characters", ErrInvalidUser, MaxDisplayNameLength)
+	}
+
+	if utf8.RuneCountInString(bio) > MaxBioLength {
+		return nil, fmt.Errorf("%w: bio can't have more than %d characters", ErrInvalidUser, MaxBioLength)
+	}
+
+	return &User{
+		ID:          uuid.NewString(),
+		Handle:      handle,
+		DisplayName: displayName,
+		Bio:         bio,
+		CreatedAt:   time.Now().UTC(),
+	}, nil
+}
+
+// ValidatePassword verifica que la contraseña tenga un largo aceptado
+func ValidatePassword(password string) error {
+	if len(password) < MinPasswordLength || len(password) > MaxPasswordLength {
+		return fmt.Errorf("%w: password must have between %d and %d bytes", ErrInvalidUser, MinPasswordLength, MaxPasswordLength)
+	}
+	return nil
+}
+
+// NormalizeHandle devuelve la forma con la que se comparan los handles: sin '@' y en minúsculas
+func NormalizeHandle(handle string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(handle), "@"))
 }
@@ -0,0 +1,114 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+
+	_ "github.com/lib/pq"
+)
+
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+// Open abre una conexión a PostgreSQL y verifica que la base esté disponible.
+func Open(ctx context.Context, dsn string) (*sql.DB, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("error opening database: %w", err)
+	}
+
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("error connecting to database: %w", err)
+	}
+
+	return db, nil
+}
+
+// migrationLockID identifica el advisory lock que toman las migraciones. La API, el consumer y el
+// worker migran al arrancar, así que sin el lock dos binarios podrían aplicar la misma migración a
+// la vez y el que pierde se cae.
+const migrationLockID = 72023
+
+// Migrate aplica, en orden de nombre, las migraciones de migrations/ que todavía no se corrieron.
+// Cada migración se aplica en su propia transacción junto con su registro en schema_migrations.
+// Todo corre en una sola conexión que tiene el advisory lock, así que si otro proceso está migrando
+// se espera a que termine y después solo se aplican las que falten.
+func Migrate(ctx context.Context, db *sql.DB) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockID); err != nil {
+		return fmt.Errorf("error locking migrations: %w", err)
+	}
+	defer func() {
+		_, _ = conn.ExecContext(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1)`, migrationLockID)
+	}()
+
+	_, err = conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    TEXT PRIMARY KEY,
+		applied_at TIMESTAMP NOT NULL
+	)`)
+	if err != nil {
+		return fmt.Errorf("error creating schema_migrations: %w", err)
+	}
+
+	files, err := fs.Glob(migrationsFS, "migrations/*.sql")
+	if err != nil {
+		return fmt.Errorf("error listing migrations: %w", err)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		version := strings.TrimSuffix(strings.TrimPrefix(file, "migrations/"), ".sql")
+		if err := applyMigration(ctx, conn, version, file); err != nil {
+			return fmt.Errorf("error applying migration %s: %w", version, err)
+		}
+	}
+
+	return nil
+}
+
+// applyMigration corre una migración si no figura en schema_migrations.
+func applyMigration(ctx context.Context, conn *sql.Conn, version, file string) error {
+	var applied int
+	err := conn.QueryRowContext(ctx, `SELECT COUNT(*) FROM schema_migrations WHERE version = $1`, version).Scan(&applied)
+	if err != nil {
+		return err
+	}
+
+	if applied > 0 {
+		return nil
+	}
+
+	script, err := migrationsFS.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, string(script)); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, applied_at) VALUES ($1, $2)`, version, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS tweets (
+    id         TEXT PRIMARY KEY,
+    user_id    TEXT      NOT NULL,
+    content    TEXT      NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS outbox (
+    id           TEXT PRIMARY KEY,
+    event_type   TEXT      NOT NULL,
+    event_key    TEXT      NOT NULL,
+    payload      TEXT      NOT NULL,
+    created_at   TIMESTAMP NOT NULL,
+    published_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_pending ON outbox (created_at) WHERE published_at IS NULL;
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS follows (
+    follower_id TEXT      NOT NULL,
+    followed_id TEXT      NOT NULL,
+    created_at  TIMESTAMP NOT NULL,
+    PRIMARY KEY (follower_id, followed_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_follows_followed_id ON follows (followed_id);
@@ -0,0 +1,2 @@
+ALTER TABLE tweets ADD COLUMN edited_at TIMESTAMP;
+ALTER TABLE tweets ADD COLUMN revisions TEXT;
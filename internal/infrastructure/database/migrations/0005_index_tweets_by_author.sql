@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS tweets_user_id_created_at_idx ON tweets (user_id, created_at);
@@ -0,0 +1,5 @@
+ALTER TABLE tweets ADD COLUMN in_reply_to_id TEXT;
+ALTER TABLE tweets ADD COLUMN conversation_id TEXT;
+CREATE INDEX IF NOT EXISTS tweets_conversation_id_idx ON tweets (conversation_id);
+-- Los tweets que ya existían son la raíz de su propia conversación
+UPDATE tweets SET conversation_id = id WHERE conversation_id IS NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE tweets ADD COLUMN kind TEXT;
+ALTER TABLE tweets ADD COLUMN referenced_tweet_id TEXT;
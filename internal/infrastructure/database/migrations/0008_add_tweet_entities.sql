@@ -0,0 +1,2 @@
+ALTER TABLE tweets ADD COLUMN mentions TEXT;
+ALTER TABLE tweets ADD COLUMN hashtags TEXT;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS users (
+    id           TEXT      PRIMARY KEY,
+    handle       TEXT      NOT NULL,
+    display_name TEXT      NOT NULL,
+    bio          TEXT      NOT NULL,
+    created_at   TIMESTAMP NOT NULL
+);
+
+-- Los handles son únicos sin importar mayúsculas
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_handle ON users (lower(handle));
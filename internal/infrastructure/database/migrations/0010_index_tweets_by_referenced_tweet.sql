@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS tweets_referenced_tweet_id_idx ON tweets (referenced_tweet_id);
@@ -0,0 +1,2 @@
+-- Los usuarios registrados antes de las contraseñas quedan sin hash y no pueden iniciar sesión
+ALTER TABLE users ADD COLUMN password_hash TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+-- Cada actualización incrementa la versión, así dos ediciones simultáneas no se pisan
+ALTER TABLE tweets ADD COLUMN version INTEGER NOT NULL DEFAULT 0;
@@ -50,10 +50,13 @@ func (d *DLQ) StoreEvent(ctx context.Context, eventType string, payload []byte)
 	return nil
 }
 
-// RetrieveEvents obtiene todos los mensajes de la DLQ
+// RetrieveEvents saca todos los mensajes de la DLQ y los devuelve. El que no se pueda reprocesar
+// se tiene que volver a guardar con StoreEvent.
 func (d *DLQ) RetrieveEvents(ctx context.Context) ([]Message, error) {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
-	return d.messages, nil
+	messages := d.messages
+	d.messages = make([]Message, 0)
+	return messages, nil
 }
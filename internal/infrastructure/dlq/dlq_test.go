@@ -72,4 +72,9 @@ func TestRetrieveEvents(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Len(t, events, 1)
 	assert.Equal(t, string(events[0].Body), "Event 1")
+
+	// Los eventos recuperados salen de la cola
+	events, err = dlq.RetrieveEvents(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, events)
 }
@@ -10,14 +10,46 @@ type KafkaConsumer struct {
 	Reader *kafka.Reader
 }
 
-// NewKafkaConsumer crea una nueva instancia de KafkaConsumer.
+// NewKafkaConsumer crea una nueva instancia de KafkaConsumer dentro del consumer group configurado.
+// Los offsets no se commitean solos: hay que usar FetchMessage y CommitMessages una vez procesado el mensaje.
 func NewKafkaConsumer(kafkaConfig config.KafkaConfig) *KafkaConsumer {
 	reader := kafka.NewReader(kafka.ReaderConfig{
-		Brokers: kafkaConfig.Brokers,
-		Topic:   kafkaConfig.Topic,
+		Brokers:        kafkaConfig.Brokers,
+		Topic:          kafkaConfig.Topic,
+		GroupID:        kafkaConfig.GroupID,
+		CommitInterval: 0, // commits sincrónicos
 	})
 
 	return &KafkaConsumer{
 		Reader: reader,
 	}
 }
+
+// NewKafkaTrendingConsumer crea un KafkaConsumer dentro del consumer group de tendencias, que
+// recibe los mismos tweets que el fan-out pero lleva sus propios offsets.
+func NewKafkaTrendingConsumer(kafkaConfig config.KafkaConfig) *KafkaConsumer {
+	kafkaConfig.GroupID = kafkaConfig.TrendingGroupID
+	return NewKafkaConsumer(kafkaConfig)
+}
+
+// KafkaSearchConsumer lee el tópico de tweets completo para armar el índice de búsqueda en memoria.
+type KafkaSearchConsumer struct {
+	Reader *TopicReader
+}
+
+// NewKafkaSearchConsumer crea un KafkaSearchConsumer para una instancia de la API. Lee sin consumer
+// group desde el principio del tópico, así que cada arranque vuelve a armar el índice sin dejar un
+// grupo nuevo en el broker.
+func NewKafkaSearchConsumer(kafkaConfig config.KafkaConfig) *KafkaSearchConsumer {
+	return &KafkaSearchConsumer{
+		Reader: NewTopicReader(kafkaConfig.Brokers, kafkaConfig.Topic),
+	}
+}
+
+// NewKafkaNotificationConsumer crea un KafkaConsumer que lee el tópico de notificaciones dentro de
+// su propio consumer group.
+func NewKafkaNotificationConsumer(kafkaConfig config.KafkaConfig) *KafkaConsumer {
+	kafkaConfig.Topic = kafkaConfig.NotificationsTopic
+	kafkaConfig.GroupID = kafkaConfig.NotificationsGroupID
+	return NewKafkaConsumer(kafkaConfig)
+}
@@ -3,8 +3,10 @@ package consumer_test
 import (
 	"ChallengeUALA/internal/infrastructure/messaging/consumer"
 	"ChallengeUALA/internal/platform/config"
+	"context"
 	"testing"
 
+	"github.com/segmentio/kafka-go"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -13,9 +15,64 @@ func TestNewKafkaConsumer(t *testing.T) {
 	kafkaConfig := config.KafkaConfig{
 		Brokers: []string{"localhost:9092"},
 		Topic:   "test-topic",
+		GroupID: "test-group",
 	}
 
 	kafkaConsumer := consumer.NewKafkaConsumer(kafkaConfig)
 
 	assert.NotNil(t, kafkaConsumer)
+	assert.Equal(t, "test-group", kafkaConsumer.Reader.Config().GroupID)
+}
+
+func TestNewKafkaTrendingConsumer(t *testing.T) {
+
+	kafkaConfig := config.KafkaConfig{
+		Brokers:         []string{"localhost:9092"},
+		Topic:           "test-topic",
+		GroupID:         "test-group",
+		TrendingGroupID: "test-trending",
+	}
+
+	kafkaConsumer := consumer.NewKafkaTrendingConsumer(kafkaConfig)
+
+	assert.NotNil(t, kafkaConsumer)
+	assert.Equal(t, "test-trending", kafkaConsumer.Reader.Config().GroupID)
+	assert.Equal(t, "test-topic", kafkaConsumer.Reader.Config().Topic)
+}
+
+func TestNewKafkaSearchConsumer(t *testing.T) {
+
+	kafkaConfig := config.KafkaConfig{
+		Brokers: []string{"localhost:1"},
+		Topic:   "test-topic",
+		GroupID: "test-group",
+	}
+
+	searchConsumer := consumer.NewKafkaSearchConsumer(kafkaConfig)
+	assert.NotNil(t, searchConsumer.Reader)
+
+	// Sin consumer group no hay offsets que commitear
+	assert.NoError(t, searchConsumer.Reader.CommitMessages(context.Background(), kafka.Message{}))
+
+	// Si no se pueden buscar las particiones se devuelve el error para reintentar
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := searchConsumer.Reader.FetchMessage(ctx)
+	assert.Error(t, err)
+
+	assert.NoError(t, searchConsumer.Reader.Close())
+}
+
+func TestNewKafkaNotificationConsumer(t *testing.T) {
+	kafkaConfig := config.KafkaConfig{
+		Brokers:              []string{"localhost:9092"},
+		Topic:                "test-topic",
+		GroupID:              "test-group",
+		NotificationsTopic:   "test-notifications",
+		NotificationsGroupID: "test-notifications-group",
+	}
+
+	kafkaConsumer := consumer.NewKafkaNotificationConsumer(kafkaConfig)
+	assert.Equal(t, "test-notifications", kafkaConsumer.Reader.Config().Topic)
+	assert.Equal(t, "test-notifications-group", kafkaConsumer.Reader.Config().GroupID)
 }
@@ -0,0 +1,166 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// minReadBackoff y maxReadBackoff son la primera y la máxima espera entre lecturas fallidas de una partición
+const (
+	minReadBackoff = 100 * time.Millisecond
+	maxReadBackoff = 5 * time.Second
+)
+
+// TopicReader lee todas las particiones de un tópico sin consumer group, cada una desde su primer
+// offset. Sirve para que cada instancia arme un estado derivado del tópico completo sin dejar grupos
+// ni offsets en el broker. Las particiones se buscan en el primer FetchMessage; las que se agreguen
+// después no se leen hasta reiniciar.
+type TopicReader struct {
+	brokers  []string
+	topic    string
+	messages chan kafka.Message
+
+	mu      sync.Mutex
+	readers []*kafka.Reader
+	stop    context.CancelFunc
+	done    sync.WaitGroup
+}
+
+// NewTopicReader crea un TopicReader para topic. No se conecta al broker hasta el primer FetchMessage.
+func NewTopicReader(brokers []string, topic string) *TopicReader {
+	return &TopicReader{
+		brokers:  brokers,
+		topic:    topic,
+		messages: make(chan kafka.Message),
+	}
+}
+
+// FetchMessage devuelve el próximo mensaje de cualquiera de las particiones. Dentro de cada
+// partición los mensajes llegan en orden.
+func (r *TopicReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	if err := r.start(ctx); err != nil {
+		return kafka.Message{}, err
+	}
+
+	select {
+	case msg := <-r.messages:
+		return msg, nil
+	case <-ctx.Done():
+		return kafka.Message{}, ctx.Err()
+	}
+}
+
+// CommitMessages no hace nada: sin consumer group no hay offsets que guardar y cada arranque
+// vuelve a leer el tópico desde el principio.
+func (r *TopicReader) CommitMessages(ctx context.Context, msgs ...kafka.Message) error {
+	return nil
+}
+
+// Close deja de leer las particiones y cierra sus readers.
+func (r *TopicReader) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.stop != nil {
+		r.stop()
+	}
+	r.done.Wait()
+
+	var errs []error
+	for _, reader := range r.readers {
+		errs = append(errs, reader.Close())
+	}
+	r.readers = nil
+	return errors.Join(errs...)
+}
+
+// start busca las particiones del tópico y abre un reader por cada una, si todavía no se hizo.
+func (r *TopicReader) start(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.readers != nil {
+		return nil
+	}
+
+	partitions, err := r.lookupPartitions(ctx)
+	if err != nil {
+		// Quien llama reintenta enseguida, así que esperamos antes de volver a preguntarle al broker
+		select {
+		case <-time.After(time.Second):
+		case <-ctx.Done():
+		}
+		return fmt.Errorf("error looking up partitions of %s: %w", r.topic, err)
+	}
+
+	readCtx, stop := context.WithCancel(context.Background())
+	r.stop = stop
+	r.readers = make([]*kafka.Reader, 0, len(partitions))
+	for _, partition := range partitions {
+		// Sin GroupID el reader arranca en FirstOffset
+		reader := kafka.NewReader(kafka.ReaderConfig{
+			Brokers:   r.brokers,
+			Topic:     r.topic,
+			Partition: partition.ID,
+		})
+		r.readers = append(r.readers, reader)
+
+		r.done.Add(1)
+		go r.read(readCtx, reader)
+	}
+
+	return nil
+}
+
+// read pasa los mensajes de una partición al canal compartido hasta que se cierra el TopicReader.
+// Si el broker falla reintenta esperando cada vez el doble, hasta maxReadBackoff.
+func (r *TopicReader) read(ctx context.Context, reader *kafka.Reader) {
+	defer r.done.Done()
+
+	backoff := minReadBackoff
+	for {
+		msg, err := reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, io.EOF) {
+				return
+			}
+
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return
+			}
+			backoff = min(backoff*2, maxReadBackoff)
+			continue
+		}
+		backoff = minReadBackoff
+
+		select {
+		case r.messages <- msg:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// lookupPartitions le pregunta las particiones del tópico al primer broker que responde.
+func (r *TopicReader) lookupPartitions(ctx context.Context) ([]kafka.Partition, error) {
+	err := errors.New("no brokers configured")
+	for _, broker := range r.brokers {
+		var partitions []kafka.Partition
+		partitions, err = kafka.DefaultDialer.LookupPartitions(ctx, "tcp", broker, r.topic)
+		if err == nil && len(partitions) == 0 {
+			err = fmt.Errorf("topic %s has no partitions", r.topic)
+		}
+		if err == nil {
+			return partitions, nil
+		}
+	}
+	return nil, err
+}
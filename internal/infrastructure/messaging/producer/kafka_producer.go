@@ -1,8 +1,10 @@
 package producer
 
 import (
+	"ChallengeUALA/internal/application/ports"
 	"ChallengeUALA/internal/platform/config"
 	"context"
+	"time"
 
 	"github.com/segmentio/kafka-go"
 )
@@ -29,6 +31,23 @@ func NewKafkaProducer(kafkaConfig config.KafkaConfig) *KafkaProducer {
 	}
 }
 
+// notificationBatchTimeout es cuánto espera el writer de notificaciones a juntar mensajes antes de
+// escribir. Las notificaciones se publican mientras se atiende un request, así que no puede esperar
+// el segundo por defecto de kafka.Writer.
+const notificationBatchTimeout = 10 * time.Millisecond
+
+// NewKafkaNotificationProducer crea un KafkaProducer que publica en el tópico de notificaciones.
+func NewKafkaNotificationProducer(kafkaConfig config.KafkaConfig) *KafkaProducer {
+	return &KafkaProducer{
+		KafkaWriter: &kafka.Writer{
+			Addr:         kafka.TCP(kafkaConfig.Brokers...),
+			Topic:        kafkaConfig.NotificationsTopic,
+			Balancer:     &kafka.LeastBytes{},
+			BatchTimeout: notificationBatchTimeout,
+		},
+	}
+}
+
 // PublishEvent publica un evento en Kafka.
 func (kp *KafkaProducer) PublishEvent(ctx context.Context, key string, value []byte) error {
 	msg := kafka.Message{
@@ -39,6 +58,23 @@ func (kp *KafkaProducer) PublishEvent(ctx context.Context, key string, value []b
 	return kp.KafkaWriter.WriteMessages(ctx, msg)
 }
 
+// PublishEvents publica varios eventos en Kafka con una sola escritura.
+func (kp *KafkaProducer) PublishEvents(ctx context.Context, events ...ports.Event) error {
+	msgs := make([]kafka.Message, 0, len(events))
+	for _, event := range events {
+		msgs = append(msgs, kafka.Message{
+			Key:   []byte(event.Key),
+			Value: event.Value,
+		})
+	}
+	return kp.KafkaWriter.WriteMessages(ctx, msgs...)
+}
+
+// Close cierra el writer, esperando a que se escriban los mensajes pendientes.
+func (kp *KafkaProducer) Close() error {
+	return kp.KafkaWriter.Close()
+}
+
 // Writer devuelve el writer interno (solo para pruebas)
 func (kp *KafkaProducer) Writer() KafkaWriter {
 	return kp.KafkaWriter
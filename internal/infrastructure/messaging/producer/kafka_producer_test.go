@@ -1,6 +1,7 @@
 package producer
 
 import (
+	"ChallengeUALA/internal/application/ports"
 	"ChallengeUALA/internal/platform/config"
 	"context"
 	"testing"
@@ -80,3 +81,37 @@ func TestKafkaProducer_Writer(t *testing.T) {
 
 	assert.NotNil(t, writer)
 }
+
+func TestNewKafkaNotificationProducer(t *testing.T) {
+	kafkaConfig := config.KafkaConfig{
+		Brokers:            []string{"localhost:9092"},
+		Topic:              "test-topic",
+		NotificationsTopic: "test-notifications",
+	}
+
+	kp := NewKafkaNotificationProducer(kafkaConfig)
+	writer := kp.Writer().(*kafka.Writer)
+	assert.Equal(t, "test-notifications", writer.Topic)
+	assert.Equal(t, notificationBatchTimeout, writer.BatchTimeout)
+}
+
+func TestKafkaProducer_PublishEvents(t *testing.T) {
+	mockWriter := new(MockKafkaWriter)
+	kp := &KafkaProducer{
+		KafkaWriter: mockWriter,
+	}
+
+	// Todos los eventos se escriben juntos
+	mockWriter.On("WriteMessages", mock.Anything, []kafka.Message{
+		{Key: []byte("user1"), Value: []byte("a")},
+		{Key: []byte("user2"), Value: []byte("b")},
+	}).Return(nil).Once()
+
+	err := kp.PublishEvents(context.Background(),
+		ports.Event{Key: "user1", Value: []byte("a")},
+		ports.Event{Key: "user2", Value: []byte("b")},
+	)
+
+	assert.NoError(t, err)
+	mockWriter.AssertExpectations(t)
+}
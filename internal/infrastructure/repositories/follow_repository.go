@@ -2,22 +2,25 @@ package repositories
 
 import (
 	"context"
-	"fmt"
-	"log"
+	"sort"
 	"sync"
 )
 
-// FollowRepository es una implementación en memoria de la interfaz FollowRepository
+// FollowRepository es una implementación en memoria de la interfaz FollowRepository.
+// Mantiene dos índices: a quién sigue cada usuario (follows) y quién sigue a cada usuario
+// (followers), así las consultas sobre seguidores no recorren todos los usuarios.
 type FollowRepository struct {
-	mu      sync.RWMutex
-	follows map[string]map[string]bool
+	mu        sync.RWMutex
+	follows   map[string]map[string]bool
+	followers map[string]map[string]bool
 }
 
 // FollowRepository crea una nueva instancia de FollowRepository
 func NewFollowRepository() *FollowRepository {
 	return &FollowRepository{
-		mu:      sync.RWMutex{},
-		follows: make(map[string]map[string]bool),
+		mu:        sync.RWMutex{},
+		follows:   make(map[string]map[string]bool),
+		followers: make(map[string]map[string]bool),
 	}
 }
 
@@ -26,10 +29,18 @@ func (r *FollowRepository) Follow(ctx context.Context, followerID, followedID st
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if r.follows[followerID] == nil {
-		r.follows[followerID] = make(map[string]bool)
-	}
-	r.follows[followerID][followedID] = true
+	addToIndex(r.follows, followerID, followedID)
+	addToIndex(r.followers, followedID, followerID)
+	return nil
+}
+
+// Unfollow elimina la relación de seguimiento entre dos usuarios.
+func (r *FollowRepository) Unfollow(ctx context.Context, followerID, followedID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	removeFromIndex(r.follows, followerID, followedID)
+	removeFromIndex(r.followers, followedID, followerID)
 	return nil
 }
 
@@ -53,19 +64,94 @@ func (r *FollowRepository) IsFollowing(ctx context.Context, followerID, followed
 
 // GetFollowers devuelve los seguidores de un usuario.
 func (r *FollowRepository) GetFollowers(ctx context.Context, userID string) ([]string, error) {
-	log.Println("Getting followers oon repository")
 	r.mu.RLock()
 	defer r.mu.RUnlock()
-	log.Println("UserID: ", userID)
-	if r.follows == nil {
-		return nil, fmt.Errorf("follows map is uninitialized")
+
+	return indexKeys(r.followers[userID]), nil
+}
+
+// GetFollowing devuelve los usuarios que sigue un usuario.
+func (r *FollowRepository) GetFollowing(ctx context.Context, userID string) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return indexKeys(r.follows[userID]), nil
+}
+
+// CountFollowers devuelve la cantidad de seguidores de un usuario.
+func (r *FollowRepository) CountFollowers(ctx context.Context, userID string) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.followers[userID]), nil
+}
+
+// CountFollowing devuelve la cantidad de usuarios que sigue un usuario.
+func (r *FollowRepository) CountFollowing(ctx context.Context, userID string) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.follows[userID]), nil
+}
+
+// ListFollowers devuelve una página de los seguidores de un usuario ordenados por ID.
+func (r *FollowRepository) ListFollowers(ctx context.Context, userID, afterID string, limit int) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return pageIndex(r.followers[userID], afterID, limit), nil
+}
+
+// ListFollowing devuelve una página de los usuarios que sigue un usuario ordenados por ID.
+func (r *FollowRepository) ListFollowing(ctx context.Context, userID, afterID string, limit int) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return pageIndex(r.follows[userID], afterID, limit), nil
+}
+
+func addToIndex(index map[string]map[string]bool, key, value string) {
+	if index[key] == nil {
+		index[key] = make(map[string]bool)
 	}
-	log.Println("Follows: ", r.follows)
-	var followers []string
-	for followerID, followed := range r.follows {
-		if followed[userID] {
-			followers = append(followers, followerID)
-		}
+	index[key][value] = true
+}
+
+// removeFromIndex saca el valor y borra la entrada si queda vacía para no acumular mapas vacíos
+func removeFromIndex(index map[string]map[string]bool, key, value string) {
+	values, ok := index[key]
+	if !ok {
+		return
 	}
-	return followers, nil
+
+	delete(values, value)
+	if len(values) == 0 {
+		delete(index, key)
+	}
+}
+
+func indexKeys(values map[string]bool) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// pageIndex ordena los IDs y devuelve hasta limit a partir del primero mayor que afterID
+func pageIndex(values map[string]bool, afterID string, limit int) []string {
+	keys := indexKeys(values)
+	sort.Strings(keys)
+
+	start := sort.SearchStrings(keys, afterID)
+	if start < len(keys) && keys[start] == afterID {
+		start++
+	}
+
+	end := start + limit
+	if end > len(keys) {
+		end = len(keys)
+	}
+
+	return keys[start:end]
 }
@@ -31,6 +31,33 @@ func TestFollow(t *testing.T) {
 	assert.True(t, isFollowing, "user1 should be following themselves")
 }
 
+// TestUnfollow verifica el método Unfollow.
+func TestUnfollow(t *testing.T) {
+	repo := NewFollowRepository()
+	ctx := context.Background()
+
+	err := repo.Follow(ctx, "user1", "user2")
+	assert.NoError(t, err, "Follow should not return an error")
+	err = repo.Follow(ctx, "user1", "user3")
+	assert.NoError(t, err, "Follow should not return an error")
+
+	// Caso 1: Un usuario deja de seguir a otro
+	err = repo.Unfollow(ctx, "user1", "user2")
+	assert.NoError(t, err, "Unfollow should not return an error")
+
+	isFollowing, err := repo.IsFollowing(ctx, "user1", "user2")
+	assert.NoError(t, err, "IsFollowing should not return an error")
+	assert.False(t, isFollowing, "user1 should not be following user2")
+
+	isFollowing, err = repo.IsFollowing(ctx, "user1", "user3")
+	assert.NoError(t, err, "IsFollowing should not return an error")
+	assert.True(t, isFollowing, "user1 should still be following user3")
+
+	// Caso 2: Dejar de seguir a alguien que no se sigue no falla
+	err = repo.Unfollow(ctx, "user4", "user1")
+	assert.NoError(t, err, "Unfollow should not return an error when not following")
+}
+
 // TestIsFollowing verifica el método IsFollowing.
 func TestIsFollowing(t *testing.T) {
 	repo := NewFollowRepository()
@@ -74,3 +101,76 @@ func TestGetFollowers(t *testing.T) {
 	assert.NoError(t, err, "GetFollowers should not return an error")
 	assert.Empty(t, followers, "user4 should have no followers")
 }
+
+// TestGetFollowing verifica el método GetFollowing.
+func TestGetFollowing(t *testing.T) {
+	repo := NewFollowRepository()
+	ctx := context.Background()
+
+	// Configurar datos de prueba
+	err := repo.Follow(ctx, "user1", "user2")
+	assert.NoError(t, err, "Follow should not return an error")
+	err = repo.Follow(ctx, "user1", "user3")
+	assert.NoError(t, err, "Follow should not return an error")
+
+	following, err := repo.GetFollowing(ctx, "user1")
+	assert.NoError(t, err, "GetFollowing should not return an error")
+	assert.ElementsMatch(t, []string{"user2", "user3"}, following, "user1 should follow user2 and user3")
+
+	following, err = repo.GetFollowing(ctx, "user4")
+	assert.NoError(t, err, "GetFollowing should not return an error")
+	assert.Empty(t, following, "user4 should not follow anyone")
+}
+
+// TestCountFollows verifica los métodos CountFollowers y CountFollowing.
+func TestCountFollows(t *testing.T) {
+	repo := NewFollowRepository()
+	ctx := context.Background()
+
+	// Configurar datos de prueba
+	assert.NoError(t, repo.Follow(ctx, "user2", "user1"))
+	assert.NoError(t, repo.Follow(ctx, "user3", "user1"))
+	assert.NoError(t, repo.Follow(ctx, "user1", "user2"))
+
+	followers, err := repo.CountFollowers(ctx, "user1")
+	assert.NoError(t, err, "CountFollowers should not return an error")
+	assert.Equal(t, 2, followers, "user1 should have 2 followers")
+
+	following, err := repo.CountFollowing(ctx, "user1")
+	assert.NoError(t, err, "CountFollowing should not return an error")
+	assert.Equal(t, 1, following, "user1 should follow 1 user")
+
+	// El índice de seguidores se mantiene al dejar de seguir
+	assert.NoError(t, repo.Unfollow(ctx, "user2", "user1"))
+
+	followers, err = repo.CountFollowers(ctx, "user1")
+	assert.NoError(t, err, "CountFollowers should not return an error")
+	assert.Equal(t, 1, followers, "user1 should have 1 follower")
+
+	followerIDs, err := repo.GetFollowers(ctx, "user1")
+	assert.NoError(t, err, "GetFollowers should not return an error")
+	assert.Equal(t, []string{"user3"}, followerIDs, "user3 should be the only follower of user1")
+}
+
+// TestListFollowers verifica la paginación de ListFollowers y ListFollowing.
+func TestListFollowers(t *testing.T) {
+	repo := NewFollowRepository()
+	ctx := context.Background()
+
+	// Configurar datos de prueba
+	for _, followerID := range []string{"user4", "user2", "user3"} {
+		assert.NoError(t, repo.Follow(ctx, followerID, "user1"))
+	}
+
+	followers, err := repo.ListFollowers(ctx, "user1", "", 2)
+	assert.NoError(t, err, "ListFollowers should not return an error")
+	assert.Equal(t, []string{"user2", "user3"}, followers, "followers should be sorted by ID")
+
+	followers, err = repo.ListFollowers(ctx, "user1", "user3", 2)
+	assert.NoError(t, err, "ListFollowers should not return an error")
+	assert.Equal(t, []string{"user4"}, followers, "the page should start after user3")
+
+	following, err := repo.ListFollowing(ctx, "user2", "", 10)
+	assert.NoError(t, err, "ListFollowing should not return an error")
+	assert.Equal(t, []string{"user1"}, following, "user2 should follow user1")
+}
@@ -0,0 +1,66 @@
+package repositories
+
+import (
+	"context"
+	"sync"
+)
+
+// LikeRepository es una implementación en memoria de la interfaz LikeRepository.
+// Guarda, por cada tweet, el conjunto de usuarios que le dieron like.
+type LikeRepository struct {
+	mu    sync.RWMutex
+	likes map[string]map[string]bool
+}
+
+// NewLikeRepository crea una nueva instancia de LikeRepository
+func NewLikeRepository() *LikeRepository {
+	return &LikeRepository{
+		likes: make(map[string]map[string]bool),
+	}
+}
+
+// Like registra el like de un usuario a un tweet.
+func (r *LikeRepository) Like(ctx context.Context, tweetID, userID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.likes[tweetID][userID] {
+		return false, nil
+	}
+	addToIndex(r.likes, tweetID, userID)
+	return true, nil
+}
+
+// Unlike saca el like de un usuario a un tweet.
+func (r *LikeRepository) Unlike(ctx context.Context, tweetID, userID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.likes[tweetID][userID] {
+		return false, nil
+	}
+	removeFromIndex(r.likes, tweetID, userID)
+	return true, nil
+}
+
+// CountLikes devuelve la cantidad de likes de cada tweet que tiene alguno.
+func (r *LikeRepository) CountLikes(ctx context.Context, tweetIDs []string) (map[string]int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for _, tweetID := range tweetIDs {
+		if count := len(r.likes[tweetID]); count > 0 {
+			counts[tweetID] = count
+		}
+	}
+	return counts, nil
+}
+
+// ListLikers devuelve una página de los usuarios que le dieron like a un tweet ordenados por ID.
+func (r *LikeRepository) ListLikers(ctx context.Context, tweetID, afterID string, limit int) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return pageIndex(r.likes[tweetID], afterID, limit), nil
+}
@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLikeRepository_LikeAndUnlike(t *testing.T) {
+	repo := NewLikeRepository()
+	ctx := context.Background()
+
+	added, err := repo.Like(ctx, "tweet1", "user1")
+	assert.NoError(t, err)
+	assert.True(t, added)
+
+	// Un segundo like del mismo usuario no cuenta
+	added, err = repo.Like(ctx, "tweet1", "user1")
+	assert.NoError(t, err)
+	assert.False(t, added)
+
+	_, err = repo.Like(ctx, "tweet1", "user2")
+	assert.NoError(t, err)
+
+	counts, err := repo.CountLikes(ctx, []string{"tweet1", "tweet2"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"tweet1": 2}, counts)
+
+	removed, err := repo.Unlike(ctx, "tweet1", "user1")
+	assert.NoError(t, err)
+	assert.True(t, removed)
+
+	removed, err = repo.Unlike(ctx, "tweet1", "user1")
+	assert.NoError(t, err)
+	assert.False(t, removed)
+
+	counts, err = repo.CountLikes(ctx, []string{"tweet1"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"tweet1": 1}, counts)
+}
+
+func TestLikeRepository_ListLikers(t *testing.T) {
+	repo := NewLikeRepository()
+	ctx := context.Background()
+
+	for _, userID := range []string{"c", "a", "b"} {
+		_, err := repo.Like(ctx, "tweet1", userID)
+		assert.NoError(t, err)
+	}
+
+	users, err := repo.ListLikers(ctx, "tweet1", "", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, users)
+
+	users, err = repo.ListLikers(ctx, "tweet1", "b", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"c"}, users)
+
+	users, err = repo.ListLikers(ctx, "tweet2", "", 2)
+	assert.NoError(t, err)
+	assert.Empty(t, users)
+}
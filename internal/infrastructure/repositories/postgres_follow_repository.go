@@ -0,0 +1,153 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// PostgresFollowRepository es una implementación de la interfaz FollowRepository sobre PostgreSQL.
+// Permite que la API y el consumer compartan el grafo de follows.
+type PostgresFollowRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresFollowRepository crea una nueva instancia de PostgresFollowRepository
+func NewPostgresFollowRepository(db *sql.DB) *PostgresFollowRepository {
+	return &PostgresFollowRepository{
+		db: db,
+	}
+}
+
+// Follow establece una relación de seguimiento entre dos usuarios.
+func (r *PostgresFollowRepository) Follow(ctx context.Context, followerID, followedID string) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO follows (follower_id, followed_id, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (follower_id, followed_id) DO NOTHING`,
+		followerID, followedID, time.Now().UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("error saving follow: %w", err)
+	}
+
+	return nil
+}
+
+// Unfollow elimina la relación de seguimiento entre dos usuarios.
+func (r *PostgresFollowRepository) Unfollow(ctx context.Context, followerID, followedID string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM follows WHERE follower_id = $1 AND followed_id = $2`, followerID, followedID)
+	if err != nil {
+		return fmt.Errorf("error deleting follow: %w", err)
+	}
+
+	return nil
+}
+
+// IsFollowing verifica si un usuario sigue a otro.
+func (r *PostgresFollowRepository) IsFollowing(ctx context.Context, followerID, followedID string) (bool, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM follows WHERE follower_id = $1 AND followed_id = $2`,
+		followerID, followedID,
+	).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("error checking follow: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// GetFollowers devuelve los seguidores de un usuario. Usa el índice sobre followed_id.
+func (r *PostgresFollowRepository) GetFollowers(ctx context.Context, userID string) ([]string, error) {
+	followers, err := r.queryIDs(ctx, `SELECT follower_id FROM follows WHERE followed_id = $1`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting followers: %w", err)
+	}
+
+	return followers, nil
+}
+
+// GetFollowing devuelve los usuarios que sigue un usuario. Usa la clave primaria.
+func (r *PostgresFollowRepository) GetFollowing(ctx context.Context, userID string) ([]string, error) {
+	following, err := r.queryIDs(ctx, `SELECT followed_id FROM follows WHERE follower_id = $1`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting following: %w", err)
+	}
+
+	return following, nil
+}
+
+// CountFollowers devuelve la cantidad de seguidores de un usuario.
+func (r *PostgresFollowRepository) CountFollowers(ctx context.Context, userID string) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM follows WHERE followed_id = $1`, userID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("error counting followers: %w", err)
+	}
+
+	return count, nil
+}
+
+// CountFollowing devuelve la cantidad de usuarios que sigue un usuario.
+func (r *PostgresFollowRepository) CountFollowing(ctx context.Context, userID string) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM follows WHERE follower_id = $1`, userID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("error counting following: %w", err)
+	}
+
+	return count, nil
+}
+
+// ListFollowers devuelve una página de los seguidores de un usuario ordenados por ID.
+func (r *PostgresFollowRepository) ListFollowers(ctx context.Context, userID, afterID string, limit int) ([]string, error) {
+	followers, err := r.queryIDs(ctx, `
+		SELECT follower_id FROM follows
+		WHERE followed_id = $1 AND follower_id > $2
+		ORDER BY follower_id
+		LIMIT $3`,
+		userID, afterID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error listing followers: %w", err)
+	}
+
+	return followers, nil
+}
+
+// ListFollowing devuelve una página de los usuarios que sigue un usuario ordenados por ID.
+func (r *PostgresFollowRepository) ListFollowing(ctx context.Context, userID, afterID string, limit int) ([]string, error) {
+	following, err := r.queryIDs(ctx, `
+		SELECT followed_id FROM follows
+		WHERE follower_id = $1 AND followed_id > $2
+		ORDER BY followed_id
+		LIMIT $3`,
+		userID, afterID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error listing following: %w", err)
+	}
+
+	return following, nil
+}
+
+func (r *PostgresFollowRepository) queryIDs(ctx context.Context, query string, args ...interface{}) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]string, 0)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgresFollowRepository_FollowAndUnfollow(t *testing.T) {
+	db, cleanup := setupTestPostgresDB()
+	defer cleanup()
+
+	repo := NewPostgresFollowRepository(db)
+	ctx := context.Background()
+
+	err := repo.Follow(ctx, "user1", "user2")
+	assert.NoError(t, err)
+
+	// Seguir dos veces al mismo usuario no falla
+	err = repo.Follow(ctx, "user1", "user2")
+	assert.NoError(t, err)
+
+	isFollowing, err := repo.IsFollowing(ctx, "user1", "user2")
+	assert.NoError(t, err)
+	assert.True(t, isFollowing)
+
+	isFollowing, err = repo.IsFollowing(ctx, "user2", "user1")
+	assert.NoError(t, err)
+	assert.False(t, isFollowing)
+
+	err = repo.Unfollow(ctx, "user1", "user2")
+	assert.NoError(t, err)
+
+	isFollowing, err = repo.IsFollowing(ctx, "user1", "user2")
+	assert.NoError(t, err)
+	assert.False(t, isFollowing)
+}
+
+func TestPostgresFollowRepository_GetFollowers(t *testing.T) {
+	db, cleanup := setupTestPostgresDB()
+	defer cleanup()
+
+	repo := NewPostgresFollowRepository(db)
+	ctx := context.Background()
+
+	assert.NoError(t, repo.Follow(ctx, "user2", "user1"))
+	assert.NoError(t, repo.Follow(ctx, "user3", "user1"))
+
+	followers, err := repo.GetFollowers(ctx, "user1")
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"user2", "user3"}, followers)
+
+	followers, err = repo.GetFollowers(ctx, "user4")
+	assert.NoError(t, err)
+	assert.Empty(t, followers)
+}
+
+func TestPostgresFollowRepository_GetFollowingAndCounts(t *testing.T) {
+	db, cleanup := setupTestPostgresDB()
+	defer cleanup()
+
+	repo := NewPostgresFollowRepository(db)
+	ctx := context.Background()
+
+	assert.NoError(t, repo.Follow(ctx, "user1", "user2"))
+	assert.NoError(t, repo.Follow(ctx, "user1", "user3"))
+	assert.NoError(t, repo.Follow(ctx, "user2", "user1"))
+
+	following, err := repo.GetFollowing(ctx, "user1")
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"user2", "user3"}, following)
+
+	count, err := repo.CountFollowing(ctx, "user1")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	count, err = repo.CountFollowers(ctx, "user1")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	count, err = repo.CountFollowers(ctx, "user4")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
+
+func TestPostgresFollowRepository_ListFollowers(t *testing.T) {
+	db, cleanup := setupTestPostgresDB()
+	defer cleanup()
+
+	repo := NewPostgresFollowRepository(db)
+	ctx := context.Background()
+
+	for _, followerID := range []string{"user4", "user2", "user3"} {
+		assert.NoError(t, repo.Follow(ctx, followerID, "user1"))
+	}
+
+	followers, err := repo.ListFollowers(ctx, "user1", "", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"user2", "user3"}, followers)
+
+	followers, err = repo.ListFollowers(ctx, "user1", "user3", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"user4"}, followers)
+
+	following, err := repo.ListFollowing(ctx, "user2", "", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"user1"}, following)
+}
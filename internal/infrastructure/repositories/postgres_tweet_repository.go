@@ -0,0 +1,502 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"ChallengeUALA/internal/domain"
+)
+
+// tweetColumns son las columnas que lee scanTweet, en orden
+const tweetColumns = "id, user_id, content, created_at, edited_at, revisions, in_reply_to_id, conversation_id, kind, referenced_tweet_id, mentions, hashtags, version"
+
+// PostgresTweetRepository es una implementación de las interfaces TweetRepository y OutboxRepository sobre PostgreSQL
+type PostgresTweetRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresTweetRepository crea una nueva instancia de PostgresTweetRepository.
+// El esquema tiene que estar creado de antemano con database.Migrate.
+func NewPostgresTweetRepository(db *sql.DB) *PostgresTweetRepository {
+	return &PostgresTweetRepository{
+		db: db,
+	}
+}
+
+// Save guarda un tweet en la base de datos. Si el tweet ya existe lo sobreescribe,
+// igual que la implementación en memoria.
+func (r *PostgresTweetRepository) Save(ctx context.Context, tweet *domain.Tweet) error {
+	if err := upsertTweet(ctx, r.db, tweet); err != nil {
+		return fmt.Errorf("error saving tweet: %w", err)
+	}
+
+	return nil
+}
+
+// SaveWithEvent guarda el tweet y el evento en el outbox dentro de la misma transacción
+func (r *PostgresTweetRepository) SaveWithEvent(ctx context.Context, tweet *domain.Tweet, event *domain.OutboxEvent) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := upsertTweet(ctx, tx, tweet); err != nil {
+		return fmt.Errorf("error saving tweet: %w", err)
+	}
+
+	if err := insertOutboxEvent(ctx, tx, event); err != nil {
+		return fmt.Errorf("error saving outbox event: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID devuelve un tweet por su ID
+func (r *PostgresTweetRepository) GetByID(ctx context.Context, id string) (*domain.Tweet, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+tweetColumns+`
+		FROM tweets
+		WHERE id = $1`,
+		id,
+	)
+
+	tweet, err := scanTweet(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrTweetNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting tweet: %w", err)
+	}
+
+	return tweet, nil
+}
+
+// ListByAuthor devuelve una página de los tweets de un autor, del más nuevo al más viejo.
+// Igual que en Redis, los timelines se ordenan por segundo y después por ID, así que los
+// tweets del segundo del cursor y del último segundo de la página se traen completos y se
+// reordenan con domain.SortTimeline.
+func (r *PostgresTweetRepository) ListByAuthor(ctx context.Context, authorID, cursor string, limit int) (*domain.TimelinePage, error) {
+	candidates := make([]*domain.Tweet, 0)
+	var rest []*domain.Tweet
+
+	if cursor != "" {
+		unix, tweetID, err := domain.DecodeTimelineCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		// Del segundo del cursor solo quedan los tweets con ID menor
+		tied, err := r.listSecond(ctx, authorID, unix)
+		if err != nil {
+			return nil, err
+		}
+		for _, tweet := range tied {
+			if tweet.ID < tweetID {
+				candidates = append(candidates, tweet)
+			}
+		}
+
+		// Pedimos un elemento de más para saber si hay una página siguiente
+		rest, err = r.queryTweets(ctx, `
+			SELECT `+tweetColumns+`
+			FROM tweets
+			WHERE user_id = $1 AND created_at < $2
+			ORDER BY created_at DESC, id DESC
+			LIMIT $3`,
+			authorID, time.Unix(unix, 0).UTC(), limit+1,
+		)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		rest, err = r.queryTweets(ctx, `
+			SELECT `+tweetColumns+`
+			FROM tweets
+			WHERE user_id = $1
+			ORDER BY created_at DESC, id DESC
+			LIMIT $2`,
+			authorID, limit+1,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Si el último segundo quedó cortado, lo traemos completo para ordenar bien los empates
+	if len(rest) > limit {
+		last := rest[len(rest)-1].CreatedAt.Unix()
+		tied, err := r.listSecond(ctx, authorID, last)
+		if err != nil {
+			return nil, err
+		}
+		for len(rest) > 0 && rest[len(rest)-1].CreatedAt.Unix() == last {
+			rest = rest[:len(rest)-1]
+		}
+		rest = append(rest, tied...)
+	}
+
+	candidates = append(candidates, rest...)
+	domain.SortTimeline(candidates)
+
+	page := &domain.TimelinePage{Tweets: candidates}
+	if len(candidates) > limit {
+		page.Tweets = candidates[:limit]
+		page.NextCursor = domain.EncodeTimelineCursor(page.Tweets[len(page.Tweets)-1])
+	}
+
+	return page, nil
+}
+
+// listSecond devuelve los tweets de un autor publicados durante el segundo unix
+func (r *PostgresTweetRepository) listSecond(ctx context.Context, authorID string, unix int64) ([]*domain.Tweet, error) {
+	start := time.Unix(unix, 0).UTC()
+	return r.queryTweets(ctx, `
+		SELECT `+tweetColumns+`
+		FROM tweets
+		WHERE user_id = $1 AND created_at >= $2 AND created_at < $3`,
+		authorID, start, start.Add(time.Second),
+	)
+}
+
+func (r *PostgresTweetRepository) queryTweets(ctx context.Context, query string, args ...interface{}) ([]*domain.Tweet, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing tweets: %w", err)
+	}
+	defer rows.Close()
+
+	tweets := make([]*domain.Tweet, 0)
+	for rows.Next() {
+		tweet, err := scanTweet(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning tweet: %w", err)
+		}
+		tweets = append(tweets, tweet)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error listing tweets: %w", err)
+	}
+
+	return tweets, nil
+}
+
+// ListByConversation devuelve los tweets de una conversación
+func (r *PostgresTweetRepository) ListByConversation(ctx context.Context, conversationID string) ([]*domain.Tweet, error) {
+	return r.queryTweets(ctx, `
+		SELECT `+tweetColumns+`
+		FROM tweets
+		WHERE conversation_id = $1`,
+		conversationID,
+	)
+}
+
+// ListRetweets devuelve los retweets de un tweet
+func (r *PostgresTweetRepository) ListRetweets(ctx context.Context, tweetID string) ([]*domain.Tweet, error) {
+	return r.queryTweets(ctx, `
+		SELECT `+tweetColumns+`
+		FROM tweets
+		WHERE referenced_tweet_id = $1 AND kind = $2`,
+		tweetID, domain.TweetKindRetweet,
+	)
+}
+
+// UpdateWithEvent guarda el contenido y el historial de edición del tweet y el evento
+// en el outbox dentro de la misma transacción. El UPDATE solo toca la fila si todavía tiene la
+// versión que se leyó, así que de dos ediciones simultáneas la segunda no pisa a la primera.
+func (r *PostgresTweetRepository) UpdateWithEvent(ctx context.Context, tweet *domain.Tweet, event *domain.OutboxEvent) error {
+	columns, err := marshalTweetLists(tweet)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE tweets
+		SET content = $1, edited_at = $2, revisions = $3, mentions = $4, hashtags = $5, version = version + 1
+		WHERE id = $6 AND version = $7`,
+		tweet.Content, nullTime(tweet.EditedAt), columns.revisions, columns.mentions, columns.hashtags, tweet.ID, tweet.Version,
+	)
+	if err != nil {
+		return fmt.Errorf("error updating tweet: %w", err)
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error updating tweet: %w", err)
+	}
+	if updated == 0 {
+		return updateMissError(ctx, tx, tweet.ID)
+	}
+
+	if err := insertOutboxEvent(ctx, tx, event); err != nil {
+		return fmt.Errorf("error saving outbox event: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	tweet.Version++
+	return nil
+}
+
+// updateMissError explica por qué un UPDATE no tocó ninguna fila: el tweet no existe o cambió de versión
+func updateMissError(ctx context.Context, tx *sql.Tx, id string) error {
+	var exists bool
+	err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM tweets WHERE id = $1)`, id).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("error updating tweet: %w", err)
+	}
+
+	if !exists {
+		return domain.ErrTweetNotFound
+	}
+	return domain.ErrTweetConflict
+}
+
+// Delete borra un tweet de la base de datos
+func (r *PostgresTweetRepository) Delete(ctx context.Context, id string) error {
+	return deleteTweet(ctx, r.db, id)
+}
+
+// DeleteWithEvent borra el tweet y guarda el evento en el outbox dentro de la misma transacción
+func (r *PostgresTweetRepository) DeleteWithEvent(ctx context.Context, id string, event *domain.OutboxEvent) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := deleteTweet(ctx, tx, id); err != nil {
+		return err
+	}
+
+	if err := insertOutboxEvent(ctx, tx, event); err != nil {
+		return fmt.Errorf("error saving outbox event: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+// FetchPending devuelve hasta limit eventos sin publicar, del más viejo al más nuevo
+func (r *PostgresTweetRepository) FetchPending(ctx context.Context, limit int) ([]*domain.OutboxEvent, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, event_type, event_key, payload, created_at
+		FROM outbox
+		WHERE published_at IS NULL
+		ORDER BY created_at, id
+		LIMIT $1`,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching outbox events: %w", err)
+	}
+	defer rows.Close()
+
+	events := make([]*domain.OutboxEvent, 0)
+	for rows.Next() {
+		var event domain.OutboxEvent
+		var payload string
+		if err := rows.Scan(&event.ID, &event.EventType, &event.Key, &payload, &event.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning outbox event: %w", err)
+		}
+		event.Payload = []byte(payload)
+		events = append(events, &event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error fetching outbox events: %w", err)
+	}
+
+	return events, nil
+}
+
+// MarkPublished marca un evento del outbox como publicado
+func (r *PostgresTweetRepository) MarkPublished(ctx context.Context, eventID string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE outbox SET published_at = $1 WHERE id = $2`, time.Now().UTC(), eventID)
+	if err != nil {
+		return fmt.Errorf("error marking outbox event as published: %w", err)
+	}
+
+	return nil
+}
+
+// sqlExecutor permite usar la misma query con *sql.DB o dentro de una *sql.Tx
+type sqlExecutor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+func insertOutboxEvent(ctx context.Context, exec sqlExecutor, event *domain.OutboxEvent) error {
+	_, err := exec.ExecContext(ctx, `
+		INSERT INTO outbox (id, event_type, event_key, payload, created_at)
+		VALUES ($1, $2, $3, $4, $5)`,
+		event.ID, event.EventType, event.Key, string(event.Payload), event.CreatedAt.UTC(),
+	)
+	return err
+}
+
+// deleteTweet devuelve domain.ErrTweetNotFound si no se borró ninguna fila
+func deleteTweet(ctx context.Context, exec sqlExecutor, id string) error {
+	result, err := exec.ExecContext(ctx, `DELETE FROM tweets WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("error deleting tweet: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error deleting tweet: %w", err)
+	}
+	if deleted == 0 {
+		return domain.ErrTweetNotFound
+	}
+
+	return nil
+}
+
+func upsertTweet(ctx context.Context, exec sqlExecutor, tweet *domain.Tweet) error {
+	columns, err := marshalTweetLists(tweet)
+	if err != nil {
+		return err
+	}
+
+	_, err = exec.ExecContext(ctx, `
+		INSERT INTO tweets (id, user_id, content, created_at, edited_at, revisions, in_reply_to_id, conversation_id, kind, referenced_tweet_id, mentions, hashtags, version)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (id) DO UPDATE SET
+			user_id = EXCLUDED.user_id,
+			content = EXCLUDED.content,
+			created_at = EXCLUDED.created_at,
+			edited_at = EXCLUDED.edited_at,
+			revisions = EXCLUDED.revisions,
+			in_reply_to_id = EXCLUDED.in_reply_to_id,
+			conversation_id = EXCLUDED.conversation_id,
+			kind = EXCLUDED.kind,
+			referenced_tweet_id = EXCLUDED.referenced_tweet_id,
+			mentions = EXCLUDED.mentions,
+			hashtags = EXCLUDED.hashtags,
+			version = EXCLUDED.version`,
+		tweet.ID, tweet.UserID, tweet.Content, tweet.CreatedAt.UTC(), nullTime(tweet.EditedAt), columns.revisions,
+		nullString(tweet.InReplyToID), tweet.Conversation(), nullString(tweet.Kind), nullString(tweet.ReferencedTweetID),
+		columns.mentions, columns.hashtags, tweet.Version,
+	)
+	return err
+}
+
+// rowScanner permite escanear tanto una *sql.Row como una fila de *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanTweet lee las columnas de tweetColumns
+func scanTweet(row rowScanner) (*domain.Tweet, error) {
+	var tweet domain.Tweet
+	var editedAt sql.NullTime
+	var revisions, inReplyToID, conversationID, kind, referencedTweetID, mentions, hashtags sql.NullString
+	err := row.Scan(
+		&tweet.ID, &tweet.UserID, &tweet.Content, &tweet.CreatedAt,
+		&editedAt, &revisions, &inReplyToID, &conversationID, &kind, &referencedTweetID,
+		&mentions, &hashtags, &tweet.Version,
+	)
+	if err != nil {
+		return nil, err
+	}
+	tweet.InReplyToID = inReplyToID.String
+	tweet.ConversationID = conversationID.String
+	tweet.Kind = kind.String
+	tweet.ReferencedTweetID = referencedTweetID.String
+
+	tweet.CreatedAt = tweet.CreatedAt.UTC()
+	if editedAt.Valid {
+		edited := editedAt.Time.UTC()
+		tweet.EditedAt = &edited
+	}
+
+	if err := unmarshalList("revisions", revisions, &tweet.Revisions); err != nil {
+		return nil, err
+	}
+	if err := unmarshalList("mentions", mentions, &tweet.Mentions); err != nil {
+		return nil, err
+	}
+	if err := unmarshalList("hashtags", hashtags, &tweet.Hashtags); err != nil {
+		return nil, err
+	}
+
+	return &tweet, nil
+}
+
+// tweetLists son las listas del tweet que se guardan como JSON en su propia columna
+type tweetLists struct {
+	revisions, mentions, hashtags sql.NullString
+}
+
+func marshalTweetLists(tweet *domain.Tweet) (tweetLists, error) {
+	var columns tweetLists
+	var err error
+	if columns.revisions, err = marshalList("revisions", tweet.Revisions); err != nil {
+		return columns, err
+	}
+	if columns.mentions, err = marshalList("mentions", tweet.Mentions); err != nil {
+		return columns, err
+	}
+	if columns.hashtags, err = marshalList("hashtags", tweet.Hashtags); err != nil {
+		return columns, err
+	}
+	return columns, nil
+}
+
+// marshalList guarda una lista como JSON, o NULL si está vacía (por ejemplo, un tweet que nunca se editó)
+func marshalList[T any](name string, list []T) (sql.NullString, error) {
+	if len(list) == 0 {
+		return sql.NullString{}, nil
+	}
+
+	data, err := json.Marshal(list)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("error marshalling tweet %s: %w", name, err)
+	}
+
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+// unmarshalList lee una lista guardada con marshalList. NULL deja la lista vacía.
+func unmarshalList[T any](name string, column sql.NullString, list *[]T) error {
+	if !column.Valid || column.String == "" {
+		return nil
+	}
+
+	if err := json.Unmarshal([]byte(column.String), list); err != nil {
+		return fmt.Errorf("error unmarshalling tweet %s: %w", name, err)
+	}
+	return nil
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func nullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t.UTC(), Valid: true}
+}
@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"ChallengeUALA/internal/domain"
+)
+
+// PostgresUserRepository es una implementación de la interfaz UserRepository sobre PostgreSQL.
+// La unicidad del handle la garantiza el índice único sobre lower(handle).
+type PostgresUserRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresUserRepository crea una nueva instancia de PostgresUserRepository
+func NewPostgresUserRepository(db *sql.DB) *PostgresUserRepository {
+	return &PostgresUserRepository{
+		db: db,
+	}
+}
+
+// Create guarda un usuario nuevo. Si el insert choca con el índice del handle no se guarda nada.
+func (r *PostgresUserRepository) Create(ctx context.Context, user *domain.User) error {
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO users (id, handle, display_name, bio, created_at, password_hash)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT DO NOTHING`,
+		user.ID, user.Handle, user.DisplayName, user.Bio, user.CreatedAt, user.PasswordHash,
+	)
+	if err != nil {
+		return fmt.Errorf("error saving user: %w", err)
+	}
+
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error saving user: %w", err)
+	}
+
+	if inserted == 0 {
+		return domain.ErrHandleTaken
+	}
+
+	return nil
+}
+
+// GetByID devuelve un usuario por su ID.
+func (r *PostgresUserRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
+	return r.getUser(ctx, `WHERE id = $1`, id)
+}
+
+// GetByHandle devuelve el usuario con ese handle.
+func (r *PostgresUserRepository) GetByHandle(ctx context.Context, handle string) (*domain.User, error) {
+	return r.getUser(ctx, `WHERE lower(handle) = $1`, domain.NormalizeHandle(handle))
+}
+
+func (r *PostgresUserRepository) getUser(ctx context.Context, where string, arg string) (*domain.User, error) {
+	var user domain.User
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, handle, display_name, bio, created_at, password_hash
+		FROM users `+where, arg,
+	).Scan(&user.ID, &user.Handle, &user.DisplayName, &user.Bio, &user.CreatedAt, &user.PasswordHash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting user: %w", err)
+	}
+
+	user.CreatedAt = user.CreatedAt.UTC()
+	return &user, nil
+}
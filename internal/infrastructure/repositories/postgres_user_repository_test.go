@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"ChallengeUALA/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgresUserRepository_CreateAndGet(t *testing.T) {
+	db, cleanup := setupTestPostgresDB()
+	defer cleanup()
+
+	repo := NewPostgresUserRepository(db)
+	ctx := context.Background()
+
+	user, err := domain.NewUser("Ana_Dev", "Ana", "Hola")
+	assert.NoError(t, err)
+	user.PasswordHash = "hash"
+	assert.NoError(t, repo.Create(ctx, user))
+
+	found, err := repo.GetByID(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, user.Handle, found.Handle)
+	assert.Equal(t, user.DisplayName, found.DisplayName)
+	assert.Equal(t, user.Bio, found.Bio)
+	assert.WithinDuration(t, user.CreatedAt, found.CreatedAt, 0)
+
+	found, err = repo.GetByHandle(ctx, "@ANA_dev")
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID, found.ID)
+	assert.Equal(t, "hash", found.PasswordHash)
+
+	// El handle se compara sin mayúsculas
+	other, err := domain.NewUser("ana_DEV", "", "")
+	assert.NoError(t, err)
+	assert.ErrorIs(t, repo.Create(ctx, other), domain.ErrHandleTaken)
+
+	_, err = repo.GetByID(ctx, other.ID)
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+
+	_, err = repo.GetByHandle(ctx, "beto")
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+}
@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"ChallengeUALA/internal/domain"
+	"github.com/go-redis/redis/v8"
+)
+
+// RedisIdempotencyRepository guarda en idempotency:<key> el fingerprint del pedido y el ID del tweet
+// que creó la key, como <fingerprint>|<ID>, con el ID vacío mientras el pedido original se procesa.
+// Las keys guardadas antes de que existiera el fingerprint tienen solo el ID y aceptan cualquier pedido.
+type RedisIdempotencyRepository struct {
+	client *redis.Client
+}
+
+func NewRedisIdempotencyRepository(client *redis.Client) *RedisIdempotencyRepository {
+	return &RedisIdempotencyRepository{
+		client: client,
+	}
+}
+
+// Reserve toma la key con SETNX, así que de dos pedidos simultáneos con la misma key solo uno la obtiene.
+func (r *RedisIdempotencyRepository) Reserve(ctx context.Context, key, fingerprint string, lockTTL time.Duration) (string, error) {
+	reserved, err := r.client.SetNX(ctx, idempotencyKey(key), idempotencyValue(fingerprint, ""), lockTTL).Result()
+	if err != nil {
+		return "", fmt.Errorf("error reserving idempotency key: %w", err)
+	}
+	if reserved {
+		return "", nil
+	}
+
+	value, err := r.client.Get(ctx, idempotencyKey(key)).Result()
+	if errors.Is(err, redis.Nil) {
+		// La reserva venció entre el SETNX y el GET: se vuelve a intentar
+		return r.Reserve(ctx, key, fingerprint, lockTTL)
+	}
+	if err != nil {
+		return "", fmt.Errorf("error getting idempotency key: %w", err)
+	}
+
+	stored, tweetID, found := strings.Cut(value, "|")
+	if !found {
+		stored, tweetID = "", value
+	}
+
+	if stored != "" && stored != fingerprint {
+		return "", domain.ErrIdempotencyKeyMismatch
+	}
+
+	if tweetID == "" {
+		return "", domain.ErrIdempotencyKeyInUse
+	}
+
+	return tweetID, nil
+}
+
+// Complete guarda el tweet creado por la key.
+func (r *RedisIdempotencyRepository) Complete(ctx context.Context, key, fingerprint, tweetID string, ttl time.Duration) error {
+	if err := r.client.Set(ctx, idempotencyKey(key), idempotencyValue(fingerprint, tweetID), ttl).Err(); err != nil {
+		return fmt.Errorf("error completing idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+// Release borra la reserva de la key.
+func (r *RedisIdempotencyRepository) Release(ctx context.Context, key string) error {
+	if err := r.client.Del(ctx, idempotencyKey(key)).Err(); err != nil {
+		return fmt.Errorf("error releasing idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+func idempotencyKey(key string) string {
+	return "idempotency:" + key
+}
+
+func idempotencyValue(fingerprint, tweetID string) string {
+	return fingerprint + "|" + tweetID
+}
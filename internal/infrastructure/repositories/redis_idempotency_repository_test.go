@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ChallengeUALA/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisIdempotencyRepository_ReserveAndComplete(t *testing.T) {
+	client, cleanup := setupTestRedisClient()
+	defer cleanup()
+
+	repo := NewRedisIdempotencyRepository(client)
+	ctx := context.Background()
+
+	tweetID, err := repo.Reserve(ctx, "user1:key1", "request1", time.Minute)
+	assert.NoError(t, err)
+	assert.Empty(t, tweetID)
+
+	// Mientras el pedido original se procesa, los reintentos no pueden tomar la key
+	_, err = repo.Reserve(ctx, "user1:key1", "request1", time.Minute)
+	assert.ErrorIs(t, err, domain.ErrIdempotencyKeyInUse)
+
+	assert.NoError(t, repo.Complete(ctx, "user1:key1", "request1", "tweet1", 24*time.Hour))
+
+	tweetID, err = repo.Reserve(ctx, "user1:key1", "request1", time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, "tweet1", tweetID)
+
+	ttl, err := client.TTL(ctx, "idempotency:user1:key1").Result()
+	assert.NoError(t, err)
+	assert.Greater(t, ttl, time.Minute)
+
+	// La misma key con otro pedido no devuelve el tweet
+	_, err = repo.Reserve(ctx, "user1:key1", "other", time.Minute)
+	assert.ErrorIs(t, err, domain.ErrIdempotencyKeyMismatch)
+
+	// Otra key es independiente
+	tweetID, err = repo.Reserve(ctx, "user2:key1", "request2", time.Minute)
+	assert.NoError(t, err)
+	assert.Empty(t, tweetID)
+}
+
+func TestRedisIdempotencyRepository_Release(t *testing.T) {
+	client, cleanup := setupTestRedisClient()
+	defer cleanup()
+
+	repo := NewRedisIdempotencyRepository(client)
+	ctx := context.Background()
+
+	_, err := repo.Reserve(ctx, "user1:key1", "request1", time.Minute)
+	assert.NoError(t, err)
+	assert.NoError(t, repo.Release(ctx, "user1:key1"))
+
+	tweetID, err := repo.Reserve(ctx, "user1:key1", "request1", time.Minute)
+	assert.NoError(t, err)
+	assert.Empty(t, tweetID)
+}
+
+func TestRedisIdempotencyRepository_KeysWithoutFingerprint(t *testing.T) {
+	client, cleanup := setupTestRedisClient()
+	defer cleanup()
+
+	repo := NewRedisIdempotencyRepository(client)
+	ctx := context.Background()
+
+	// Las keys guardadas antes del fingerprint solo tienen el ID del tweet
+	assert.NoError(t, client.Set(ctx, "idempotency:user1:key1", "tweet1", time.Hour).Err())
+
+	tweetID, err := repo.Reserve(ctx, "user1:key1", "request1", time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, "tweet1", tweetID)
+}
@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// likeCountsKey es el hash con la cantidad de likes de cada tweet
+const likeCountsKey = "like_counts"
+
+// likeScript agrega el like y suma uno al contador solo si el like no existía
+var likeScript = redis.NewScript(`
+if redis.call('ZADD', KEYS[1], 'NX', 0, ARGV[1]) == 1 then
+	redis.call('HINCRBY', KEYS[2], ARGV[2], 1)
+	return 1
+end
+return 0
+`)
+
+// unlikeScript saca el like y resta uno al contador solo si el like existía
+var unlikeScript = redis.NewScript(`
+if redis.call('ZREM', KEYS[1], ARGV[1]) == 1 then
+	if redis.call('HINCRBY', KEYS[2], ARGV[2], -1) <= 0 then
+		redis.call('HDEL', KEYS[2], ARGV[2])
+	end
+	return 1
+end
+return 0
+`)
+
+// RedisLikeRepository guarda los likes en Redis. Los usuarios que le dieron like a un tweet
+// están en el sorted set likes:<tweetID> (todos con score 0, así se pagina por orden de ID)
+// y la cantidad en el hash like_counts, que se lee para muchos tweets en un solo HMGET.
+type RedisLikeRepository struct {
+	client *redis.Client
+}
+
+func NewRedisLikeRepository(client *redis.Client) *RedisLikeRepository {
+	return &RedisLikeRepository{
+		client: client,
+	}
+}
+
+// Like registra el like de un usuario a un tweet.
+func (r *RedisLikeRepository) Like(ctx context.Context, tweetID, userID string) (bool, error) {
+	added, err := likeScript.Run(ctx, r.client, []string{"likes:" + tweetID, likeCountsKey}, userID, tweetID).Int()
+	if err != nil {
+		return false, fmt.Errorf("error liking tweet: %w", err)
+	}
+
+	return added == 1, nil
+}
+
+// Unlike saca el like de un usuario a un tweet.
+func (r *RedisLikeRepository) Unlike(ctx context.Context, tweetID, userID string) (bool, error) {
+	removed, err := unlikeScript.Run(ctx, r.client, []string{"likes:" + tweetID, likeCountsKey}, userID, tweetID).Int()
+	if err != nil {
+		return false, fmt.Errorf("error unliking tweet: %w", err)
+	}
+
+	return removed == 1, nil
+}
+
+// CountLikes devuelve la cantidad de likes de cada tweet que tiene alguno.
+func (r *RedisLikeRepository) CountLikes(ctx context.Context, tweetIDs []string) (map[string]int, error) {
+	counts := make(map[string]int)
+	if len(tweetIDs) == 0 {
+		return counts, nil
+	}
+
+	values, err := r.client.HMGet(ctx, likeCountsKey, tweetIDs...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("error counting likes: %w", err)
+	}
+
+	for i, value := range values {
+		raw, ok := value.(string)
+		if !ok {
+			continue // el tweet no tiene likes
+		}
+		count, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing like count: %w", err)
+		}
+		if count > 0 {
+			counts[tweetIDs[i]] = count
+		}
+	}
+
+	return counts, nil
+}
+
+// ListLikers devuelve una página de los usuarios que le dieron like a un tweet ordenados por ID.
+func (r *RedisLikeRepository) ListLikers(ctx context.Context, tweetID, afterID string, limit int) ([]string, error) {
+	min := "-"
+	if afterID != "" {
+		min = "(" + afterID
+	}
+
+	users, err := r.client.ZRangeByLex(ctx, "likes:"+tweetID, &redis.ZRangeBy{
+		Min:   min,
+		Max:   "+",
+		Count: int64(limit),
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("error listing likes: %w", err)
+	}
+
+	return users, nil
+}
@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisLikeRepository_LikeAndUnlike(t *testing.T) {
+	client, cleanup := setupTestRedisClient()
+	defer cleanup()
+
+	repo := NewRedisLikeRepository(client)
+	ctx := context.Background()
+
+	added, err := repo.Like(ctx, "tweet1", "user1")
+	assert.NoError(t, err)
+	assert.True(t, added)
+
+	// Un segundo like del mismo usuario no suma al contador
+	added, err = repo.Like(ctx, "tweet1", "user1")
+	assert.NoError(t, err)
+	assert.False(t, added)
+
+	_, err = repo.Like(ctx, "tweet1", "user2")
+	assert.NoError(t, err)
+
+	counts, err := repo.CountLikes(ctx, []string{"tweet1", "tweet2"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"tweet1": 2}, counts)
+
+	removed, err := repo.Unlike(ctx, "tweet1", "user1")
+	assert.NoError(t, err)
+	assert.True(t, removed)
+
+	removed, err = repo.Unlike(ctx, "tweet1", "user1")
+	assert.NoError(t, err)
+	assert.False(t, removed)
+
+	_, err = repo.Unlike(ctx, "tweet1", "user2")
+	assert.NoError(t, err)
+
+	// Sin likes el contador se borra del hash
+	counts, err = repo.CountLikes(ctx, []string{"tweet1"})
+	assert.NoError(t, err)
+	assert.Empty(t, counts)
+	assert.False(t, client.HExists(ctx, likeCountsKey, "tweet1").Val())
+}
+
+func TestRedisLikeRepository_ListLikers(t *testing.T) {
+	client, cleanup := setupTestRedisClient()
+	defer cleanup()
+
+	repo := NewRedisLikeRepository(client)
+	ctx := context.Background()
+
+	for _, userID := range []string{"c", "a", "b"} {
+		_, err := repo.Like(ctx, "tweet1", userID)
+		assert.NoError(t, err)
+	}
+
+	users, err := repo.ListLikers(ctx, "tweet1", "", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, users)
+
+	users, err = repo.ListLikers(ctx, "tweet1", "b", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"c"}, users)
+
+	users, err = repo.ListLikers(ctx, "tweet2", "", 2)
+	assert.NoError(t, err)
+	assert.Empty(t, users)
+}
@@ -0,0 +1,153 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"ChallengeUALA/internal/domain"
+	"github.com/go-redis/redis/v8"
+)
+
+// Se guardan las últimas maxInboxSize notificaciones de cada usuario durante inboxTTL desde la última
+const (
+	maxInboxSize = 200
+	inboxTTL     = 30 * 24 * time.Hour
+)
+
+// addNotificationScript agrega la notificación al inbox y, si es nueva, a las no leídas. Después
+// recorta el inbox y saca de las no leídas las que quedaron afuera del recorte.
+var addNotificationScript = redis.NewScript(`
+if redis.call('ZADD', KEYS[1], ARGV[1], ARGV[2]) == 1 then
+	redis.call('ZADD', KEYS[2], ARGV[1], ARGV[3])
+end
+redis.call('ZREMRANGEBYRANK', KEYS[1], 0, -(tonumber(ARGV[4]) + 1))
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+if oldest[2] then
+	redis.call('ZREMRANGEBYSCORE', KEYS[2], '-inf', '(' .. oldest[2])
+end
+redis.call('EXPIRE', KEYS[1], ARGV[5])
+redis.call('EXPIRE', KEYS[2], ARGV[5])
+return 1
+`)
+
+// RedisNotificationRepository guarda el inbox de cada usuario en el sorted set notifications:<userID>,
+// con las notificaciones serializadas y ordenadas por fecha en microsegundos, y los IDs de las que
+// todavía no leyó en notifications_unread:<userID>.
+type RedisNotificationRepository struct {
+	client *redis.Client
+}
+
+func NewRedisNotificationRepository(client *redis.Client) *RedisNotificationRepository {
+	return &RedisNotificationRepository{
+		client: client,
+	}
+}
+
+// Add guarda la notificación en el inbox de su destinatario.
+func (r *RedisNotificationRepository) Add(ctx context.Context, notification *domain.Notification) error {
+	stored := *notification
+	stored.Read = false
+
+	notificationJSON, err := json.Marshal(stored)
+	if err != nil {
+		return fmt.Errorf("error marshalling notification: %w", err)
+	}
+
+	keys := []string{notificationsKey(notification.UserID), unreadNotificationsKey(notification.UserID)}
+	err = addNotificationScript.Run(ctx, r.client, keys,
+		notification.CreatedAt.UnixMicro(), notificationJSON, notification.ID, maxInboxSize, int(inboxTTL.Seconds()),
+	).Err()
+	if err != nil {
+		return fmt.Errorf("error adding notification: %w", err)
+	}
+
+	return nil
+}
+
+// List devuelve una página del inbox marcando cuáles ya se leyeron.
+func (r *RedisNotificationRepository) List(ctx context.Context, userID string, before time.Time, limit int) ([]*domain.Notification, error) {
+	max := "+inf"
+	if !before.IsZero() {
+		max = "(" + strconv.FormatInt(before.UnixMicro(), 10)
+	}
+
+	members, err := r.client.ZRevRangeByScore(ctx, notificationsKey(userID), &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   max,
+		Count: int64(limit),
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("error getting notifications: %w", err)
+	}
+
+	notifications := make([]*domain.Notification, 0, len(members))
+	for _, member := range members {
+		var notification domain.Notification
+		if err := json.Unmarshal([]byte(member), &notification); err != nil {
+			return nil, fmt.Errorf("error unmarshalling notification: %w", err)
+		}
+		notifications = append(notifications, &notification)
+	}
+
+	if len(notifications) == 0 {
+		return notifications, nil
+	}
+
+	scores := make([]*redis.FloatCmd, 0, len(notifications))
+	_, err = r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, notification := range notifications {
+			scores = append(scores, pipe.ZScore(ctx, unreadNotificationsKey(userID), notification.ID))
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("error getting unread notifications: %w", err)
+	}
+
+	for i, notification := range notifications {
+		notification.Read = errors.Is(scores[i].Err(), redis.Nil)
+	}
+
+	return notifications, nil
+}
+
+// CountUnread devuelve cuántas notificaciones del inbox no se leyeron.
+func (r *RedisNotificationRepository) CountUnread(ctx context.Context, userID string) (int, error) {
+	count, err := r.client.ZCard(ctx, unreadNotificationsKey(userID)).Result()
+	if err != nil {
+		return 0, fmt.Errorf("error counting unread notifications: %w", err)
+	}
+
+	return int(count), nil
+}
+
+// MarkRead marca notificaciones como leídas. Marcar una que ya se leyó o que no existe no es un error.
+func (r *RedisNotificationRepository) MarkRead(ctx context.Context, userID string, ids []string) error {
+	var err error
+	if len(ids) == 0 {
+		err = r.client.Del(ctx, unreadNotificationsKey(userID)).Err()
+	} else {
+		members := make([]interface{}, len(ids))
+		for i, id := range ids {
+			members[i] = id
+		}
+		err = r.client.ZRem(ctx, unreadNotificationsKey(userID), members...).Err()
+	}
+	if err != nil {
+		return fmt.Errorf("error marking notifications as read: %w", err)
+	}
+
+	return nil
+}
+
+func notificationsKey(userID string) string {
+	return "notifications:" + userID
+}
+
+func unreadNotificationsKey(userID string) string {
+	return "notifications_unread:" + userID
+}
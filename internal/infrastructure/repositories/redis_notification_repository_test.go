@@ -0,0 +1,107 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ChallengeUALA/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisNotificationRepository_AddAndList(t *testing.T) {
+	client, cleanup := setupTestRedisClient()
+	defer cleanup()
+
+	repo := NewRedisNotificationRepository(client)
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	var added []*domain.Notification
+	for i, notificationType := range []string{domain.NotificationFollow, domain.NotificationLike, domain.NotificationMention} {
+		notification := domain.NewNotification(notificationType, "user1", "user2", "")
+		notification.CreatedAt = now.Add(time.Duration(i) * time.Second)
+		assert.NoError(t, repo.Add(ctx, notification))
+		added = append(added, notification)
+	}
+
+	// Reprocesar el mismo mensaje no duplica la notificación
+	assert.NoError(t, repo.Add(ctx, added[2]))
+
+	unread, err := repo.CountUnread(ctx, "user1")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, unread)
+
+	page, err := repo.List(ctx, "user1", time.Time{}, 2)
+	assert.NoError(t, err)
+	if assert.Len(t, page, 2) {
+		assert.Equal(t, added[2].ID, page[0].ID)
+		assert.Equal(t, added[1].ID, page[1].ID)
+		assert.False(t, page[0].Read)
+	}
+
+	page, err = repo.List(ctx, "user1", page[1].CreatedAt, 2)
+	assert.NoError(t, err)
+	if assert.Len(t, page, 1) {
+		assert.Equal(t, added[0].ID, page[0].ID)
+	}
+
+	page, err = repo.List(ctx, "user3", time.Time{}, 2)
+	assert.NoError(t, err)
+	assert.Empty(t, page)
+}
+
+func TestRedisNotificationRepository_MarkRead(t *testing.T) {
+	client, cleanup := setupTestRedisClient()
+	defer cleanup()
+
+	repo := NewRedisNotificationRepository(client)
+	ctx := context.Background()
+
+	first := domain.NewNotification(domain.NotificationFollow, "user1", "user2", "")
+	second := domain.NewNotification(domain.NotificationReply, "user1", "user3", "tweet1")
+	second.CreatedAt = first.CreatedAt.Add(time.Second)
+	assert.NoError(t, repo.Add(ctx, first))
+	assert.NoError(t, repo.Add(ctx, second))
+
+	assert.NoError(t, repo.MarkRead(ctx, "user1", []string{first.ID, "missing"}))
+
+	page, err := repo.List(ctx, "user1", time.Time{}, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, page, 2) {
+		assert.False(t, page[0].Read)
+		assert.True(t, page[1].Read)
+	}
+
+	// Una notificación ya leída que se vuelve a consumir sigue leída
+	assert.NoError(t, repo.Add(ctx, first))
+	unread, err := repo.CountUnread(ctx, "user1")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, unread)
+
+	assert.NoError(t, repo.MarkRead(ctx, "user1", nil))
+	unread, err = repo.CountUnread(ctx, "user1")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, unread)
+}
+
+func TestRedisNotificationRepository_TrimsInbox(t *testing.T) {
+	client, cleanup := setupTestRedisClient()
+	defer cleanup()
+
+	repo := NewRedisNotificationRepository(client)
+	ctx := context.Background()
+
+	start := time.Now().UTC()
+	for i := 0; i < maxInboxSize+5; i++ {
+		notification := domain.NewNotification(domain.NotificationLike, "user1", "user2", "tweet1")
+		notification.CreatedAt = start.Add(time.Duration(i) * time.Millisecond)
+		assert.NoError(t, repo.Add(ctx, notification))
+	}
+
+	// Las no leídas que se recortaron del inbox tampoco se cuentan
+	assert.Equal(t, int64(maxInboxSize), client.ZCard(ctx, notificationsKey("user1")).Val())
+	unread, err := repo.CountUnread(ctx, "user1")
+	assert.NoError(t, err)
+	assert.Equal(t, maxInboxSize, unread)
+}
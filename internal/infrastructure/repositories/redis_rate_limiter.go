@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"ChallengeUALA/internal/domain"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+// slidingWindowScript saca de la ventana los pedidos viejos y, si queda lugar, agrega el nuevo.
+// Devuelve si se permitió, cuántos lugares quedan y en cuántos microsegundos se libera el próximo.
+var slidingWindowScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+local count = redis.call('ZCARD', KEYS[1])
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', KEYS[1], now, ARGV[4])
+	redis.call('PEXPIRE', KEYS[1], math.ceil(window / 1000))
+	count = count + 1
+	allowed = 1
+end
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+local reset = 0
+if oldest[2] then
+	reset = tonumber(oldest[2]) + window - now
+end
+return {allowed, limit - count, reset}
+`)
+
+// RedisRateLimiter implementa una ventana deslizante en el sorted set ratelimit:<key>, con un
+// miembro por pedido aceptado y la fecha en microsegundos como score. Como vive en Redis, el límite
+// se comparte entre todas las instancias de la API.
+type RedisRateLimiter struct {
+	client *redis.Client
+	now    func() time.Time
+}
+
+func NewRedisRateLimiter(client *redis.Client) *RedisRateLimiter {
+	return &RedisRateLimiter{
+		client: client,
+		now:    time.Now,
+	}
+}
+
+// Allow consume un pedido de key si en la última window hubo menos de limit.
+func (r *RedisRateLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (domain.RateLimitDecision, error) {
+	result, err := slidingWindowScript.Run(ctx, r.client, []string{rateLimitKey(key)},
+		r.now().UnixMicro(), window.Microseconds(), limit, uuid.NewString(),
+	).Int64Slice()
+	if err != nil {
+		return domain.RateLimitDecision{}, fmt.Errorf("error checking rate limit: %w", err)
+	}
+
+	return domain.RateLimitDecision{
+		Allowed:    result[0] == 1,
+		Limit:      limit,
+		Remaining:  int(result[1]),
+		ResetAfter: time.Duration(result[2]) * time.Microsecond,
+	}, nil
+}
+
+func rateLimitKey(key string) string {
+	return "ratelimit:" + key
+}
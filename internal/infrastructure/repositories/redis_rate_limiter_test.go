@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisRateLimiter_Allow(t *testing.T) {
+	client, cleanup := setupTestRedisClient()
+	defer cleanup()
+
+	limiter := NewRedisRateLimiter(client)
+	ctx := context.Background()
+
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		decision, err := limiter.Allow(ctx, "tweets:user1", 3, time.Minute)
+		assert.NoError(t, err)
+		assert.True(t, decision.Allowed)
+		assert.Equal(t, 3, decision.Limit)
+		assert.Equal(t, 2-i, decision.Remaining)
+	}
+
+	// El cuarto pedido se rechaza hasta que el primero sale de la ventana
+	limiter.now = func() time.Time { return now.Add(40 * time.Second) }
+	decision, err := limiter.Allow(ctx, "tweets:user1", 3, time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, decision.Allowed)
+	assert.Equal(t, 0, decision.Remaining)
+	assert.Equal(t, 20*time.Second, decision.ResetAfter)
+
+	// Cada usuario y cada ruta tienen su propia ventana
+	decision, err = limiter.Allow(ctx, "tweets:user2", 3, time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, decision.Allowed)
+	decision, err = limiter.Allow(ctx, "follow:user1", 3, time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, decision.Allowed)
+
+	// Los pedidos rechazados no cuentan: al salir los tres primeros de la ventana vuelve a haber lugar
+	limiter.now = func() time.Time { return now.Add(time.Minute + time.Second) }
+	decision, err = limiter.Allow(ctx, "tweets:user1", 3, time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, decision.Allowed)
+	assert.Equal(t, 2, decision.Remaining)
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"strconv"
 	"time"
 
 	"ChallengeUALA/internal/domain"
@@ -21,52 +22,411 @@ func NewRedisRepository(client *redis.Client) *RedisRepository {
 	}
 }
 
-// AddToTimeline agrega un tweet al timeline de un usuario usando LPUSH.
+// timelineBatchSize es la cantidad de timelines que se actualizan por pipeline (un round trip)
+const timelineBatchSize = 1000
+
+// maxTimelineLength es la cantidad de tweets que se guardan en cada sorted set de tweets
+const maxTimelineLength = 500
+
+// timelineTTL es cuánto vive un timeline sin recibir tweets nuevos
+const timelineTTL = 7 * 24 * time.Hour
+
+// tweetIndexLua tiene las funciones comunes a los scripts que escriben sorted sets de tweets. Cada set
+// tiene al lado un hash <set>:index con el miembro serializado de cada tweet por ID, para quitarlo o
+// reemplazarlo sin recorrer el set, y con content:<ID> -> cantidad de miembros que muestran ese tweet
+// (él mismo o sus retweets). Los sets guardados antes de que existiera el índice lo arman la primera
+// vez que se tocan.
+const tweetIndexLua = `
+local function decode(member)
+	local ok, tweet = pcall(cjson.decode, member)
+	if ok then
+		return tweet
+	end
+	return nil
+end
+
+local function content_id(tweet)
+	if tweet.Kind == 'retweet' then
+		return tweet.ReferencedTweetID
+	end
+	return tweet.ID
+end
+
+local function remember(index, member)
+	local tweet = decode(member)
+	if tweet then
+		redis.call('HSET', index, tweet.ID, member)
+		redis.call('HINCRBY', index, 'content:' .. content_id(tweet), 1)
+	end
+end
+
+local function forget(index, member)
+	local tweet = decode(member)
+	if tweet then
+		redis.call('HDEL', index, tweet.ID)
+		local field = 'content:' .. content_id(tweet)
+		if redis.call('HINCRBY', index, field, -1) <= 0 then
+			redis.call('HDEL', index, field)
+		end
+	end
+end
+
+local function ensure_index(set, index)
+	if redis.call('EXISTS', index) == 1 then
+		return
+	end
+	for _, member in ipairs(redis.call('ZRANGE', set, 0, -1)) do
+		remember(index, member)
+	end
+	local ttl = redis.call('PTTL', set)
+	if ttl > 0 then
+		redis.call('PEXPIRE', index, ttl)
+	end
+end
+`
+
+// addTweetScript agrega un tweet a cada par de KEYS (set, índice), reemplazando la versión anterior
+// si ya estaba, recorta el set a ARGV[4] tweets y, si ARGV[5] no es 0, lo vence a los ARGV[5]
+// segundos. Con ARGV[6] = 1 no se agrega a los sets que ya muestran el tweet con ID ARGV[7].
+var addTweetScript = redis.NewScript(tweetIndexLua + `
+for i = 1, #KEYS, 2 do
+	local set, index = KEYS[i], KEYS[i + 1]
+	ensure_index(set, index)
+	if ARGV[6] ~= '1' or redis.call('HEXISTS', index, 'content:' .. ARGV[7]) == 0 then
+		local previous = redis.call('HGET', index, ARGV[3])
+		if previous then
+			redis.call('ZREM', set, previous)
+			forget(index, previous)
+		end
+		redis.call('ZADD', set, ARGV[1], ARGV[2])
+		remember(index, ARGV[2])
+		local excess = redis.call('ZCARD', set) - tonumber(ARGV[4])
+		if excess > 0 then
+			for _, member in ipairs(redis.call('ZRANGE', set, 0, excess - 1)) do
+				forget(index, member)
+			end
+			redis.call('ZREMRANGEBYRANK', set, 0, excess - 1)
+		end
+		if ARGV[5] ~= '0' then
+			redis.call('EXPIRE', set, ARGV[5])
+			redis.call('EXPIRE', index, ARGV[5])
+		end
+	end
+end
+return 0
+`)
+
+// rewriteTweetScript quita el tweet ARGV[1] de cada par de KEYS (set, índice) y, si ARGV[2] no está
+// vacío, agrega en su lugar esa versión con el mismo score. Los sets que no lo tienen no se tocan.
+var rewriteTweetScript = redis.NewScript(tweetIndexLua + `
+for i = 1, #KEYS, 2 do
+	local set, index = KEYS[i], KEYS[i + 1]
+	ensure_index(set, index)
+	local member = redis.call('HGET', index, ARGV[1])
+	if member then
+		local score = redis.call('ZSCORE', set, member)
+		redis.call('ZREM', set, member)
+		forget(index, member)
+		if score and ARGV[2] ~= '' then
+			redis.call('ZADD', set, score, ARGV[2])
+			remember(index, ARGV[2])
+		end
+	end
+end
+return 0
+`)
+
+// removeAuthorScript quita del set KEYS[1] (con su índice KEYS[2]) los tweets publicados por ARGV[1].
+var removeAuthorScript = redis.NewScript(tweetIndexLua + `
+ensure_index(KEYS[1], KEYS[2])
+for _, member in ipairs(redis.call('ZRANGE', KEYS[1], 0, -1)) do
+	local tweet = decode(member)
+	if tweet and tweet.UserID == ARGV[1] then
+		redis.call('ZREM', KEYS[1], member)
+		forget(KEYS[2], member)
+	end
+end
+return 0
+`)
+
+// AddToTimeline agrega un tweet al timeline de un usuario.
 func (r *RedisRepository) AddToTimeline(ctx context.Context, userID string, tweet *domain.Tweet) error {
-	// Serializar el tweet a JSON
+	return r.AddToTimelines(ctx, []string{userID}, tweet)
+}
+
+// AddToTimelines agrega un tweet a los timelines de varios usuarios. Cada llamada al script actualiza
+// timelineBatchSize timelines, así que el fan-out a 10k seguidores son 10 round trips en vez de 30k.
+// Si un timeline ya tenía el tweet (por un evento repetido) se reemplaza en vez de duplicarlo.
+func (r *RedisRepository) AddToTimelines(ctx context.Context, userIDs []string, tweet *domain.Tweet) error {
+	if err := r.addTweet(ctx, timelineKeys(userIDs), tweet, timelineTTL, false); err != nil {
+		return fmt.Errorf("error adding tweet to timelines: %w", err)
+	}
+
+	return nil
+}
+
+// AddRetweetToTimelines agrega un retweet a los timelines de varios usuarios, salvo a los que ya
+// tienen el tweet original u otro retweet del mismo original. El índice de cada timeline cuenta qué
+// tweets muestra, así que no hace falta leer los timelines.
+func (r *RedisRepository) AddRetweetToTimelines(ctx context.Context, userIDs []string, retweet *domain.Tweet) error {
+	if err := r.addTweet(ctx, timelineKeys(userIDs), retweet, timelineTTL, true); err != nil {
+		return fmt.Errorf("error adding retweet to timelines: %w", err)
+	}
+
+	return nil
+}
+
+// addTweet agrega el tweet a los sorted sets keys con addTweetScript, en llamadas de
+// timelineBatchSize sets. Con ttl 0 los sets no vencen; con skipShown no se agrega a los sets que
+// ya muestran el mismo tweet.
+func (r *RedisRepository) addTweet(ctx context.Context, keys []string, tweet *domain.Tweet, ttl time.Duration, skipShown bool) error {
 	tweetJSON, err := json.Marshal(tweet)
 	if err != nil {
 		return fmt.Errorf("error marshalling tweet: %w", err)
 	}
 
-	// Agrega el tweet al inicio de la lista del timeline del usuario
-	// ordenado por la fecha del tweet
-	err = r.client.ZAdd(ctx, "timeline:"+userID, &redis.Z{
-		Score:  float64(tweet.CreatedAt.Unix()),
-		Member: tweetJSON,
-	}).Err()
-	if err != nil {
-		return fmt.Errorf("error adding tweet to timeline: %w", err)
+	skip := "0"
+	if skipShown {
+		skip = "1"
 	}
 
-	// Limitamos el timeline a los últimos 500 tweets
-	err = r.client.ZRemRangeByRank(ctx, "timeline:"+userID, 0, -501).Err()
-	if err != nil {
-		return fmt.Errorf("error trimming timeline: %w", err)
+	return runOnTweetSets(ctx, r.client, addTweetScript, keys,
+		tweet.CreatedAt.Unix(), tweetJSON, tweet.ID, maxTimelineLength, int64(ttl.Seconds()), skip, tweet.ContentID())
+}
+
+// RemoveAuthorFromTimeline quita del timeline de un usuario todos los tweets publicados por authorID.
+// Como el índice es por tweet y no por autor, el script recorre el timeline (acotado a 500 elementos).
+func (r *RedisRepository) RemoveAuthorFromTimeline(ctx context.Context, userID, authorID string) error {
+	key := "timeline:" + userID
+	if err := removeAuthorScript.Run(ctx, r.client, []string{key, tweetIndexKey(key)}, authorID).Err(); err != nil {
+		return fmt.Errorf("error removing tweets from timeline: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveFromTimelines quita un tweet de los timelines de varios usuarios.
+func (r *RedisRepository) RemoveFromTimelines(ctx context.Context, userIDs []string, tweetID string) error {
+	if err := r.rewriteTweet(ctx, timelineKeys(userIDs), tweetID, nil); err != nil {
+		return fmt.Errorf("error removing tweet from timelines: %w", err)
+	}
+
+	return nil
+}
+
+// ReplaceInTimelines reemplaza la versión guardada de un tweet editado en los timelines de varios
+// usuarios. Los timelines que no tienen el tweet (porque ya salió de los últimos 500) no se tocan.
+func (r *RedisRepository) ReplaceInTimelines(ctx context.Context, userIDs []string, tweet *domain.Tweet) error {
+	if err := r.rewriteTweet(ctx, timelineKeys(userIDs), tweet.ID, tweet); err != nil {
+		return fmt.Errorf("error replacing tweet in timelines: %w", err)
 	}
 
-	// expiración en 7 días
-	err = r.client.Expire(ctx, "timeline:"+userID, 7*24*time.Hour).Err()
+	return nil
+}
+
+// GetTimeline devuelve una página del timeline de un usuario, del tweet más nuevo al más viejo.
+func (r *RedisRepository) GetTimeline(ctx context.Context, userID, cursor string, limit int) (*domain.TimelinePage, error) {
+	page, err := r.getPage(ctx, "timeline:"+userID, cursor, limit)
 	if err != nil {
-		return fmt.Errorf("error setting expiration: %w", err)
+		return nil, err
+	}
+
+	log.Printf("Retrieved %d tweets for user %s", len(page.Tweets), userID)
+	return page, nil
+}
+
+// AddToCelebrityTweets guarda un tweet en la lista de tweets recientes de su autor. Los seguidores
+// lo leen al pedir su timeline en vez de recibirlo por fan-out.
+func (r *RedisRepository) AddToCelebrityTweets(ctx context.Context, tweet *domain.Tweet) error {
+	// Mismo límite que los timelines, pero sin vencimiento
+	if err := r.addTweet(ctx, []string{"celebrity_tweets:" + tweet.UserID}, tweet, 0, false); err != nil {
+		return fmt.Errorf("error adding tweet to celebrity tweets: %w", err)
 	}
 
 	return nil
 }
 
-func (r *RedisRepository) GetTimeline(ctx context.Context, userID string) ([]*domain.Tweet, error) {
-	// le devuelvo los últimos 100 tweets del timeline
-	fmt.Println("Getting Timeline: ", userID)
-	tweetsJSON, err := r.client.ZRevRange(ctx, "timeline:"+userID, 0, 99).Result()
+// GetCelebrities devuelve cuáles de los usuarios pedidos tienen tweets recientes de celebridad. No hay
+// un set global de celebridades que mantener: Redis borra celebrity_tweets:<id> cuando queda vacío, así
+// que alcanza con ver qué claves existen, en pipelines de timelineBatchSize usuarios.
+func (r *RedisRepository) GetCelebrities(ctx context.Context, userIDs []string) ([]string, error) {
+	celebrities := make([]string, 0)
+	for start := 0; start < len(userIDs); start += timelineBatchSize {
+		end := start + timelineBatchSize
+		if end > len(userIDs) {
+			end = len(userIDs)
+		}
+
+		batch := userIDs[start:end]
+		exists := make([]*redis.IntCmd, len(batch))
+		_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for i, userID := range batch {
+				exists[i] = pipe.Exists(ctx, "celebrity_tweets:"+userID)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error getting celebrities: %w", err)
+		}
+
+		for i, userID := range batch {
+			if exists[i].Val() > 0 {
+				celebrities = append(celebrities, userID)
+			}
+		}
+	}
+
+	return celebrities, nil
+}
+
+// GetCelebrityTweets devuelve una página de los tweets recientes de una celebridad,
+// con el mismo orden y cursor que GetTimeline para poder mezclarlos.
+func (r *RedisRepository) GetCelebrityTweets(ctx context.Context, authorID, cursor string, limit int) (*domain.TimelinePage, error) {
+	return r.getPage(ctx, "celebrity_tweets:"+authorID, cursor, limit)
+}
+
+// RemoveFromCelebrityTweets quita un tweet de los tweets recientes de una celebridad.
+func (r *RedisRepository) RemoveFromCelebrityTweets(ctx context.Context, authorID, tweetID string) error {
+	if err := r.rewriteTweet(ctx, []string{"celebrity_tweets:" + authorID}, tweetID, nil); err != nil {
+		return fmt.Errorf("error removing tweet from celebrity tweets: %w", err)
+	}
+
+	return nil
+}
+
+// ReplaceInCelebrityTweets reemplaza la versión guardada de un tweet editado en los tweets recientes de su autor.
+func (r *RedisRepository) ReplaceInCelebrityTweets(ctx context.Context, tweet *domain.Tweet) error {
+	if err := r.rewriteTweet(ctx, []string{"celebrity_tweets:" + tweet.UserID}, tweet.ID, tweet); err != nil {
+		return fmt.Errorf("error replacing tweet in celebrity tweets: %w", err)
+	}
+
+	return nil
+}
+
+// rewriteTweet saca el tweet tweetID de los sorted sets keys y, si replacement no es nil, agrega en su
+// lugar la versión nueva con el mismo score. El miembro guardado se busca por ID en el índice de cada
+// set, así que no importa si su JSON no coincide byte a byte con el del evento (por ejemplo, si la
+// fecha volvió de la base en otra zona).
+func (r *RedisRepository) rewriteTweet(ctx context.Context, keys []string, tweetID string, replacement *domain.Tweet) error {
+	replacementJSON := []byte{}
+	if replacement != nil {
+		var err error
+		replacementJSON, err = json.Marshal(replacement)
+		if err != nil {
+			return fmt.Errorf("error marshalling tweet: %w", err)
+		}
+	}
+
+	return runOnTweetSets(ctx, r.client, rewriteTweetScript, keys, tweetID, replacementJSON)
+}
+
+// runOnTweetSets corre script sobre los sorted sets keys y sus índices, en llamadas de
+// timelineBatchSize sets (un round trip cada una).
+func runOnTweetSets(ctx context.Context, client *redis.Client, script *redis.Script, keys []string, args ...interface{}) error {
+	for start := 0; start < len(keys); start += timelineBatchSize {
+		end := start + timelineBatchSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		pairs := make([]string, 0, 2*(end-start))
+		for _, key := range keys[start:end] {
+			pairs = append(pairs, key, tweetIndexKey(key))
+		}
+
+		if err := script.Run(ctx, client, pairs, args...).Err(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func tweetIndexKey(key string) string {
+	return key + ":index"
+}
+
+func timelineKeys(userIDs []string) []string {
+	keys := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		keys = append(keys, "timeline:"+userID)
+	}
+	return keys
+}
+
+// getPage devuelve una página de un sorted set de tweets ordenado como un timeline.
+// Redis ordena los empates de score por el JSON del tweet, así que los empates se
+// traen completos y se reordenan por ID para respetar domain.TimelineBefore.
+func (r *RedisRepository) getPage(ctx context.Context, key, cursor string, limit int) (*domain.TimelinePage, error) {
+	candidates := make([]*domain.Tweet, 0)
+	maxScore := "+inf"
+
+	if cursor != "" {
+		unix, tweetID, err := domain.DecodeTimelineCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		score := strconv.FormatInt(unix, 10)
+
+		// Del segundo del cursor solo quedan los tweets con ID menor
+		tied, err := r.rangeByScore(ctx, key, score, score, 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, tweet := range tied {
+			if tweet.ID < tweetID {
+				candidates = append(candidates, tweet)
+			}
+		}
+		maxScore = "(" + score
+	}
+
+	// Pedimos un elemento de más para saber si hay una página siguiente
+	rest, err := r.rangeByScore(ctx, key, "-inf", maxScore, int64(limit)+1)
 	if err != nil {
-		return nil, fmt.Errorf("error getting timeline: %w", err)
+		return nil, err
 	}
 
-	if len(tweetsJSON) == 0 {
-		return nil, fmt.Errorf("timeline is empty for user %s", userID)
+	// Si el último segundo quedó cortado, lo traemos completo para ordenar bien los empates
+	if len(rest) > limit {
+		last := rest[len(rest)-1].CreatedAt.Unix()
+		score := strconv.FormatInt(last, 10)
+		tied, err := r.rangeByScore(ctx, key, score, score, 0)
+		if err != nil {
+			return nil, err
+		}
+		for len(rest) > 0 && rest[len(rest)-1].CreatedAt.Unix() == last {
+			rest = rest[:len(rest)-1]
+		}
+		rest = append(rest, tied...)
+	}
+
+	candidates = append(candidates, rest...)
+	domain.SortTimeline(candidates)
+
+	page := &domain.TimelinePage{Tweets: candidates}
+	if len(candidates) > limit {
+		page.Tweets = candidates[:limit]
+		page.NextCursor = domain.EncodeTimelineCursor(page.Tweets[len(page.Tweets)-1])
+	}
+
+	return page, nil
+}
+
+// rangeByScore devuelve los tweets con score entre min y max, de mayor a menor.
+// Con count 0 los devuelve todos.
+func (r *RedisRepository) rangeByScore(ctx context.Context, key, min, max string, count int64) ([]*domain.Tweet, error) {
+	opt := &redis.ZRangeBy{Min: min, Max: max}
+	if count > 0 {
+		opt.Count = count
+	}
+
+	tweetsJSON, err := r.client.ZRevRangeByScore(ctx, key, opt).Result()
+	if err != nil {
+		return nil, fmt.Errorf("error getting timeline: %w", err)
 	}
 
-	var tweets []*domain.Tweet
+	tweets := make([]*domain.Tweet, 0, len(tweetsJSON))
 	for _, tweetJSON := range tweetsJSON {
 		var tweet domain.Tweet
 		if err := json.Unmarshal([]byte(tweetJSON), &tweet); err != nil {
@@ -76,6 +436,5 @@ func (r *RedisRepository) GetTimeline(ctx context.Context, userID string) ([]*do
 		tweets = append(tweets, &tweet)
 	}
 
-	log.Printf("Retrieved %d tweets for user %s", len(tweets), userID)
 	return tweets, nil
 }
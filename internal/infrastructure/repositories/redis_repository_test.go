@@ -2,6 +2,8 @@ package repositories
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"log"
 	"testing"
 	"time"
@@ -68,10 +70,40 @@ func TestRedisRepository_AddToTimeline(t *testing.T) {
 	assert.NoError(t, err)
 
 	// Verify that the tweet was added correctly
-	tweets, err := repo.GetTimeline(ctx, "user1")
+	page, err := repo.GetTimeline(ctx, "user1", "", 100)
 	assert.NoError(t, err)
-	assert.Equal(t, 1, len(tweets))
-	assert.Equal(t, tweet.Content, tweets[0].Content)
+	assert.Equal(t, 1, len(page.Tweets))
+	assert.Equal(t, tweet.Content, page.Tweets[0].Content)
+}
+
+func TestRedisRepository_AddToTimelines(t *testing.T) {
+	client, cleanup := setupTestRedisClient()
+	defer cleanup()
+
+	repo := NewRedisRepository(client)
+	ctx := context.Background()
+
+	// Más seguidores que el tamaño de un pipeline
+	followers := make([]string, timelineBatchSize+5)
+	for i := range followers {
+		followers[i] = fmt.Sprintf("follower%d", i)
+	}
+
+	tweet := &domain.Tweet{ID: "1", UserID: "author", Content: "Hello, followers!", CreatedAt: time.Now()}
+
+	err := repo.AddToTimelines(ctx, followers, tweet)
+	assert.NoError(t, err)
+
+	for _, followerID := range []string{followers[0], followers[len(followers)-1]} {
+		page, err := repo.GetTimeline(ctx, followerID, "", 100)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(page.Tweets))
+		assert.Equal(t, tweet.Content, page.Tweets[0].Content)
+
+		ttl, err := client.TTL(ctx, "timeline:"+followerID).Result()
+		assert.NoError(t, err)
+		assert.Greater(t, ttl, time.Duration(0))
+	}
 }
 
 func TestRedisRepository_GetTimeline(t *testing.T) {
@@ -93,9 +125,275 @@ func TestRedisRepository_GetTimeline(t *testing.T) {
 	}
 
 	// Retrieve the timeline and verify the tweets
-	retrievedTweets, err := repo.GetTimeline(ctx, "user2")
+	page, err := repo.GetTimeline(ctx, "user2", "", 100)
+	assert.NoError(t, err)
+	assert.Equal(t, len(tweets), len(page.Tweets))
+	assert.Equal(t, tweets[0].Content, page.Tweets[1].Content)
+	assert.Equal(t, tweets[1].Content, page.Tweets[0].Content)
+	assert.Empty(t, page.NextCursor)
+}
+
+func TestRedisRepository_GetTimeline_Pagination(t *testing.T) {
+	client, cleanup := setupTestRedisClient()
+	defer cleanup()
+
+	repo := NewRedisRepository(client)
+	ctx := context.Background()
+
+	// Dos tweets comparten el mismo segundo para verificar el desempate del cursor
+	now := time.Now()
+	tweets := []*domain.Tweet{
+		{ID: "1", Content: "Tweet 1", CreatedAt: now.Add(-2 * time.Hour)},
+		{ID: "2", Content: "Tweet 2", CreatedAt: now.Add(-1 * time.Hour)},
+		{ID: "3", Content: "Tweet 3", CreatedAt: now},
+		{ID: "4", Content: "Tweet 4", CreatedAt: now},
+	}
+
+	for _, tweet := range tweets {
+		err := repo.AddToTimeline(ctx, "user4", tweet)
+		assert.NoError(t, err)
+	}
+
+	var seen []string
+	cursor := ""
+	for {
+		page, err := repo.GetTimeline(ctx, "user4", cursor, 1)
+		assert.NoError(t, err)
+		for _, tweet := range page.Tweets {
+			seen = append(seen, tweet.ID)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	// Los empates en el mismo segundo se ordenan por ID descendente
+	assert.Equal(t, []string{"4", "3", "2", "1"}, seen)
+
+	_, err := repo.GetTimeline(ctx, "user4", "not-a-cursor", 1)
+	assert.ErrorIs(t, err, domain.ErrInvalidCursor)
+}
+
+func TestRedisRepository_RemoveAuthorFromTimeline(t *testing.T) {
+	client, cleanup := setupTestRedisClient()
+	defer cleanup()
+
+	repo := NewRedisRepository(client)
+	ctx := context.Background()
+
+	tweets := []*domain.Tweet{
+		{ID: "1", UserID: "author1", Content: "Tweet 1", CreatedAt: time.Now().Add(-1 * time.Hour)},
+		{ID: "2", UserID: "author2", Content: "Tweet 2", CreatedAt: time.Now()},
+	}
+
+	for _, tweet := range tweets {
+		err := repo.AddToTimeline(ctx, "user3", tweet)
+		assert.NoError(t, err)
+	}
+
+	err := repo.RemoveAuthorFromTimeline(ctx, "user3", "author1")
+	assert.NoError(t, err)
+
+	page, err := repo.GetTimeline(ctx, "user3", "", 100)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(page.Tweets))
+	assert.Equal(t, "author2", page.Tweets[0].UserID)
+}
+
+func TestRedisRepository_RemoveFromTimelines(t *testing.T) {
+	client, cleanup := setupTestRedisClient()
+	defer cleanup()
+
+	repo := NewRedisRepository(client)
+	ctx := context.Background()
+
+	deleted := &domain.Tweet{ID: "1", UserID: "author1", Content: "Tweet 1", CreatedAt: time.Now().Add(-1 * time.Hour)}
+	kept := &domain.Tweet{ID: "2", UserID: "author1", Content: "Tweet 2", CreatedAt: time.Now()}
+	assert.NoError(t, repo.AddToTimelines(ctx, []string{"user1", "user2"}, deleted))
+	assert.NoError(t, repo.AddToTimelines(ctx, []string{"user1", "user2"}, kept))
+
+	// El tweet se busca por ID aunque el JSON guardado no sea igual al del evento
+	err := repo.RemoveFromTimelines(ctx, []string{"user1", "user2", "user3"}, deleted.ID)
+	assert.NoError(t, err)
+
+	for _, userID := range []string{"user1", "user2"} {
+		page, err := repo.GetTimeline(ctx, userID, "", 100)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(page.Tweets))
+		assert.Equal(t, kept.ID, page.Tweets[0].ID)
+	}
+}
+
+func TestRedisRepository_ReplaceInTimelines(t *testing.T) {
+	client, cleanup := setupTestRedisClient()
+	defer cleanup()
+
+	repo := NewRedisRepository(client)
+	ctx := context.Background()
+
+	tweet := &domain.Tweet{ID: "1", UserID: "author1", Content: "Helo", CreatedAt: time.Now()}
+	assert.NoError(t, repo.AddToTimelines(ctx, []string{"user1"}, tweet))
+
+	edited := *tweet
+	assert.NoError(t, edited.Edit("Hello", time.Now()))
+
+	// user2 no tenía el tweet, así que no se le agrega
+	err := repo.ReplaceInTimelines(ctx, []string{"user1", "user2"}, &edited)
+	assert.NoError(t, err)
+
+	page, err := repo.GetTimeline(ctx, "user1", "", 100)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(page.Tweets))
+	assert.Equal(t, "Hello", page.Tweets[0].Content)
+	assert.Len(t, page.Tweets[0].Revisions, 1)
+
+	page, err = repo.GetTimeline(ctx, "user2", "", 100)
+	assert.NoError(t, err)
+	assert.Empty(t, page.Tweets)
+}
+
+func TestRedisRepository_AddRetweetToTimelines(t *testing.T) {
+	client, cleanup := setupTestRedisClient()
+	defer cleanup()
+
+	repo := NewRedisRepository(client)
+	ctx := context.Background()
+
+	original := domain.NewTweet("author1", "Hello")
+	firstRetweet := domain.NewRetweet("friend1", original)
+	secondRetweet := domain.NewRetweet("friend2", original)
+
+	// user1 ya tiene el original y user2 un retweet del original, solo user3 recibe el retweet
+	assert.NoError(t, repo.AddToTimeline(ctx, "user1", original))
+	assert.NoError(t, repo.AddToTimeline(ctx, "user2", firstRetweet))
+
+	err := repo.AddRetweetToTimelines(ctx, []string{"user1", "user2", "user3"}, secondRetweet)
+	assert.NoError(t, err)
+
+	expected := map[string]string{"user1": original.ID, "user2": firstRetweet.ID, "user3": secondRetweet.ID}
+	for userID, tweetID := range expected {
+		page, err := repo.GetTimeline(ctx, userID, "", 100)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(page.Tweets), userID)
+		assert.Equal(t, tweetID, page.Tweets[0].ID, userID)
+	}
+}
+
+func TestRedisRepository_CelebrityTweets(t *testing.T) {
+	client, cleanup := setupTestRedisClient()
+	defer cleanup()
+
+	repo := NewRedisRepository(client)
+	ctx := context.Background()
+
+	tweets := []*domain.Tweet{
+		{ID: "1", UserID: "celebrity", Content: "Tweet 1", CreatedAt: time.Now().Add(-1 * time.Hour)},
+		{ID: "2", UserID: "celebrity", Content: "Tweet 2", CreatedAt: time.Now()},
+	}
+
+	for _, tweet := range tweets {
+		err := repo.AddToCelebrityTweets(ctx, tweet)
+		assert.NoError(t, err)
+	}
+
+	celebrities, err := repo.GetCelebrities(ctx, []string{"friend", "celebrity"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"celebrity"}, celebrities)
+
+	page, err := repo.GetCelebrityTweets(ctx, "celebrity", "", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(page.Tweets))
+	assert.Equal(t, "2", page.Tweets[0].ID)
+	assert.NotEmpty(t, page.NextCursor)
+
+	page, err = repo.GetCelebrityTweets(ctx, "celebrity", page.NextCursor, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(page.Tweets))
+	assert.Equal(t, "1", page.Tweets[0].ID)
+	assert.Empty(t, page.NextCursor)
+
+	err = repo.RemoveFromCelebrityTweets(ctx, "celebrity", "2")
+	assert.NoError(t, err)
+
+	page, err = repo.GetCelebrityTweets(ctx, "celebrity", "", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(page.Tweets))
+	assert.Equal(t, "1", page.Tweets[0].ID)
+
+	// Sin tweets recientes deja de contar como celebridad
+	assert.NoError(t, repo.RemoveFromCelebrityTweets(ctx, "celebrity", "1"))
+	celebrities, err = repo.GetCelebrities(ctx, []string{"celebrity"})
+	assert.NoError(t, err)
+	assert.Empty(t, celebrities)
+}
+
+func TestRedisRepository_TimelineIndex(t *testing.T) {
+	client, cleanup := setupTestRedisClient()
+	defer cleanup()
+
+	repo := NewRedisRepository(client)
+	ctx := context.Background()
+
+	now := time.Now()
+	tweet := &domain.Tweet{ID: "1", UserID: "author1", Content: "Hello", CreatedAt: now}
+	assert.NoError(t, repo.AddToTimeline(ctx, "user1", tweet))
+
+	// Un evento repetido reemplaza el tweet en vez de duplicarlo
+	redelivered := *tweet
+	redelivered.CreatedAt = now.UTC()
+	assert.NoError(t, repo.AddToTimeline(ctx, "user1", &redelivered))
+
+	page, err := repo.GetTimeline(ctx, "user1", "", 100)
+	assert.NoError(t, err)
+	assert.Len(t, page.Tweets, 1)
+
+	// Los tweets que salen por el recorte también salen del índice
+	for i := 0; i < maxTimelineLength; i++ {
+		older := &domain.Tweet{ID: fmt.Sprintf("old%d", i), UserID: "author2", CreatedAt: now.Add(time.Duration(i-1000) * time.Second)}
+		assert.NoError(t, repo.AddToTimeline(ctx, "user1", older))
+	}
+	assert.NoError(t, repo.AddToTimeline(ctx, "user1", &domain.Tweet{ID: "2", UserID: "author1", CreatedAt: now}))
+
+	size, err := client.HLen(ctx, tweetIndexKey("timeline:user1")).Result()
+	assert.NoError(t, err)
+	count, err := client.ZCard(ctx, "timeline:user1").Result()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(maxTimelineLength), count)
+	// Un campo por tweet y otro con la cantidad de miembros que muestran cada tweet
+	assert.Equal(t, 2*count, size)
+
+	ttl, err := client.TTL(ctx, tweetIndexKey("timeline:user1")).Result()
+	assert.NoError(t, err)
+	assert.Greater(t, ttl, time.Duration(0))
+}
+
+func TestRedisRepository_TimelineIndexBuiltForExistingTimelines(t *testing.T) {
+	client, cleanup := setupTestRedisClient()
+	defer cleanup()
+
+	repo := NewRedisRepository(client)
+	ctx := context.Background()
+
+	// Un timeline guardado antes de que existiera el índice
+	tweet := &domain.Tweet{ID: "1", UserID: "author1", Content: "Helo", CreatedAt: time.Now()}
+	tweetJSON, err := json.Marshal(tweet)
+	assert.NoError(t, err)
+	assert.NoError(t, client.ZAdd(ctx, "timeline:user1", &redis.Z{Score: float64(tweet.CreatedAt.Unix()), Member: tweetJSON}).Err())
+
+	edited := *tweet
+	edited.Content = "Hello"
+	assert.NoError(t, repo.ReplaceInTimelines(ctx, []string{"user1"}, &edited))
+
+	page, err := repo.GetTimeline(ctx, "user1", "", 100)
+	assert.NoError(t, err)
+	if assert.Len(t, page.Tweets, 1) {
+		assert.Equal(t, "Hello", page.Tweets[0].Content)
+	}
+
+	// El retweet de un tweet que ya está en el timeline no se agrega
+	assert.NoError(t, repo.AddRetweetToTimelines(ctx, []string{"user1"}, domain.NewRetweet("friend1", &edited)))
+	page, err = repo.GetTimeline(ctx, "user1", "", 100)
 	assert.NoError(t, err)
-	assert.Equal(t, len(tweets), len(retrievedTweets))
-	assert.Equal(t, tweets[0].Content, retrievedTweets[1].Content)
-	assert.Equal(t, tweets[1].Content, retrievedTweets[0].Content)
+	assert.Len(t, page.Tweets, 1)
 }
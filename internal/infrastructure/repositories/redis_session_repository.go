@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// RedisSessionRepository guarda cada sesión abierta en session:<userID>:<sessionID>, que vence con
+// ella, y los IDs de las sesiones de cada usuario en sessions:<userID> para poder cerrarlas todas.
+// El set vence con la última sesión que se abrió o se extendió, así que no crece para siempre.
+type RedisSessionRepository struct {
+	client *redis.Client
+}
+
+func NewRedisSessionRepository(client *redis.Client) *RedisSessionRepository {
+	return &RedisSessionRepository{
+		client: client,
+	}
+}
+
+// Open guarda la sesión y la agrega a las del usuario en una transacción.
+func (r *RedisSessionRepository) Open(ctx context.Context, userID, sessionID string, ttl time.Duration) error {
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, sessionKey(userID, sessionID), userID, ttl)
+		pipe.SAdd(ctx, userSessionsKey(userID), sessionID)
+		pipe.Expire(ctx, userSessionsKey(userID), ttl)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error opening session: %w", err)
+	}
+
+	return nil
+}
+
+// Extend renueva el vencimiento con SET XX, que no vuelve a abrir una sesión cerrada.
+func (r *RedisSessionRepository) Extend(ctx context.Context, userID, sessionID string, ttl time.Duration) (bool, error) {
+	extended, err := r.client.SetXX(ctx, sessionKey(userID, sessionID), userID, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("error extending session: %w", err)
+	}
+	if !extended {
+		return false, nil
+	}
+
+	if err := r.client.Expire(ctx, userSessionsKey(userID), ttl).Err(); err != nil {
+		return false, fmt.Errorf("error extending session: %w", err)
+	}
+
+	return true, nil
+}
+
+// IsOpen indica si la sesión existe: las que vencieron ya las borró Redis.
+func (r *RedisSessionRepository) IsOpen(ctx context.Context, userID, sessionID string) (bool, error) {
+	exists, err := r.client.Exists(ctx, sessionKey(userID, sessionID)).Result()
+	if err != nil {
+		return false, fmt.Errorf("error checking session: %w", err)
+	}
+
+	return exists == 1, nil
+}
+
+// Close borra la sesión.
+func (r *RedisSessionRepository) Close(ctx context.Context, userID, sessionID string) error {
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, sessionKey(userID, sessionID))
+		pipe.SRem(ctx, userSessionsKey(userID), sessionID)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error closing session: %w", err)
+	}
+
+	return nil
+}
+
+// CloseAll borra todas las sesiones del usuario junto con su set.
+func (r *RedisSessionRepository) CloseAll(ctx context.Context, userID string) error {
+	sessionIDs, err := r.client.SMembers(ctx, userSessionsKey(userID)).Result()
+	if err != nil {
+		return fmt.Errorf("error getting sessions: %w", err)
+	}
+
+	keys := []string{userSessionsKey(userID)}
+	for _, sessionID := range sessionIDs {
+		keys = append(keys, sessionKey(userID, sessionID))
+	}
+
+	if err := r.client.Del(ctx, keys...).Err(); err != nil {
+		return fmt.Errorf("error closing sessions: %w", err)
+	}
+
+	return nil
+}
+
+func sessionKey(userID, sessionID string) string {
+	return "session:" + userID + ":" + sessionID
+}
+
+func userSessionsKey(userID string) string {
+	return "sessions:" + userID
+}
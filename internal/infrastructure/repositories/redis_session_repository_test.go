@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisSessionRepository_OpenAndClose(t *testing.T) {
+	client, cleanup := setupTestRedisClient()
+	defer cleanup()
+
+	repo := NewRedisSessionRepository(client)
+	ctx := context.Background()
+
+	assert.NoError(t, repo.Open(ctx, "user1", "session1", time.Hour))
+	assert.NoError(t, repo.Open(ctx, "user1", "session2", time.Hour))
+
+	open, err := repo.IsOpen(ctx, "user1", "session1")
+	assert.NoError(t, err)
+	assert.True(t, open)
+
+	// Una sesión es del usuario que la abrió
+	open, err = repo.IsOpen(ctx, "user2", "session1")
+	assert.NoError(t, err)
+	assert.False(t, open)
+
+	assert.NoError(t, repo.Close(ctx, "user1", "session1"))
+	open, err = repo.IsOpen(ctx, "user1", "session1")
+	assert.NoError(t, err)
+	assert.False(t, open)
+
+	open, err = repo.IsOpen(ctx, "user1", "session2")
+	assert.NoError(t, err)
+	assert.True(t, open)
+}
+
+func TestRedisSessionRepository_Extend(t *testing.T) {
+	client, cleanup := setupTestRedisClient()
+	defer cleanup()
+
+	repo := NewRedisSessionRepository(client)
+	ctx := context.Background()
+
+	assert.NoError(t, repo.Open(ctx, "user1", "session1", time.Minute))
+
+	extended, err := repo.Extend(ctx, "user1", "session1", time.Hour)
+	assert.NoError(t, err)
+	assert.True(t, extended)
+
+	ttl, err := client.TTL(ctx, "session:user1:session1").Result()
+	assert.NoError(t, err)
+	assert.Greater(t, ttl, time.Minute)
+
+	// Una sesión cerrada no se puede extender
+	assert.NoError(t, repo.Close(ctx, "user1", "session1"))
+	extended, err = repo.Extend(ctx, "user1", "session1", time.Hour)
+	assert.NoError(t, err)
+	assert.False(t, extended)
+
+	open, err := repo.IsOpen(ctx, "user1", "session1")
+	assert.NoError(t, err)
+	assert.False(t, open)
+}
+
+func TestRedisSessionRepository_CloseAll(t *testing.T) {
+	client, cleanup := setupTestRedisClient()
+	defer cleanup()
+
+	repo := NewRedisSessionRepository(client)
+	ctx := context.Background()
+
+	assert.NoError(t, repo.Open(ctx, "user1", "session1", time.Hour))
+	assert.NoError(t, repo.Open(ctx, "user1", "session2", time.Hour))
+	assert.NoError(t, repo.Open(ctx, "user2", "session3", time.Hour))
+
+	assert.NoError(t, repo.CloseAll(ctx, "user1"))
+
+	for _, sessionID := range []string{"session1", "session2"} {
+		open, err := repo.IsOpen(ctx, "user1", sessionID)
+		assert.NoError(t, err)
+		assert.False(t, open)
+	}
+
+	// Las sesiones de otros usuarios siguen abiertas
+	open, err := repo.IsOpen(ctx, "user2", "session3")
+	assert.NoError(t, err)
+	assert.True(t, open)
+}
@@ -0,0 +1,146 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"ChallengeUALA/internal/domain"
+	"github.com/go-redis/redis/v8"
+)
+
+// incrementHashtagsScript suma un uso de cada tag en el bucket KEYS[1] solo si el tweet ARGV[2] no lo
+// había sumado antes, anotando <tweet>:<tag> en KEYS[2]. Los dos vencen a los ARGV[1] segundos.
+var incrementHashtagsScript = redis.NewScript(`
+for i = 3, #ARGV do
+	if redis.call('SADD', KEYS[2], ARGV[2] .. ':' .. ARGV[i]) == 1 then
+		redis.call('ZINCRBY', KEYS[1], 1, ARGV[i])
+	end
+end
+redis.call('EXPIRE', KEYS[1], ARGV[1])
+redis.call('EXPIRE', KEYS[2], ARGV[1])
+return 0
+`)
+
+// RedisTrendRepository guarda los contadores de hashtags en sorted sets trends:<inicio del bucket>
+// (hashtag -> usos en ese bucket), con los usos ya contados en trends:counted:<inicio del bucket>,
+// y los tweets de cada hashtag en hashtag_tweets:<tag>, con el mismo formato, índice y límites que
+// un timeline para reusar la paginación.
+type RedisTrendRepository struct {
+	client *redis.Client
+	tweets *RedisRepository
+}
+
+func NewRedisTrendRepository(client *redis.Client) *RedisTrendRepository {
+	return &RedisTrendRepository{
+		client: client,
+		tweets: NewRedisRepository(client),
+	}
+}
+
+// IncrementHashtags suma un uso a cada tag en el bucket que empieza en bucket. El script corre de
+// forma atómica, así que dos entregas simultáneas del mismo tweet tampoco cuentan dos veces.
+func (r *RedisTrendRepository) IncrementHashtags(ctx context.Context, tweetID string, tags []string, bucket time.Time, ttl time.Duration) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	args := make([]interface{}, 0, len(tags)+2)
+	args = append(args, int64(ttl.Seconds()), tweetID)
+	for _, tag := range tags {
+		args = append(args, tag)
+	}
+
+	keys := []string{trendBucketKey(bucket), trendCountedKey(bucket)}
+	if err := incrementHashtagsScript.Run(ctx, r.client, keys, args...).Err(); err != nil {
+		return fmt.Errorf("error incrementing hashtags: %w", err)
+	}
+
+	return nil
+}
+
+// TopHashtags suma los buckets en un set temporal y devuelve los limit hashtags más usados.
+// Los empates quedan en el orden de Redis (por hashtag, de forma descendente).
+func (r *RedisTrendRepository) TopHashtags(ctx context.Context, buckets []time.Time, limit int) ([]domain.Trend, error) {
+	trends := make([]domain.Trend, 0)
+	if len(buckets) == 0 || limit <= 0 {
+		return trends, nil
+	}
+
+	keys := make([]string, 0, len(buckets))
+	for _, bucket := range buckets {
+		keys = append(keys, trendBucketKey(bucket))
+	}
+
+	// Todo corre en una transacción, así que dos pedidos de la misma ventana no se pisan el set temporal
+	dest := "trends:window:" + keys[len(keys)-1] + ":" + strconv.Itoa(len(keys))
+	var top *redis.ZSliceCmd
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZUnionStore(ctx, dest, &redis.ZStore{Keys: keys})
+		top = pipe.ZRevRangeWithScores(ctx, dest, 0, int64(limit)-1)
+		pipe.Del(ctx, dest)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting top hashtags: %w", err)
+	}
+
+	for _, z := range top.Val() {
+		trends = append(trends, domain.Trend{Hashtag: z.Member.(string), Count: int(z.Score)})
+	}
+
+	return trends, nil
+}
+
+// AddToHashtags agrega el tweet a la lista de tweets recientes de cada tag.
+func (r *RedisTrendRepository) AddToHashtags(ctx context.Context, tags []string, tweet *domain.Tweet) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	if err := r.tweets.addTweet(ctx, hashtagKeys(tags), tweet, timelineTTL, false); err != nil {
+		return fmt.Errorf("error adding tweet to hashtags: %w", err)
+	}
+
+	return nil
+}
+
+// GetHashtagTweets devuelve una página de los tweets recientes de un hashtag, del más nuevo al más viejo.
+func (r *RedisTrendRepository) GetHashtagTweets(ctx context.Context, tag, cursor string, limit int) (*domain.TimelinePage, error) {
+	return r.tweets.getPage(ctx, "hashtag_tweets:"+tag, cursor, limit)
+}
+
+// RemoveFromHashtags quita un tweet de los tweets recientes de cada tag.
+func (r *RedisTrendRepository) RemoveFromHashtags(ctx context.Context, tags []string, tweetID string) error {
+	if err := r.tweets.rewriteTweet(ctx, hashtagKeys(tags), tweetID, nil); err != nil {
+		return fmt.Errorf("error removing tweet from hashtags: %w", err)
+	}
+
+	return nil
+}
+
+// ReplaceInHashtags reemplaza la versión guardada de un tweet editado en los tags que lo tienen.
+func (r *RedisTrendRepository) ReplaceInHashtags(ctx context.Context, tags []string, tweet *domain.Tweet) error {
+	if err := r.tweets.rewriteTweet(ctx, hashtagKeys(tags), tweet.ID, tweet); err != nil {
+		return fmt.Errorf("error replacing tweet in hashtags: %w", err)
+	}
+
+	return nil
+}
+
+func trendBucketKey(bucket time.Time) string {
+	return "trends:" + strconv.FormatInt(bucket.Unix(), 10)
+}
+
+func trendCountedKey(bucket time.Time) string {
+	return "trends:counted:" + strconv.FormatInt(bucket.Unix(), 10)
+}
+
+func hashtagKeys(tags []string) []string {
+	keys := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		keys = append(keys, "hashtag_tweets:"+tag)
+	}
+	return keys
+}
@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ChallengeUALA/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisTrendRepository_TopHashtags(t *testing.T) {
+	client, cleanup := setupTestRedisClient()
+	defer cleanup()
+
+	repo := NewRedisTrendRepository(client)
+	ctx := context.Background()
+
+	first := time.Unix(1700000000, 0).Truncate(5 * time.Minute)
+	second := first.Add(5 * time.Minute)
+	old := first.Add(-5 * time.Minute)
+
+	assert.NoError(t, repo.IncrementHashtags(ctx, "1", []string{"go", "rust"}, first, time.Hour))
+	assert.NoError(t, repo.IncrementHashtags(ctx, "2", []string{"go"}, second, time.Hour))
+	for _, id := range []string{"3", "4", "5"} {
+		assert.NoError(t, repo.IncrementHashtags(ctx, id, []string{"java"}, old, time.Hour))
+	}
+
+	// Solo se suman los buckets de la ventana pedida
+	trends, err := repo.TopHashtags(ctx, []time.Time{first, second}, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Trend{{Hashtag: "go", Count: 2}, {Hashtag: "rust", Count: 1}}, trends)
+
+	trends, err = repo.TopHashtags(ctx, []time.Time{old, first, second}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Trend{{Hashtag: "java", Count: 3}}, trends)
+
+	// El set temporal de la ventana no queda guardado
+	keys, err := client.Keys(ctx, "trends:window:*").Result()
+	assert.NoError(t, err)
+	assert.Empty(t, keys)
+
+	ttl, err := client.TTL(ctx, trendBucketKey(first)).Result()
+	assert.NoError(t, err)
+	assert.True(t, ttl > 0 && ttl <= time.Hour)
+
+	trends, err = repo.TopHashtags(ctx, []time.Time{second.Add(time.Hour)}, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, trends)
+}
+
+func TestRedisTrendRepository_IncrementHashtagsOncePerTweet(t *testing.T) {
+	client, cleanup := setupTestRedisClient()
+	defer cleanup()
+
+	repo := NewRedisTrendRepository(client)
+	ctx := context.Background()
+
+	bucket := time.Unix(1700000000, 0).Truncate(5 * time.Minute)
+	assert.NoError(t, repo.IncrementHashtags(ctx, "1", []string{"go"}, bucket, time.Hour))
+
+	// Una entrega repetida del mismo tweet no vuelve a sumar, pero un tag nuevo de una edición sí
+	assert.NoError(t, repo.IncrementHashtags(ctx, "1", []string{"go"}, bucket, time.Hour))
+	assert.NoError(t, repo.IncrementHashtags(ctx, "1", []string{"go", "rust"}, bucket, time.Hour))
+	assert.NoError(t, repo.IncrementHashtags(ctx, "2", []string{"go"}, bucket, time.Hour))
+
+	trends, err := repo.TopHashtags(ctx, []time.Time{bucket}, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Trend{{Hashtag: "go", Count: 2}, {Hashtag: "rust", Count: 1}}, trends)
+
+	ttl, err := client.TTL(ctx, trendCountedKey(bucket)).Result()
+	assert.NoError(t, err)
+	assert.True(t, ttl > 0 && ttl <= time.Hour)
+}
+
+func TestRedisTrendRepository_HashtagTweets(t *testing.T) {
+	client, cleanup := setupTestRedisClient()
+	defer cleanup()
+
+	repo := NewRedisTrendRepository(client)
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	older := &domain.Tweet{ID: "1", UserID: "user1", Content: "#go #rust", CreatedAt: now.Add(-time.Minute)}
+	newer := &domain.Tweet{ID: "2", UserID: "user2", Content: "#go", CreatedAt: now}
+	assert.NoError(t, repo.AddToHashtags(ctx, []string{"go", "rust"}, older))
+	assert.NoError(t, repo.AddToHashtags(ctx, []string{"go"}, newer))
+
+	page, err := repo.GetHashtagTweets(ctx, "go", "", 1)
+	assert.NoError(t, err)
+	assert.Len(t, page.Tweets, 1)
+	assert.Equal(t, "2", page.Tweets[0].ID)
+
+	page, err = repo.GetHashtagTweets(ctx, "go", page.NextCursor, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", page.Tweets[0].ID)
+	assert.Empty(t, page.NextCursor)
+
+	edited := *older
+	edited.Content = "#go editado"
+	assert.NoError(t, repo.ReplaceInHashtags(ctx, []string{"go"}, &edited))
+	assert.NoError(t, repo.RemoveFromHashtags(ctx, []string{"rust"}, older.ID))
+
+	page, err = repo.GetHashtagTweets(ctx, "go", "", 10)
+	assert.NoError(t, err)
+	assert.Len(t, page.Tweets, 2)
+	assert.Equal(t, "#go editado", page.Tweets[1].Content)
+
+	page, err = repo.GetHashtagTweets(ctx, "rust", "", 10)
+	assert.NoError(t, err)
+	assert.Empty(t, page.Tweets)
+}
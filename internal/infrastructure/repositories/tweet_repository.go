@@ -8,15 +8,26 @@ import (
 )
 
 // TweetRepository es una struct que implementa la interfaz TweetRepository
+// y la interfaz OutboxRepository para los eventos de tweets. Mantiene índices
+// de los IDs de los tweets de cada autor (byAuthor), de cada conversación (byConversation)
+// y de los retweets de cada tweet (retweets).
 type TweetRepository struct {
-	mu     sync.RWMutex
-	tweets map[string]*domain.Tweet
+	mu             sync.RWMutex
+	tweets         map[string]*domain.Tweet
+	byAuthor       map[string]map[string]bool
+	byConversation map[string]map[string]bool
+	retweets       map[string]map[string]bool
+	outbox         []*domain.OutboxEvent
 }
 
 // NewTweetRepository crea una nueva instancia de TweetRepository
 func NewTweetRepository() *TweetRepository {
 	return &TweetRepository{
-		tweets: make(map[string]*domain.Tweet),
+		tweets:         make(map[string]*domain.Tweet),
+		byAuthor:       make(map[string]map[string]bool),
+		byConversation: make(map[string]map[string]bool),
+		retweets:       make(map[string]map[string]bool),
+		outbox:         make([]*domain.OutboxEvent, 0),
 	}
 }
 
@@ -24,6 +35,172 @@ func NewTweetRepository() *TweetRepository {
 func (r *TweetRepository) Save(ctx context.Context, tweet *domain.Tweet) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	r.tweets[tweet.ID] = tweet
+	r.put(tweet)
+	return nil
+}
+
+// SaveWithEvent guarda un tweet y su evento bajo el mismo lock
+func (r *TweetRepository) SaveWithEvent(ctx context.Context, tweet *domain.Tweet, event *domain.OutboxEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.put(tweet)
+	r.outbox = append(r.outbox, event)
+	return nil
+}
+
+// GetByID devuelve una copia del tweet, así los cambios del llamador no se ven hasta guardarlos
+func (r *TweetRepository) GetByID(ctx context.Context, id string) (*domain.Tweet, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tweet, ok := r.tweets[id]
+	if !ok {
+		return nil, domain.ErrTweetNotFound
+	}
+	return cloneTweet(tweet), nil
+}
+
+// ListByAuthor devuelve una página de los tweets de un autor, del más nuevo al más viejo
+func (r *TweetRepository) ListByAuthor(ctx context.Context, authorID, cursor string, limit int) (*domain.TimelinePage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tweets := make([]*domain.Tweet, 0, len(r.byAuthor[authorID]))
+	for id := range r.byAuthor[authorID] {
+		tweets = append(tweets, cloneTweet(r.tweets[id]))
+	}
+
+	return domain.PageTimeline(tweets, cursor, limit)
+}
+
+// ListByConversation devuelve los tweets de una conversación
+func (r *TweetRepository) ListByConversation(ctx context.Context, conversationID string) ([]*domain.Tweet, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tweets := make([]*domain.Tweet, 0, len(r.byConversation[conversationID]))
+	for id := range r.byConversation[conversationID] {
+		tweets = append(tweets, cloneTweet(r.tweets[id]))
+	}
+
+	return tweets, nil
+}
+
+// ListRetweets devuelve los retweets de un tweet
+func (r *TweetRepository) ListRetweets(ctx context.Context, tweetID string) ([]*domain.Tweet, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tweets := make([]*domain.Tweet, 0, len(r.retweets[tweetID]))
+	for id := range r.retweets[tweetID] {
+		tweets = append(tweets, cloneTweet(r.tweets[id]))
+	}
+
+	return tweets, nil
+}
+
+// UpdateWithEvent reemplaza un tweet existente si nadie lo actualizó desde que se leyó y guarda su
+// evento bajo el mismo lock
+func (r *TweetRepository) UpdateWithEvent(ctx context.Context, tweet *domain.Tweet, event *domain.OutboxEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.tweets[tweet.ID]
+	if !ok {
+		return domain.ErrTweetNotFound
+	}
+	if stored.Version != tweet.Version {
+		return domain.ErrTweetConflict
+	}
+
+	tweet.Version++
+	r.put(tweet)
+	r.outbox = append(r.outbox, event)
+	return nil
+}
+
+// Delete borra un tweet de memoria
+func (r *TweetRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.tweets[id]; !ok {
+		return domain.ErrTweetNotFound
+	}
+	r.remove(id)
+	return nil
+}
+
+// DeleteWithEvent borra un tweet y guarda su evento bajo el mismo lock
+func (r *TweetRepository) DeleteWithEvent(ctx context.Context, id string, event *domain.OutboxEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.tweets[id]; !ok {
+		return domain.ErrTweetNotFound
+	}
+	r.remove(id)
+	r.outbox = append(r.outbox, event)
 	return nil
 }
+
+// FetchPending devuelve hasta limit eventos pendientes, del más viejo al más nuevo
+func (r *TweetRepository) FetchPending(ctx context.Context, limit int) ([]*domain.OutboxEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if limit > len(r.outbox) {
+		limit = len(r.outbox)
+	}
+
+	events := make([]*domain.OutboxEvent, limit)
+	copy(events, r.outbox[:limit])
+	return events, nil
+}
+
+// MarkPublished saca un evento del outbox una vez publicado
+func (r *TweetRepository) MarkPublished(ctx context.Context, eventID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, event := range r.outbox {
+		if event.ID == eventID {
+			r.outbox = append(r.outbox[:i], r.outbox[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func cloneTweet(tweet *domain.Tweet) *domain.Tweet {
+	clone := *tweet
+	if tweet.EditedAt != nil {
+		editedAt := *tweet.EditedAt
+		clone.EditedAt = &editedAt
+	}
+	clone.Revisions = append([]domain.TweetRevision(nil), tweet.Revisions...)
+	return &clone
+}
+
+// put guarda el tweet y actualiza los índices. Se llama con el lock tomado.
+func (r *TweetRepository) put(tweet *domain.Tweet) {
+	r.remove(tweet.ID)
+	r.tweets[tweet.ID] = tweet
+	addToIndex(r.byAuthor, tweet.UserID, tweet.ID)
+	addToIndex(r.byConversation, tweet.Conversation(), tweet.ID)
+	if tweet.IsRetweet() {
+		addToIndex(r.retweets, tweet.ReferencedTweetID, tweet.ID)
+	}
+}
+
+// remove borra el tweet y lo saca de los índices. Se llama con el lock tomado.
+func (r *TweetRepository) remove(id string) {
+	if tweet, ok := r.tweets[id]; ok {
+		removeFromIndex(r.byAuthor, tweet.UserID, id)
+		removeFromIndex(r.byConversation, tweet.Conversation(), id)
+		if tweet.IsRetweet() {
+			removeFromIndex(r.retweets, tweet.ReferencedTweetID, id)
+		}
+		delete(r.tweets, id)
+	}
+}
@@ -3,6 +3,7 @@ package repositories
 import (
 	"context"
 	"testing"
+	"time"
 
 	"ChallengeUALA/internal/domain"
 	"github.com/stretchr/testify/assert"
@@ -27,3 +28,201 @@ func TestTweetRepository_Save(t *testing.T) {
 	assert.True(t, exists)
 	assert.Equal(t, tweet, savedTweet)
 }
+
+func TestTweetRepository_SaveWithEvent(t *testing.T) {
+	repo := NewTweetRepository()
+	ctx := context.Background()
+	tweet := &domain.Tweet{ID: "1", Content: "Hello, world!"}
+	event := domain.NewOutboxEvent(domain.EventTweetCreated, "user1", []byte(`{"ID":"1"}`))
+
+	err := repo.SaveWithEvent(ctx, tweet, event)
+	assert.NoError(t, err)
+
+	repo.mu.RLock()
+	savedTweet, exists := repo.tweets[tweet.ID]
+	repo.mu.RUnlock()
+	assert.True(t, exists)
+	assert.Equal(t, tweet, savedTweet)
+
+	pending, err := repo.FetchPending(ctx, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []*domain.OutboxEvent{event}, pending)
+}
+
+func TestTweetRepository_MarkPublished(t *testing.T) {
+	repo := NewTweetRepository()
+	ctx := context.Background()
+	first := domain.NewOutboxEvent(domain.EventTweetCreated, "user1", []byte("1"))
+	second := domain.NewOutboxEvent(domain.EventTweetCreated, "user1", []byte("2"))
+
+	assert.NoError(t, repo.SaveWithEvent(ctx, &domain.Tweet{ID: "1"}, first))
+	assert.NoError(t, repo.SaveWithEvent(ctx, &domain.Tweet{ID: "2"}, second))
+
+	// Se respeta el límite y el orden de inserción
+	pending, err := repo.FetchPending(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []*domain.OutboxEvent{first}, pending)
+
+	err = repo.MarkPublished(ctx, first.ID)
+	assert.NoError(t, err)
+
+	pending, err = repo.FetchPending(ctx, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []*domain.OutboxEvent{second}, pending)
+}
+
+func TestTweetRepository_GetByIDAndDelete(t *testing.T) {
+	repo := NewTweetRepository()
+	ctx := context.Background()
+	tweet := &domain.Tweet{ID: "1", UserID: "user1", Content: "Hello, world!"}
+	assert.NoError(t, repo.Save(ctx, tweet))
+
+	found, err := repo.GetByID(ctx, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, tweet, found)
+
+	event := domain.NewOutboxEvent(domain.EventTweetDeleted, "user1", []byte(`{"type":"tweet_deleted"}`))
+	assert.NoError(t, repo.DeleteWithEvent(ctx, "1", event))
+
+	_, err = repo.GetByID(ctx, "1")
+	assert.ErrorIs(t, err, domain.ErrTweetNotFound)
+
+	pending, err := repo.FetchPending(ctx, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []*domain.OutboxEvent{event}, pending)
+
+	// Borrar un tweet que no existe no guarda el evento
+	err = repo.DeleteWithEvent(ctx, "1", domain.NewOutboxEvent(domain.EventTweetDeleted, "user1", nil))
+	assert.ErrorIs(t, err, domain.ErrTweetNotFound)
+	assert.ErrorIs(t, repo.Delete(ctx, "1"), domain.ErrTweetNotFound)
+
+	pending, err = repo.FetchPending(ctx, 10)
+	assert.NoError(t, err)
+	assert.Len(t, pending, 1)
+}
+
+func TestTweetRepository_UpdateWithEvent(t *testing.T) {
+	repo := NewTweetRepository()
+	ctx := context.Background()
+	assert.NoError(t, repo.Save(ctx, &domain.Tweet{ID: "1", UserID: "user1", Content: "Helo", CreatedAt: time.Now()}))
+
+	tweet, err := repo.GetByID(ctx, "1")
+	assert.NoError(t, err)
+	assert.NoError(t, tweet.Edit("Hello", time.Now()))
+
+	// Los cambios sobre la copia no se ven hasta guardarlos
+	stored, err := repo.GetByID(ctx, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Helo", stored.Content)
+
+	event := domain.NewOutboxEvent(domain.EventTweetEdited, "user1", []byte(`{"type":"tweet_edited"}`))
+	assert.NoError(t, repo.UpdateWithEvent(ctx, tweet, event))
+
+	stored, err = repo.GetByID(ctx, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Hello", stored.Content)
+	assert.Len(t, stored.Revisions, 1)
+
+	// Una edición hecha sobre una copia leída antes de la anterior no la pisa
+	stale, err := repo.GetByID(ctx, "1")
+	assert.NoError(t, err)
+	stale.Version--
+	assert.NoError(t, stale.Edit("Hola", time.Now()))
+	assert.ErrorIs(t, repo.UpdateWithEvent(ctx, stale, event), domain.ErrTweetConflict)
+
+	// La versión que devuelve la última actualización se puede volver a guardar
+	assert.NoError(t, tweet.Edit("Hello!", time.Now()))
+	assert.NoError(t, repo.UpdateWithEvent(ctx, tweet, event))
+	stored, err = repo.GetByID(ctx, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Hello!", stored.Content)
+	assert.Equal(t, 2, stored.Version)
+
+	err = repo.UpdateWithEvent(ctx, &domain.Tweet{ID: "2"}, event)
+	assert.ErrorIs(t, err, domain.ErrTweetNotFound)
+
+	pending, err := repo.FetchPending(ctx, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []*domain.OutboxEvent{event, event}, pending)
+}
+
+func TestTweetRepository_ListByAuthor(t *testing.T) {
+	repo := NewTweetRepository()
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+
+	// Los tweets 2, 3 y 4 son del mismo segundo, así que se ordenan por ID
+	tweets := []*domain.Tweet{
+		{ID: "1", UserID: "author1", Content: "Tweet 1", CreatedAt: now.Add(-time.Hour)},
+		{ID: "2", UserID: "author1", Content: "Tweet 2", CreatedAt: now},
+		{ID: "3", UserID: "author1", Content: "Tweet 3", CreatedAt: now.Add(100 * time.Millisecond)},
+		{ID: "4", UserID: "author1", Content: "Tweet 4", CreatedAt: now.Add(50 * time.Millisecond)},
+		{ID: "5", UserID: "author2", Content: "Tweet 5", CreatedAt: now},
+	}
+	for _, tweet := range tweets {
+		assert.NoError(t, repo.Save(ctx, tweet))
+	}
+	assert.NoError(t, repo.Delete(ctx, "3"))
+
+	var seen []string
+	cursor := ""
+	for {
+		page, err := repo.ListByAuthor(ctx, "author1", cursor, 1)
+		assert.NoError(t, err)
+		for _, tweet := range page.Tweets {
+			seen = append(seen, tweet.ID)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	assert.Equal(t, []string{"4", "2", "1"}, seen)
+
+	page, err := repo.ListByAuthor(ctx, "author3", "", 10)
+	assert.NoError(t, err)
+	assert.Empty(t, page.Tweets)
+	assert.Empty(t, page.NextCursor)
+}
+
+func TestTweetRepository_ListByConversation(t *testing.T) {
+	repo := NewTweetRepository()
+	ctx := context.Background()
+
+	root := domain.NewTweet("user1", "Root")
+	reply := domain.NewReply("user2", "Reply", root)
+	other := domain.NewTweet("user1", "Other")
+	for _, tweet := range []*domain.Tweet{root, reply, other} {
+		assert.NoError(t, repo.Save(ctx, tweet))
+	}
+
+	tweets, err := repo.ListByConversation(ctx, root.ID)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []*domain.Tweet{root, reply}, tweets)
+
+	assert.NoError(t, repo.Delete(ctx, reply.ID))
+	tweets, err = repo.ListByConversation(ctx, root.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []*domain.Tweet{root}, tweets)
+}
+
+func TestTweetRepository_ListRetweets(t *testing.T) {
+	repo := NewTweetRepository()
+	ctx := context.Background()
+
+	original := domain.NewTweet("user1", "Hello")
+	retweet := domain.NewRetweet("user2", original)
+	quote := domain.NewQuote("user3", "Mirá", original)
+	for _, tweet := range []*domain.Tweet{original, retweet, quote} {
+		assert.NoError(t, repo.Save(ctx, tweet))
+	}
+
+	retweets, err := repo.ListRetweets(ctx, original.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []*domain.Tweet{retweet}, retweets)
+
+	assert.NoError(t, repo.Delete(ctx, retweet.ID))
+	retweets, err = repo.ListRetweets(ctx, original.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, retweets)
+}
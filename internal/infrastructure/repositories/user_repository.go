@@ -7,30 +7,62 @@ import (
 	"ChallengeUALA/internal/domain"
 )
 
+// UserRepository guarda los usuarios en memoria, indexados por ID y por handle normalizado.
 type UserRepository struct {
-	mu    sync.RWMutex
-	users map[string]*domain.User
+	mu       sync.RWMutex
+	users    map[string]*domain.User
+	byHandle map[string]string // handle normalizado -> ID
 }
 
 // NewUserRepository crea una nueva instancia de UserRepository
 func NewUserRepository() *UserRepository {
 	return &UserRepository{
-		users: make(map[string]*domain.User),
+		users:    make(map[string]*domain.User),
+		byHandle: make(map[string]string),
 	}
 }
 
+// Create guarda un usuario nuevo si su handle está libre.
+func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	handle := domain.NormalizeHandle(user.Handle)
+	if _, taken := r.byHandle[handle]; taken {
+		return domain.ErrHandleTaken
+	}
+
+	stored := *user
+	r.users[user.ID] = &stored
+	r.byHandle[handle] = user.ID
+
+	return nil
+}
+
+// GetByID devuelve una copia del usuario.
 func (r *UserRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	// Como en el challenge nos dan como validos cualquier usuario, vamos a devolver siempre un usuario
-	// en caso de que no exista
-	_, ok := r.users[id]
+	user, ok := r.users[id]
+	if !ok {
+		return nil, domain.ErrUserNotFound
+	}
+
+	found := *user
+	return &found, nil
+}
+
+// GetByHandle devuelve una copia del usuario con ese handle.
+func (r *UserRepository) GetByHandle(ctx context.Context, handle string) (*domain.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	id, ok := r.byHandle[domain.NormalizeHandle(handle)]
 	if !ok {
-		r.users[id] = &domain.User{
-			ID: id,
-		}
+		return nil, domain.ErrUserNotFound
 	}
 
-	return r.users[id], nil
+	found := *r.users[id]
+	return &found, nil
 }
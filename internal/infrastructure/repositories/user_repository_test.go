@@ -2,8 +2,10 @@ package repositories
 
 import (
 	"context"
+	"sync"
 	"testing"
 
+	"ChallengeUALA/internal/domain"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -13,19 +15,59 @@ func TestNewUserRepository(t *testing.T) {
 	assert.NotNil(t, repo.users)
 }
 
-func TestUserRepository_GetByID(t *testing.T) {
+func TestUserRepository_CreateAndGet(t *testing.T) {
 	repo := NewUserRepository()
-	userID := "123"
+	ctx := context.Background()
 
-	user, err := repo.GetByID(context.Background(), userID)
+	user, err := domain.NewUser("Ana_Dev", "Ana", "Hola")
 	assert.NoError(t, err)
-	assert.NotNil(t, user)
-	assert.Equal(t, userID, user.ID)
-
-	// Verificar que el usuario se guarda en el repositorio
-	repo.mu.RLock()
-	defer repo.mu.RUnlock()
-	savedUser, exists := repo.users[userID]
-	assert.True(t, exists)
-	assert.Equal(t, user, savedUser)
+	assert.NoError(t, repo.Create(ctx, user))
+
+	found, err := repo.GetByID(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, user, found)
+
+	// El handle no distingue mayúsculas y se acepta con '@'
+	found, err = repo.GetByHandle(ctx, "@ana_dev")
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID, found.ID)
+
+	other, err := domain.NewUser("ANA_DEV", "", "")
+	assert.NoError(t, err)
+	assert.ErrorIs(t, repo.Create(ctx, other), domain.ErrHandleTaken)
+}
+
+func TestUserRepository_NotFound(t *testing.T) {
+	repo := NewUserRepository()
+
+	// Un ID desconocido ya no crea un usuario
+	_, err := repo.GetByID(context.Background(), "123")
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	assert.Empty(t, repo.users)
+
+	_, err = repo.GetByHandle(context.Background(), "ana")
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+}
+
+func TestUserRepository_ConcurrentAccess(t *testing.T) {
+	repo := NewUserRepository()
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			user, _ := domain.NewUser("ana", "", "")
+			_ = repo.Create(ctx, user)
+		}()
+		go func() {
+			defer wg.Done()
+			_, _ = repo.GetByHandle(ctx, "ana")
+		}()
+	}
+	wg.Wait()
+
+	// Solo una de las altas concurrentes se queda con el handle
+	assert.Len(t, repo.users, 1)
 }
@@ -0,0 +1,174 @@
+package search
+
+import (
+	"context"
+	"sync"
+
+	"ChallengeUALA/internal/domain"
+)
+
+// InvertedIndex es un índice invertido en memoria: por cada palabra guarda los tweets que la usan
+// y en qué posiciones, para poder resolver frases exactas sin volver a leer el contenido.
+// Vive en el proceso que lo alimenta, así que cada instancia de la API arma el suyo.
+type InvertedIndex struct {
+	mu       sync.RWMutex
+	tweets   map[string]*domain.Tweet
+	postings map[string]map[string][]int // palabra -> tweet -> posiciones
+	byAuthor map[string]map[string]bool
+}
+
+// NewInvertedIndex crea un índice vacío
+func NewInvertedIndex() *InvertedIndex {
+	return &InvertedIndex{
+		tweets:   make(map[string]*domain.Tweet),
+		postings: make(map[string]map[string][]int),
+		byAuthor: make(map[string]map[string]bool),
+	}
+}
+
+// Index agrega el tweet al índice. Si ya estaba indexado (por ejemplo, porque se editó o porque
+// el mensaje de Kafka se reprocesó) se reemplaza la versión anterior.
+func (i *InvertedIndex) Index(ctx context.Context, tweet *domain.Tweet) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.remove(tweet.ID)
+
+	stored := *tweet
+	i.tweets[tweet.ID] = &stored
+
+	if i.byAuthor[tweet.UserID] == nil {
+		i.byAuthor[tweet.UserID] = make(map[string]bool)
+	}
+	i.byAuthor[tweet.UserID][tweet.ID] = true
+
+	for position, word := range domain.Tokenize(tweet.Content) {
+		if i.postings[word] == nil {
+			i.postings[word] = make(map[string][]int)
+		}
+		i.postings[word][tweet.ID] = append(i.postings[word][tweet.ID], position)
+	}
+
+	return nil
+}
+
+// Remove saca un tweet del índice. Sacar un tweet que no está no es un error.
+func (i *InvertedIndex) Remove(ctx context.Context, tweetID string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.remove(tweetID)
+	return nil
+}
+
+// Search devuelve copias de los tweets que cumplen la búsqueda.
+func (i *InvertedIndex) Search(ctx context.Context, query *domain.SearchQuery) ([]*domain.Tweet, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	results := make([]*domain.Tweet, 0)
+	for tweetID := range i.candidates(query) {
+		tweet := i.tweets[tweetID]
+		if !query.MatchesFilters(tweet) || !i.containsPhrases(tweetID, query.Phrases) {
+			continue
+		}
+
+		found := *tweet
+		results = append(results, &found)
+	}
+
+	return results, nil
+}
+
+// candidates devuelve los tweets que tienen todas las palabras de la búsqueda, o todos los
+// tweets del autor si la búsqueda no tiene texto.
+func (i *InvertedIndex) candidates(query *domain.SearchQuery) map[string]bool {
+	words := append([]string(nil), query.Terms...)
+	for _, phrase := range query.Phrases {
+		words = append(words, phrase...)
+	}
+
+	if len(words) == 0 {
+		return i.byAuthor[query.AuthorID]
+	}
+
+	// Se arranca por la palabra menos usada para que la intersección sea lo más chica posible
+	smallest := words[0]
+	for _, word := range words[1:] {
+		if len(i.postings[word]) < len(i.postings[smallest]) {
+			smallest = word
+		}
+	}
+
+	candidates := make(map[string]bool, len(i.postings[smallest]))
+	for tweetID := range i.postings[smallest] {
+		candidates[tweetID] = true
+	}
+
+	for _, word := range words {
+		for tweetID := range candidates {
+			if _, ok := i.postings[word][tweetID]; !ok {
+				delete(candidates, tweetID)
+			}
+		}
+	}
+
+	return candidates
+}
+
+// containsPhrases indica si el tweet tiene cada frase con sus palabras seguidas y en orden
+func (i *InvertedIndex) containsPhrases(tweetID string, phrases [][]string) bool {
+	for _, phrase := range phrases {
+		if !i.containsPhrase(tweetID, phrase) {
+			return false
+		}
+	}
+	return true
+}
+
+func (i *InvertedIndex) containsPhrase(tweetID string, phrase []string) bool {
+	for _, start := range i.postings[phrase[0]][tweetID] {
+		matches := true
+		for offset, word := range phrase[1:] {
+			if !containsPosition(i.postings[word][tweetID], start+offset+1) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+// remove saca el tweet de todas las estructuras. Hay que llamarlo con el lock tomado.
+func (i *InvertedIndex) remove(tweetID string) {
+	tweet, ok := i.tweets[tweetID]
+	if !ok {
+		return
+	}
+
+	for _, word := range domain.Tokenize(tweet.Content) {
+		delete(i.postings[word], tweetID)
+		if len(i.postings[word]) == 0 {
+			delete(i.postings, word)
+		}
+	}
+
+	delete(i.byAuthor[tweet.UserID], tweetID)
+	if len(i.byAuthor[tweet.UserID]) == 0 {
+		delete(i.byAuthor, tweet.UserID)
+	}
+
+	delete(i.tweets, tweetID)
+}
+
+func containsPosition(positions []int, position int) bool {
+	for _, p := range positions {
+		if p == position {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package search
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ChallengeUALA/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func searchIDs(t *testing.T, index *InvertedIndex, rawQuery string) []string {
+	query, err := domain.ParseSearchQuery(rawQuery)
+	assert.NoError(t, err)
+	// En estos tests el handle de from: es también el ID del autor
+	query.AuthorID = query.AuthorHandle
+
+	tweets, err := index.Search(context.Background(), query)
+	assert.NoError(t, err)
+
+	ids := make([]string, 0, len(tweets))
+	for _, tweet := range tweets {
+		ids = append(ids, tweet.ID)
+	}
+	return ids
+}
+
+func TestInvertedIndex_Search(t *testing.T) {
+	index := NewInvertedIndex()
+	ctx := context.Background()
+
+	day := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	tweets := []*domain.Tweet{
+		{ID: "1", UserID: "ana", Content: "Aprendiendo Go con #golang", CreatedAt: day},
+		{ID: "2", UserID: "bob", Content: "Go es rápido, aprendiendo de a poco", CreatedAt: day.AddDate(0, 0, 1)},
+		{ID: "3", UserID: "ana", Content: "Hoy llueve", CreatedAt: day.AddDate(0, 0, 2)},
+	}
+	for _, tweet := range tweets {
+		assert.NoError(t, index.Index(ctx, tweet))
+	}
+
+	assert.ElementsMatch(t, []string{"1", "2"}, searchIDs(t, index, "aprendiendo GO"))
+	assert.ElementsMatch(t, []string{"1"}, searchIDs(t, index, "golang"))
+	assert.ElementsMatch(t, []string{"1"}, searchIDs(t, index, `"aprendiendo go"`))
+	assert.ElementsMatch(t, []string{"2"}, searchIDs(t, index, `"go es"`))
+	assert.Empty(t, searchIDs(t, index, `"go aprendiendo"`))
+	assert.ElementsMatch(t, []string{"1", "3"}, searchIDs(t, index, "from:@ana"))
+	assert.ElementsMatch(t, []string{"1"}, searchIDs(t, index, "go from:ana"))
+	assert.ElementsMatch(t, []string{"3"}, searchIDs(t, index, "from:ana since:2024-03-11"))
+	assert.ElementsMatch(t, []string{"2"}, searchIDs(t, index, "go since:2024-03-11"))
+	assert.ElementsMatch(t, []string{"1"}, searchIDs(t, index, "go until:2024-03-10"))
+	assert.Empty(t, searchIDs(t, index, "inexistente"))
+}
+
+func TestInvertedIndex_ReindexAndRemove(t *testing.T) {
+	index := NewInvertedIndex()
+	ctx := context.Background()
+
+	tweet := &domain.Tweet{ID: "1", UserID: "ana", Content: "Hola mundo", CreatedAt: time.Now()}
+	assert.NoError(t, index.Index(ctx, tweet))
+
+	edited := *tweet
+	edited.Content = "Chau mundo"
+	assert.NoError(t, index.Index(ctx, &edited))
+
+	assert.Empty(t, searchIDs(t, index, "hola"))
+	assert.Equal(t, []string{"1"}, searchIDs(t, index, "chau"))
+
+	assert.NoError(t, index.Remove(ctx, "1"))
+	assert.NoError(t, index.Remove(ctx, "1"))
+	assert.Empty(t, searchIDs(t, index, "mundo"))
+	assert.Empty(t, searchIDs(t, index, "from:ana"))
+	assert.Empty(t, index.postings)
+	assert.Empty(t, index.byAuthor)
+}
@@ -1,10 +1,14 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 
 	"ChallengeUALA/internal/application/services"
+	"ChallengeUALA/internal/domain"
+	"ChallengeUALA/internal/interfaces/http/middleware"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -21,7 +25,6 @@ func NewFollowHandler(followService *services.FollowService) *FollowHandler {
 
 func (h *FollowHandler) Follow(c *fiber.Ctx) error {
 	var request struct {
-		FollowerID string `json:"follower_id"`
 		FolloweeID string `json:"followee_id"`
 	}
 
@@ -31,9 +34,20 @@ func (h *FollowHandler) Follow(c *fiber.Ctx) error {
 		})
 	}
 
-	if err := h.followService.Follow(c.Context(), request.FollowerID, request.FolloweeID); err != nil {
+	err := h.followService.Follow(c.Context(), middleware.UserID(c), request.FolloweeID)
+	if errors.Is(err, domain.ErrInvalidUserID) {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{
+			"error": "user not found",
+		})
+	}
+	if err != nil {
 		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"error": fmt.Errorf("error following user: %w", err),
+			"error": fmt.Sprintf("error following user: %v", err),
 		})
 	}
 
@@ -41,3 +55,77 @@ func (h *FollowHandler) Follow(c *fiber.Ctx) error {
 		"message": "User followed successfully",
 	})
 }
+
+func (h *FollowHandler) Unfollow(c *fiber.Ctx) error {
+	var request struct {
+		FolloweeID string `json:"followee_id"`
+	}
+
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	err := h.followService.Unfollow(c.Context(), middleware.UserID(c), request.FolloweeID)
+	switch {
+	case errors.Is(err, domain.ErrInvalidUserID):
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, domain.ErrNotFollowing):
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{
+			"error": "not following user",
+		})
+	case err != nil:
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": fmt.Sprintf("error unfollowing user: %v", err),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "User unfollowed successfully",
+	})
+}
+
+func (h *FollowHandler) GetFollowers(c *fiber.Ctx) error {
+	return h.listFollows(c, h.followService.GetFollowers)
+}
+
+func (h *FollowHandler) GetFollowing(c *fiber.Ctx) error {
+	return h.listFollows(c, h.followService.GetFollowing)
+}
+
+func (h *FollowHandler) listFollows(
+	c *fiber.Ctx,
+	list func(ctx context.Context, userID, cursor string, limit int) (*domain.FollowList, error),
+) error {
+	limit := c.QueryInt("limit", 0)
+	if limit < 0 {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "limit must be positive",
+		})
+	}
+
+	follows, err := list(c.Context(), c.Params("id"), c.Query("cursor"), limit)
+	switch {
+	case errors.Is(err, domain.ErrInvalidCursor):
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid cursor",
+		})
+	case errors.Is(err, domain.ErrInvalidUserID):
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, domain.ErrUserNotFound):
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{
+			"error": "user not found",
+		})
+	case err != nil:
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": fmt.Sprintf("error listing follows: %v", err),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(follows)
+}
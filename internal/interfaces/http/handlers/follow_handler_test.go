@@ -0,0 +1,51 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"ChallengeUALA/internal/application/services"
+	"ChallengeUALA/internal/infrastructure/repositories"
+	"ChallengeUALA/internal/interfaces/http/handlers"
+	"ChallengeUALA/internal/interfaces/http/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// uuidAuthenticator acepta cualquier token como una sesión de userID, que tiene que ser un UUID
+// para llegar a los repositorios de follows.
+type uuidAuthenticator struct {
+	userID string
+}
+
+func (a uuidAuthenticator) Authenticate(ctx context.Context, token string) (string, string, error) {
+	return a.userID, "session1", nil
+}
+
+func newFollowApp(userID string) *fiber.App {
+	followService := services.NewFollowService(repositories.NewFollowRepository(), repositories.NewUserRepository(), nil, nil)
+	handler := handlers.NewFollowHandler(followService)
+
+	app := fiber.New()
+	app.Delete("/follow", middleware.RequireAuth(uuidAuthenticator{userID: userID}), handler.Unfollow)
+	return app
+}
+
+func TestUnfollow_InvalidUserID(t *testing.T) {
+	app := newFollowApp(uuid.NewString())
+
+	resp, err := app.Test(newAuthenticatedRequest(http.MethodDelete, "/follow", `{"followee_id":"not-a-uuid"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestUnfollow_NotFollowing(t *testing.T) {
+	app := newFollowApp(uuid.NewString())
+
+	resp, err := app.Test(newAuthenticatedRequest(http.MethodDelete, "/follow", `{"followee_id":"`+uuid.NewString()+`"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"ChallengeUALA/internal/application/services"
+	"ChallengeUALA/internal/domain"
+	"ChallengeUALA/internal/interfaces/http/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type LikeHandler struct {
+	likeService *services.LikeService
+}
+
+func NewLikeHandler(likeService *services.LikeService) *LikeHandler {
+	return &LikeHandler{
+		likeService: likeService,
+	}
+}
+
+func (h *LikeHandler) Like(c *fiber.Ctx) error {
+	return h.toggle(c, h.likeService.Like, "Tweet liked successfully")
+}
+
+func (h *LikeHandler) Unlike(c *fiber.Ctx) error {
+	return h.toggle(c, h.likeService.Unlike, "Tweet unliked successfully")
+}
+
+func (h *LikeHandler) toggle(
+	c *fiber.Ctx,
+	apply func(ctx context.Context, tweetID, userID string) error,
+	message string,
+) error {
+	err := apply(c.Context(), c.Params("id"), middleware.UserID(c))
+	if errors.Is(err, domain.ErrTweetNotFound) {
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{
+			"error": "tweet not found",
+		})
+	}
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": fmt.Sprintf("error updating like: %v", err),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": message,
+	})
+}
+
+func (h *LikeHandler) GetLikers(c *fiber.Ctx) error {
+	limit := c.QueryInt("limit", 0)
+	if limit < 0 {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "limit must be positive",
+		})
+	}
+
+	likes, err := h.likeService.GetLikers(c.Context(), c.Params("id"), c.Query("cursor"), limit)
+	switch {
+	case errors.Is(err, domain.ErrInvalidCursor):
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid cursor",
+		})
+	case errors.Is(err, domain.ErrTweetNotFound):
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{
+			"error": "tweet not found",
+		})
+	case err != nil:
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": fmt.Sprintf("error listing likes: %v", err),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(likes)
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"ChallengeUALA/internal/application/services"
+	"ChallengeUALA/internal/domain"
+	"ChallengeUALA/internal/interfaces/http/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type NotificationHandler struct {
+	notificationService *services.NotificationService
+}
+
+func NewNotificationHandler(notificationService *services.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+func (h *NotificationHandler) GetNotifications(c *fiber.Ctx) error {
+	limit := c.QueryInt("limit", 0)
+	if limit < 0 {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "limit must be positive",
+		})
+	}
+
+	notifications, err := h.notificationService.GetNotifications(c.Context(), middleware.UserID(c), c.Query("cursor"), limit)
+	if errors.Is(err, domain.ErrInvalidCursor) {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid cursor",
+		})
+	}
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": fmt.Sprintf("error getting notifications: %v", err),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(notifications)
+}
+
+// MarkRead marca como leídas las notificaciones de ids, o todas las del usuario autenticado si no se
+// manda ids
+func (h *NotificationHandler) MarkRead(c *fiber.Ctx) error {
+	var request struct {
+		IDs []string `json:"ids"`
+	}
+
+	// Sin body se marcan todas, así que puede llegar sin body ni Content-Type
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid request body",
+			})
+		}
+	}
+
+	if err := h.notificationService.MarkRead(c.Context(), middleware.UserID(c), request.IDs); err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": fmt.Sprintf("error marking notifications as read: %v", err),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "Notifications marked as read",
+	})
+}
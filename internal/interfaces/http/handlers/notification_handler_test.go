@@ -0,0 +1,80 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"ChallengeUALA/internal/application/services"
+	"ChallengeUALA/internal/domain"
+	"ChallengeUALA/internal/interfaces/http/handlers"
+	"ChallengeUALA/internal/interfaces/http/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeNotificationRepository guarda con qué argumentos se llamó a MarkRead.
+type fakeNotificationRepository struct {
+	markReadCalls int
+	markedUserID  string
+	markedIDs     []string
+}
+
+func (f *fakeNotificationRepository) Add(ctx context.Context, notification *domain.Notification) error {
+	return nil
+}
+
+func (f *fakeNotificationRepository) List(ctx context.Context, userID string, before time.Time, limit int) ([]*domain.Notification, error) {
+	return nil, nil
+}
+
+func (f *fakeNotificationRepository) CountUnread(ctx context.Context, userID string) (int, error) {
+	return 0, nil
+}
+
+func (f *fakeNotificationRepository) MarkRead(ctx context.Context, userID string, ids []string) error {
+	f.markReadCalls++
+	f.markedUserID = userID
+	f.markedIDs = ids
+	return nil
+}
+
+func newNotificationApp(repo *fakeNotificationRepository) *fiber.App {
+	handler := handlers.NewNotificationHandler(services.NewNotificationService(repo))
+
+	app := fiber.New()
+	app.Post("/notifications/read", middleware.RequireAuth(fakeAuthenticator{}), handler.MarkRead)
+	return app
+}
+
+func TestMarkRead_WithoutBody(t *testing.T) {
+	repo := &fakeNotificationRepository{}
+
+	// Sin body se marcan todas las notificaciones del usuario
+	resp, err := newNotificationApp(repo).Test(newAuthenticatedRequest(http.MethodPost, "/notifications/read", ""))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 1, repo.markReadCalls)
+	assert.Equal(t, "user1", repo.markedUserID)
+	assert.Empty(t, repo.markedIDs)
+}
+
+func TestMarkRead_WithIDs(t *testing.T) {
+	repo := &fakeNotificationRepository{}
+
+	resp, err := newNotificationApp(repo).Test(newAuthenticatedRequest(http.MethodPost, "/notifications/read", `{"ids":["n1","n2"]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []string{"n1", "n2"}, repo.markedIDs)
+}
+
+func TestMarkRead_InvalidBody(t *testing.T) {
+	repo := &fakeNotificationRepository{}
+
+	resp, err := newNotificationApp(repo).Test(newAuthenticatedRequest(http.MethodPost, "/notifications/read", "{"))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Zero(t, repo.markReadCalls)
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"ChallengeUALA/internal/application/services"
+	"ChallengeUALA/internal/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type SearchHandler struct {
+	searchService *services.SearchService
+}
+
+func NewSearchHandler(searchService *services.SearchService) *SearchHandler {
+	return &SearchHandler{
+		searchService: searchService,
+	}
+}
+
+func (h *SearchHandler) Search(c *fiber.Ctx) error {
+	limit := c.QueryInt("limit", 0)
+	if limit < 0 {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "limit must be positive",
+		})
+	}
+
+	page, err := h.searchService.Search(c.Context(), c.Query("q"), c.Query("cursor"), limit)
+	switch {
+	case errors.Is(err, domain.ErrInvalidSearchQuery):
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid search query",
+		})
+	case errors.Is(err, domain.ErrInvalidCursor):
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid cursor",
+		})
+	case err != nil:
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": fmt.Sprintf("error searching tweets: %v", err),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(page)
+}
@@ -2,6 +2,9 @@ package handlers
 
 import (
 	"ChallengeUALA/internal/application/services"
+	"ChallengeUALA/internal/domain"
+	"context"
+	"errors"
 	"fmt"
 	"github.com/gofiber/fiber/v2"
 	"net/http"
@@ -18,15 +21,38 @@ func NewTimelineHandler(timelineService *services.TimelineService) *TimelineHand
 }
 
 func (h *TimelineHandler) GetTimeline(c *fiber.Ctx) error {
-	userID := c.Params("userID")
+	return h.getPage(c, c.Params("userID"), h.timelineService.GetTimeline)
+}
+
+func (h *TimelineHandler) GetUserTweets(c *fiber.Ctx) error {
+	return h.getPage(c, c.Params("id"), h.timelineService.GetUserTweets)
+}
+
+func (h *TimelineHandler) getPage(
+	c *fiber.Ctx,
+	userID string,
+	get func(ctx context.Context, userID, cursor string, limit int) (*domain.TimelinePage, error),
+) error {
 	if userID == "" {
 		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
 			"error": "userID is required",
 		})
 	}
 
-	// Obtener el timeline del usuario usando el servicio
-	timeline, err := h.timelineService.GetTimeline(c.Context(), userID)
+	limit := c.QueryInt("limit", 0)
+	if limit < 0 {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "limit must be positive",
+		})
+	}
+
+	// Obtener la página usando el servicio
+	timeline, err := get(c.Context(), userID, c.Query("cursor"), limit)
+	if errors.Is(err, domain.ErrInvalidCursor) {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid cursor",
+		})
+	}
 	if err != nil {
 		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
 			"error": fmt.Sprintf("error getting timeline: %v", err),
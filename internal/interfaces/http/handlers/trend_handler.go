@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"ChallengeUALA/internal/application/services"
+	"ChallengeUALA/internal/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type TrendHandler struct {
+	trendingService *services.TrendingService
+}
+
+func NewTrendHandler(trendingService *services.TrendingService) *TrendHandler {
+	return &TrendHandler{
+		trendingService: trendingService,
+	}
+}
+
+func (h *TrendHandler) GetTrends(c *fiber.Ctx) error {
+	limit := c.QueryInt("limit", 0)
+	if limit < 0 {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "limit must be positive",
+		})
+	}
+
+	trends, err := h.trendingService.GetTrends(c.Context(), limit)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": fmt.Sprintf("error getting trends: %v", err),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"trends": trends,
+	})
+}
+
+func (h *TrendHandler) GetHashtagTweets(c *fiber.Ctx) error {
+	limit := c.QueryInt("limit", 0)
+	if limit < 0 {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "limit must be positive",
+		})
+	}
+
+	page, err := h.trendingService.GetHashtagTweets(c.Context(), c.Params("tag"), c.Query("cursor"), limit)
+	if errors.Is(err, domain.ErrInvalidCursor) {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid cursor",
+		})
+	}
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": fmt.Sprintf("error getting hashtag tweets: %v", err),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(page)
+}
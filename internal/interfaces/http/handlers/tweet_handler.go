@@ -1,10 +1,13 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
 	"ChallengeUALA/internal/application/services"
+	"ChallengeUALA/internal/domain"
+	"ChallengeUALA/internal/interfaces/http/middleware"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -21,8 +24,8 @@ func NewTweetHandler(tweetService *services.TweetService) *TweetHandler {
 
 func (h *TweetHandler) PostTweet(c *fiber.Ctx) error {
 	var request struct {
-		UserID  string `json:"user_id"`
-		Content string `json:"content"`
+		Content     string `json:"content"`
+		InReplyToID string `json:"in_reply_to_id"`
 	}
 
 	// Algunos controles
@@ -44,13 +47,176 @@ func (h *TweetHandler) PostTweet(c *fiber.Ctx) error {
 		})
 	}
 
-	if err := h.tweetService.PostTweet(c.Context(), request.UserID, request.Content); err != nil {
+	tweet, err := h.tweetService.PostTweet(c.Context(), middleware.UserID(c), request.Content, request.InReplyToID, c.Get("Idempotency-Key"))
+	switch {
+	case errors.Is(err, domain.ErrParentTweetNotFound):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Parent tweet not found",
+		})
+	case errors.Is(err, domain.ErrInvalidIdempotencyKey):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, domain.ErrIdempotencyKeyInUse):
+		return c.Status(http.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, domain.ErrIdempotencyKeyMismatch):
+		return c.Status(http.StatusUnprocessableEntity).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, domain.ErrTweetNotFound):
+		// Solo pasa al reintentar con la Idempotency-Key de un tweet que ya se borró
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{
+			"error": "tweet not found",
+		})
+	case err != nil:
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": fmt.Sprintf("error posting tweet: %v", err),
+		})
+	}
+
+	c.Location("/api/tweets/" + tweet.ID)
+	return c.Status(http.StatusCreated).JSON(tweet)
+}
+
+func (h *TweetHandler) Retweet(c *fiber.Ctx) error {
+	var request struct {
+		Content string `json:"content"`
+	}
+
+	// El body es opcional: un retweet sin comentario puede llegar sin body ni Content-Type
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid request body",
+			})
+		}
+	}
+
+	// El contenido es opcional: si viene, el retweet es una cita
+	if len(request.Content) > 140 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Content is too long",
+		})
+	}
+
+	err := h.tweetService.Retweet(c.Context(), middleware.UserID(c), c.Params("id"), request.Content)
+	if errors.Is(err, domain.ErrTweetNotFound) {
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{
+			"error": "tweet not found",
+		})
+	}
+	if err != nil {
 		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"error": fmt.Errorf("error posting tweet: %w", err),
+			"error": fmt.Sprintf("error retweeting tweet: %v", err),
 		})
 	}
 
 	return c.Status(http.StatusCreated).JSON(fiber.Map{
-		"message": "Tweet posted successfully",
+		"message": "Tweet retweeted successfully",
+	})
+}
+
+func (h *TweetHandler) GetTweet(c *fiber.Ctx) error {
+	tweet, err := h.tweetService.GetTweet(c.Context(), c.Params("id"))
+	if errors.Is(err, domain.ErrTweetNotFound) {
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{
+			"error": "tweet not found",
+		})
+	}
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": fmt.Sprintf("error getting tweet: %v", err),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(tweet)
+}
+
+func (h *TweetHandler) GetThread(c *fiber.Ctx) error {
+	thread, err := h.tweetService.GetThread(c.Context(), c.Params("id"))
+	if errors.Is(err, domain.ErrTweetNotFound) {
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{
+			"error": "tweet not found",
+		})
+	}
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": fmt.Sprintf("error getting thread: %v", err),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(thread)
+}
+
+func (h *TweetHandler) EditTweet(c *fiber.Ctx) error {
+	var request struct {
+		Content string `json:"content"`
+	}
+
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if len(request.Content) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Content is required",
+		})
+	}
+
+	if len(request.Content) > 140 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Content is too long",
+		})
+	}
+
+	tweet, err := h.tweetService.EditTweet(c.Context(), middleware.UserID(c), c.Params("id"), request.Content)
+	switch {
+	case errors.Is(err, domain.ErrTweetNotFound):
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{
+			"error": "tweet not found",
+		})
+	case errors.Is(err, domain.ErrNotTweetOwner):
+		return c.Status(http.StatusForbidden).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, domain.ErrEditWindowExpired),
+		errors.Is(err, domain.ErrEditLimitReached),
+		errors.Is(err, domain.ErrRetweetNotEditable),
+		errors.Is(err, domain.ErrTweetConflict):
+		return c.Status(http.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case err != nil:
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": fmt.Sprintf("error editing tweet: %v", err),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(tweet)
+}
+
+func (h *TweetHandler) DeleteTweet(c *fiber.Ctx) error {
+	err := h.tweetService.DeleteTweet(c.Context(), middleware.UserID(c), c.Params("id"))
+	switch {
+	case errors.Is(err, domain.ErrTweetNotFound):
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{
+			"error": "tweet not found",
+		})
+	case errors.Is(err, domain.ErrNotTweetOwner):
+		return c.Status(http.StatusForbidden).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case err != nil:
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": fmt.Sprintf("error deleting tweet: %v", err),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "Tweet deleted successfully",
 	})
 }
@@ -0,0 +1,60 @@
+package handlers_test
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"ChallengeUALA/internal/application/services"
+	"ChallengeUALA/internal/domain"
+	"ChallengeUALA/internal/infrastructure/repositories"
+	"ChallengeUALA/internal/interfaces/http/handlers"
+	"ChallengeUALA/internal/interfaces/http/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeAuthenticator acepta cualquier token como una sesión de user1.
+type fakeAuthenticator struct{}
+
+func (fakeAuthenticator) Authenticate(ctx context.Context, token string) (string, string, error) {
+	return "user1", "session1", nil
+}
+
+// newAuthenticatedRequest arma un pedido con token y, si body no está vacío, con body JSON.
+func newAuthenticatedRequest(method, target, body string) *http.Request {
+	req := httptest.NewRequest(method, target, nil)
+	if body != "" {
+		req = httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	}
+	req.Header.Set(fiber.HeaderAuthorization, "Bearer token")
+	return req
+}
+
+func TestRetweet_WithoutBody(t *testing.T) {
+	ctx := context.Background()
+	tweetRepo := repositories.NewTweetRepository()
+	original := domain.NewTweet("user2", "Hola")
+	assert.NoError(t, tweetRepo.Save(ctx, original))
+
+	tweetService := services.NewTweetService(tweetRepo, nil, nil, nil, nil, log.Default())
+	handler := handlers.NewTweetHandler(tweetService)
+
+	app := fiber.New()
+	app.Post("/tweets/:id/retweet", middleware.RequireAuth(fakeAuthenticator{}), handler.Retweet)
+
+	// Un retweet sin comentario no necesita body ni Content-Type
+	resp, err := app.Test(newAuthenticatedRequest(http.MethodPost, "/tweets/"+original.ID+"/retweet", ""))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	// Un body con JSON inválido sigue siendo un error
+	resp, err = app.Test(newAuthenticatedRequest(http.MethodPost, "/tweets/"+original.ID+"/retweet", "{"))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
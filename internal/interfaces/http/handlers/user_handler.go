@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"ChallengeUALA/internal/application/services"
+	"ChallengeUALA/internal/domain"
+	"ChallengeUALA/internal/interfaces/http/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type UserHandler struct {
+	userService *services.UserService
+	authService *services.AuthService
+}
+
+func NewUserHandler(userService *services.UserService, authService *services.AuthService) *UserHandler {
+	return &UserHandler{
+		userService: userService,
+		authService: authService,
+	}
+}
+
+// Register crea el usuario y devuelve, junto con él, el token de su primera sesión
+func (h *UserHandler) Register(c *fiber.Ctx) error {
+	var request struct {
+		Handle      string `json:"handle"`
+		DisplayName string `json:"display_name"`
+		Bio         string `json:"bio"`
+		Password    string `json:"password"`
+	}
+
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	user, err := h.userService.Register(c.Context(), request.Handle, request.DisplayName, request.Bio, request.Password)
+	switch {
+	case errors.Is(err, domain.ErrInvalidUser):
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, domain.ErrHandleTaken):
+		return c.Status(http.StatusConflict).JSON(fiber.Map{
+			"error": "handle already taken",
+		})
+	case err != nil:
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": fmt.Sprintf("error registering user: %v", err),
+		})
+	}
+
+	token, err := h.authService.OpenSession(c.Context(), user.ID)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": fmt.Sprintf("error opening session: %v", err),
+		})
+	}
+
+	return c.Status(http.StatusCreated).JSON(fiber.Map{
+		"user":  user,
+		"token": token,
+	})
+}
+
+// Login abre una sesión nueva con el handle y la contraseña y devuelve el usuario y su token
+func (h *UserHandler) Login(c *fiber.Ctx) error {
+	var request struct {
+		Handle   string `json:"handle"`
+		Password string `json:"password"`
+	}
+
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	user, token, err := h.authService.Login(c.Context(), request.Handle, request.Password)
+	if errors.Is(err, domain.ErrInvalidCredentials) {
+		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{
+			"error": "invalid handle or password",
+		})
+	}
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": fmt.Sprintf("error logging in: %v", err),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"user":  user,
+		"token": token,
+	})
+}
+
+// RefreshToken emite un token nuevo de la sesión del request, antes de que venza el actual
+func (h *UserHandler) RefreshToken(c *fiber.Ctx) error {
+	token, err := h.authService.Refresh(c.Context(), middleware.UserID(c), middleware.SessionID(c))
+	if errors.Is(err, domain.ErrInvalidSession) {
+		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{
+			"error": "invalid token",
+		})
+	}
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": fmt.Sprintf("error refreshing token: %v", err),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"token": token,
+	})
+}
+
+// Logout cierra la sesión del request: su token deja de servir aunque no haya vencido
+func (h *UserHandler) Logout(c *fiber.Ctx) error {
+	return h.respondWithLogout(c, func() error {
+		return h.authService.Logout(c.Context(), middleware.UserID(c), middleware.SessionID(c))
+	})
+}
+
+// LogoutAll cierra todas las sesiones del usuario autenticado, incluida la del request
+func (h *UserHandler) LogoutAll(c *fiber.Ctx) error {
+	return h.respondWithLogout(c, func() error {
+		return h.authService.LogoutAll(c.Context(), middleware.UserID(c))
+	})
+}
+
+func (h *UserHandler) respondWithLogout(c *fiber.Ctx, logout func() error) error {
+	if err := logout(); err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": fmt.Sprintf("error logging out: %v", err),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "Logged out successfully",
+	})
+}
+
+func (h *UserHandler) GetUser(c *fiber.Ctx) error {
+	return h.respondWithUser(c, func() (*domain.User, error) {
+		return h.userService.GetUser(c.Context(), c.Params("id"))
+	})
+}
+
+func (h *UserHandler) GetUserByHandle(c *fiber.Ctx) error {
+	return h.respondWithUser(c, func() (*domain.User, error) {
+		return h.userService.GetUserByHandle(c.Context(), c.Params("handle"))
+	})
+}
+
+func (h *UserHandler) respondWithUser(c *fiber.Ctx, get func() (*domain.User, error)) error {
+	user, err := get()
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{
+			"error": "user not found",
+		})
+	}
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+			"error": fmt.Sprintf("error getting user: %v", err),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(user)
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"ChallengeUALA/internal/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Keys de c.Locals donde RequireAuth deja el usuario autenticado y su sesión
+const (
+	userIDKey    = "auth_user_id"
+	sessionIDKey = "auth_session_id"
+)
+
+// Authenticator valida un token y devuelve el usuario y la sesión a los que pertenece, o
+// domain.ErrInvalidSession si no es válido o la sesión se cerró
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (string, string, error)
+}
+
+// RequireAuth exige el header "Authorization: Bearer <token>" y guarda en el contexto del request
+// el usuario y la sesión del token. Si falta o no es válido responde 401 sin llamar al handler.
+func RequireAuth(authenticator Authenticator) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+		if !ok || token == "" {
+			return unauthorized(c, "missing bearer token")
+		}
+
+		userID, sessionID, err := authenticator.Authenticate(c.Context(), token)
+		if errors.Is(err, domain.ErrInvalidSession) {
+			return unauthorized(c, "invalid token")
+		}
+		if err != nil {
+			return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+				"error": fmt.Sprintf("error authenticating: %v", err),
+			})
+		}
+
+		c.Locals(userIDKey, userID)
+		c.Locals(sessionIDKey, sessionID)
+		return c.Next()
+	}
+}
+
+// UserID devuelve el usuario autenticado del request, o vacío si la ruta no pasa por RequireAuth
+func UserID(c *fiber.Ctx) string {
+	userID, _ := c.Locals(userIDKey).(string)
+	return userID
+}
+
+// SessionID devuelve la sesión del token del request, o vacío si la ruta no pasa por RequireAuth
+func SessionID(c *fiber.Ctx) string {
+	sessionID, _ := c.Locals(sessionIDKey).(string)
+	return sessionID
+}
+
+func unauthorized(c *fiber.Ctx, message string) error {
+	c.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+	return c.Status(http.StatusUnauthorized).JSON(fiber.Map{
+		"error": message,
+	})
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"ChallengeUALA/internal/application/ports"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RateLimit acepta hasta limit pedidos del usuario autenticado por cada window en la ruta route,
+// así que tiene que ir después de RequireAuth. Informa el estado en los headers X-RateLimit-* y, al
+// pasarse, responde 429 con Retry-After. Si no se puede consultar el límite el pedido pasa: preferimos
+// no cortar la API porque Redis no responde. Un limit de 0 lo deshabilita.
+func RateLimit(limiter ports.RateLimiter, route string, limit int, window time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if limit <= 0 {
+			return c.Next()
+		}
+
+		decision, err := limiter.Allow(c.Context(), route+":"+UserID(c), limit, window)
+		if err != nil {
+			log.Printf("Error checking rate limit of %s for user %s: %v", route, UserID(c), err)
+			return c.Next()
+		}
+
+		reset := strconv.Itoa(ceilSeconds(decision.ResetAfter))
+		c.Set("X-RateLimit-Limit", strconv.Itoa(decision.Limit))
+		c.Set("X-RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+		c.Set("X-RateLimit-Reset", reset)
+
+		if !decision.Allowed {
+			c.Set(fiber.HeaderRetryAfter, reset)
+			return c.Status(http.StatusTooManyRequests).JSON(fiber.Map{
+				"error": "rate limit exceeded",
+			})
+		}
+
+		return c.Next()
+	}
+}
+
+// ceilSeconds redondea hacia arriba para que el cliente no reintente antes de que haya lugar
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,105 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"ChallengeUALA/internal/domain"
+	"ChallengeUALA/internal/interfaces/http/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeRateLimiter devuelve siempre la misma decisión y guarda las keys que se consultaron.
+type fakeRateLimiter struct {
+	decision domain.RateLimitDecision
+	err      error
+	keys     []string
+}
+
+func (f *fakeRateLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (domain.RateLimitDecision, error) {
+	f.keys = append(f.keys, key)
+	return f.decision, f.err
+}
+
+// fakeAuthenticator acepta cualquier token como una sesión de user1.
+type fakeAuthenticator struct{}
+
+func (fakeAuthenticator) Authenticate(ctx context.Context, token string) (string, string, error) {
+	return "user1", "session1", nil
+}
+
+// newRateLimitedApp arma una app con una ruta autenticada y limitada que responde 200.
+func newRateLimitedApp(limiter *fakeRateLimiter, limit int) *fiber.App {
+	app := fiber.New()
+	app.Post("/tweets",
+		middleware.RequireAuth(fakeAuthenticator{}),
+		middleware.RateLimit(limiter, "post_tweet", limit, time.Minute),
+		func(c *fiber.Ctx) error {
+			return c.SendStatus(http.StatusOK)
+		},
+	)
+	return app
+}
+
+func doRequest(t *testing.T, app *fiber.App) *http.Response {
+	req := httptest.NewRequest(http.MethodPost, "/tweets", nil)
+	req.Header.Set(fiber.HeaderAuthorization, "Bearer token")
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	return resp
+}
+
+func TestRateLimit_Allowed(t *testing.T) {
+	limiter := &fakeRateLimiter{decision: domain.RateLimitDecision{Allowed: true, Limit: 10, Remaining: 9, ResetAfter: 1500 * time.Millisecond}}
+
+	resp := doRequest(t, newRateLimitedApp(limiter, 10))
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "10", resp.Header.Get("X-RateLimit-Limit"))
+	assert.Equal(t, "9", resp.Header.Get("X-RateLimit-Remaining"))
+	// El reset se redondea hacia arriba
+	assert.Equal(t, "2", resp.Header.Get("X-RateLimit-Reset"))
+	assert.Empty(t, resp.Header.Get(fiber.HeaderRetryAfter))
+	// El límite es de cada usuario en cada ruta
+	assert.Equal(t, []string{"post_tweet:user1"}, limiter.keys)
+}
+
+func TestRateLimit_Exceeded(t *testing.T) {
+	limiter := &fakeRateLimiter{decision: domain.RateLimitDecision{Allowed: false, Limit: 10, Remaining: 0, ResetAfter: 30 * time.Second}}
+
+	resp := doRequest(t, newRateLimitedApp(limiter, 10))
+
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "30", resp.Header.Get(fiber.HeaderRetryAfter))
+	assert.Equal(t, "10", resp.Header.Get("X-RateLimit-Limit"))
+	assert.Equal(t, "0", resp.Header.Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "30", resp.Header.Get("X-RateLimit-Reset"))
+}
+
+func TestRateLimit_FailsOpen(t *testing.T) {
+	limiter := &fakeRateLimiter{err: errors.New("redis error")}
+
+	// Si no se puede consultar el límite el pedido pasa, sin headers
+	resp := doRequest(t, newRateLimitedApp(limiter, 10))
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get("X-RateLimit-Limit"))
+	assert.Len(t, limiter.keys, 1)
+}
+
+func TestRateLimit_Disabled(t *testing.T) {
+	limiter := &fakeRateLimiter{decision: domain.RateLimitDecision{Allowed: false}}
+
+	// Con limit 0 ni se consulta el limiter
+	resp := doRequest(t, newRateLimitedApp(limiter, 0))
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get("X-RateLimit-Limit"))
+	assert.Empty(t, limiter.keys)
+}
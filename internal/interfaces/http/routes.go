@@ -2,26 +2,76 @@ package http
 
 import (
 	"ChallengeUALA/internal/interfaces/http/handlers"
+	"ChallengeUALA/internal/interfaces/http/middleware"
+	"ChallengeUALA/internal/platform/config"
 
+	"ChallengeUALA/internal/application/ports"
 	"ChallengeUALA/internal/application/services"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-// SetupRoutes configura las rutas de nuestra app
+// SetupRoutes configura las rutas de nuestra app. Las que actúan en nombre de un usuario exigen
+// su token y toman el usuario de ahí, nunca del body. Publicar y seguir tienen además un límite de
+// pedidos por usuario y por ruta.
 func SetupRoutes(
 	app *fiber.App,
 	tweetService *services.TweetService,
 	followService *services.FollowService,
 	timelineService *services.TimelineService,
+	likeService *services.LikeService,
+	trendingService *services.TrendingService,
+	searchService *services.SearchService,
+	notificationService *services.NotificationService,
+	userService *services.UserService,
+	authService *services.AuthService,
+	rateLimiter ports.RateLimiter,
+	rateLimits config.RateLimitConfig,
 ) {
 
 	tweetHandler := handlers.NewTweetHandler(tweetService)
 	followHandler := handlers.NewFollowHandler(followService)
 	timelineHandler := handlers.NewTimelineHandler(timelineService)
+	likeHandler := handlers.NewLikeHandler(likeService)
+	trendHandler := handlers.NewTrendHandler(trendingService)
+	searchHandler := handlers.NewSearchHandler(searchService)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	userHandler := handlers.NewUserHandler(userService, authService)
+
+	requireAuth := middleware.RequireAuth(authService)
+	limitTweets := func(route string) fiber.Handler {
+		return middleware.RateLimit(rateLimiter, route, rateLimits.Tweets, rateLimits.Window)
+	}
+	limitFollows := func(route string) fiber.Handler {
+		return middleware.RateLimit(rateLimiter, route, rateLimits.Follows, rateLimits.Window)
+	}
 
 	router := app.Group("/api")
-	router.Post("/tweets", tweetHandler.PostTweet)
-	router.Post("/follow", followHandler.Follow)
+	router.Post("/tweets", requireAuth, limitTweets("post_tweet"), tweetHandler.PostTweet)
+	router.Post("/tweets/:id/retweet", requireAuth, limitTweets("retweet"), tweetHandler.Retweet)
+	router.Get("/tweets/:id", tweetHandler.GetTweet)
+	router.Get("/tweets/:id/thread", tweetHandler.GetThread)
+	router.Post("/tweets/:id/like", requireAuth, likeHandler.Like)
+	router.Delete("/tweets/:id/like", requireAuth, likeHandler.Unlike)
+	router.Get("/tweets/:id/likes", likeHandler.GetLikers)
+	router.Patch("/tweets/:id", requireAuth, tweetHandler.EditTweet)
+	router.Delete("/tweets/:id", requireAuth, tweetHandler.DeleteTweet)
+	router.Post("/follow", requireAuth, limitFollows("follow"), followHandler.Follow)
+	router.Delete("/follow", requireAuth, limitFollows("unfollow"), followHandler.Unfollow)
 	router.Get("/timeline/:userID", timelineHandler.GetTimeline)
+	router.Post("/users", userHandler.Register)
+	router.Post("/auth/login", userHandler.Login)
+	router.Post("/auth/token", requireAuth, userHandler.RefreshToken)
+	router.Post("/auth/logout", requireAuth, userHandler.Logout)
+	router.Post("/auth/logout/all", requireAuth, userHandler.LogoutAll)
+	router.Get("/users/:id", userHandler.GetUser)
+	router.Get("/handles/:handle", userHandler.GetUserByHandle)
+	router.Get("/users/:id/tweets", timelineHandler.GetUserTweets)
+	router.Get("/users/:id/followers", followHandler.GetFollowers)
+	router.Get("/users/:id/following", followHandler.GetFollowing)
+	router.Get("/trends", trendHandler.GetTrends)
+	router.Get("/hashtags/:tag/tweets", trendHandler.GetHashtagTweets)
+	router.Get("/search", searchHandler.Search)
+	router.Get("/notifications", requireAuth, notificationHandler.GetNotifications)
+	router.Post("/notifications/read", requireAuth, notificationHandler.MarkRead)
 }
@@ -0,0 +1,34 @@
+package auth
+
+import (
+	"fmt"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// PasswordHasher guarda las contraseñas con bcrypt, que agrega su propia sal y es lento a propósito
+// para que un hash filtrado no se pueda revertir por fuerza bruta.
+type PasswordHasher struct {
+	cost int
+}
+
+// NewPasswordHasher crea un PasswordHasher con el costo por defecto de bcrypt
+func NewPasswordHasher() *PasswordHasher {
+	return &PasswordHasher{
+		cost: bcrypt.DefaultCost,
+	}
+}
+
+// Hash devuelve el hash de la contraseña
+func (h *PasswordHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if err != nil {
+		return "", fmt.Errorf("error hashing password: %w", err)
+	}
+	return string(hash), nil
+}
+
+// Matches indica si password es la contraseña de hash. Un hash vacío no coincide con ninguna.
+func (h *PasswordHasher) Matches(hash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
@@ -0,0 +1,21 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestPasswordHasher(t *testing.T) {
+	hasher := &PasswordHasher{cost: bcrypt.MinCost}
+
+	hash, err := hasher.Hash("correct horse")
+	assert.NoError(t, err)
+	assert.NotEqual(t, "correct horse", hash)
+
+	assert.True(t, hasher.Matches(hash, "correct horse"))
+	assert.False(t, hasher.Matches(hash, "wrong horse"))
+	// Los usuarios sin contraseña no pueden iniciar sesión
+	assert.False(t, hasher.Matches("", ""))
+}
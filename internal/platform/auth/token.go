@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrInvalidToken se devuelve cuando el token está mal formado, la firma no coincide o ya venció
+var ErrInvalidToken = errors.New("invalid token")
+
+// tokenHeader es el header de todos los tokens: solo se firman y aceptan JWT HS256
+var tokenHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// claims son los datos firmados del token. Subject es el ID del usuario autenticado y SessionID la
+// sesión a la que pertenece el token.
+type claims struct {
+	Subject   string `json:"sub"`
+	SessionID string `json:"sid"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// TokenSigner firma y verifica JWT con HMAC-SHA256. Los tokens no se guardan: cualquier instancia
+// de la API con el mismo secreto los puede verificar. Para poder revocarlos, quien los usa tiene
+// que verificar además que su sesión siga abierta.
+type TokenSigner struct {
+	secret []byte
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+// NewTokenSigner crea un TokenSigner cuyos tokens vencen ttl después de emitidos
+func NewTokenSigner(secret string, ttl time.Duration) *TokenSigner {
+	return &TokenSigner{
+		secret: []byte(secret),
+		ttl:    ttl,
+		now:    time.Now,
+	}
+}
+
+// TTL devuelve cuánto duran los tokens que emite
+func (s *TokenSigner) TTL() time.Duration {
+	return s.ttl
+}
+
+// Sign emite un token para la sesión sessionID de userID.
+func (s *TokenSigner) Sign(userID, sessionID string) (string, error) {
+	now := s.now()
+	payload, err := json.Marshal(claims{
+		Subject:   userID,
+		SessionID: sessionID,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(s.ttl).Unix(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("error marshalling claims: %w", err)
+	}
+
+	unsigned := tokenHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return unsigned + "." + s.signature(unsigned), nil
+}
+
+// Verify valida la firma y el vencimiento del token y devuelve el ID del usuario y el de la sesión.
+// Los tokens emitidos antes de que existieran las sesiones no tienen sesión y no se aceptan.
+func (s *TokenSigner) Verify(token string) (string, string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != tokenHeader {
+		return "", "", ErrInvalidToken
+	}
+
+	unsigned := parts[0] + "." + parts[1]
+	if !hmac.Equal([]byte(parts[2]), []byte(s.signature(unsigned))) {
+		return "", "", ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", "", ErrInvalidToken
+	}
+
+	var c claims
+	if err := json.Unmarshal(payload, &c); err != nil || c.Subject == "" || c.SessionID == "" {
+		return "", "", ErrInvalidToken
+	}
+
+	if s.now().Unix() >= c.ExpiresAt {
+		return "", "", ErrInvalidToken
+	}
+
+	return c.Subject, c.SessionID, nil
+}
+
+func (s *TokenSigner) signature(unsigned string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(unsigned))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenSigner_SignAndVerify(t *testing.T) {
+	signer := NewTokenSigner("secret", time.Hour)
+
+	token, err := signer.Sign("user1", "session1")
+	assert.NoError(t, err)
+
+	userID, sessionID, err := signer.Verify(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "user1", userID)
+	assert.Equal(t, "session1", sessionID)
+}
+
+func TestTokenSigner_RejectsTamperedTokens(t *testing.T) {
+	signer := NewTokenSigner("secret", time.Hour)
+	token, err := signer.Sign("user1", "session1")
+	assert.NoError(t, err)
+
+	// Un token firmado con otro secreto
+	_, _, err = NewTokenSigner("other", time.Hour).Verify(token)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	// Cambiar el usuario invalida la firma
+	parts := strings.Split(token, ".")
+	forged, err := NewTokenSigner("other", time.Hour).Sign("user2", "session1")
+	assert.NoError(t, err)
+	_, _, err = signer.Verify(parts[0] + "." + strings.Split(forged, ".")[1] + "." + parts[2])
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	// Un token sin firma con "alg":"none"
+	_, _, err = signer.Verify("eyJhbGciOiJub25lIn0." + parts[1] + ".")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	// Un token sin sesión, como los que se emitían antes de que existieran
+	unsigned := parts[0] + "." + base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"user1","exp":9999999999}`))
+	_, _, err = signer.Verify(unsigned + "." + signer.signature(unsigned))
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	for _, malformed := range []string{"", "abc", "a.b", "a.b.c.d"} {
+		_, _, err = signer.Verify(malformed)
+		assert.ErrorIs(t, err, ErrInvalidToken, malformed)
+	}
+}
+
+func TestTokenSigner_Expiration(t *testing.T) {
+	signer := NewTokenSigner("secret", time.Hour)
+	issuedAt := time.Now()
+	signer.now = func() time.Time { return issuedAt }
+
+	token, err := signer.Sign("user1", "session1")
+	assert.NoError(t, err)
+
+	signer.now = func() time.Time { return issuedAt.Add(59 * time.Minute) }
+	_, _, err = signer.Verify(token)
+	assert.NoError(t, err)
+
+	signer.now = func() time.Time { return issuedAt.Add(time.Hour) }
+	_, _, err = signer.Verify(token)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
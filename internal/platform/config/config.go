@@ -1,28 +1,119 @@
 package config
 
 import (
+	"fmt"
 	"github.com/go-redis/redis/v8"
 	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	// StorageMemory guarda los datos en memoria, se pierden al reiniciar
+	StorageMemory = "memory"
+	// StoragePostgres guarda los datos en una base PostgreSQL
+	StoragePostgres = "postgres"
 )
 
 type Config struct {
-	Kafka KafkaConfig
-	Redis *redis.Options
+	Kafka     KafkaConfig
+	Redis     *redis.Options
+	Database  DatabaseConfig
+	Timeline  TimelineConfig
+	Auth      AuthConfig
+	RateLimit RateLimitConfig
 }
 
 type KafkaConfig struct {
 	Brokers []string
 	Topic   string
+	// GroupID es el consumer group del fan-out, las instancias con el mismo grupo se reparten las particiones
+	GroupID string
+	// TrendingGroupID es el consumer group que cuenta los hashtags. Es distinto al del fan-out
+	// para que los dos reciban todos los tweets.
+	TrendingGroupID string
+	// NotificationsTopic es el tópico donde los servicios publican las notificaciones y
+	// NotificationsGroupID el consumer group que las guarda en los inbox
+	NotificationsTopic   string
+	NotificationsGroupID string
+}
+
+// DatabaseConfig define qué almacenamiento usar para los repositorios persistentes
+type DatabaseConfig struct {
+	Storage string
+	DSN     string
+}
+
+// TimelineConfig define cómo se distribuyen los tweets en los timelines
+type TimelineConfig struct {
+	// CelebrityThreshold es la cantidad de seguidores a partir de la cual los tweets de un autor
+	// no se distribuyen por fan-out sino que se mezclan al leer el timeline. 0 lo deshabilita.
+	CelebrityThreshold int
+}
+
+// AuthConfig define cómo se firman los tokens de la API. Solo lo usa cmd/api, que exige el secreto.
+type AuthConfig struct {
+	Secret   string
+	TokenTTL time.Duration
+}
+
+// RateLimitConfig define cuántos pedidos por usuario acepta la API en cada ventana. Cada ruta lleva
+// su propia cuenta y 0 deshabilita el límite.
+type RateLimitConfig struct {
+	Window time.Duration
+	// Tweets es el límite para publicar y retwittear
+	Tweets int
+	// Follows es el límite para seguir y dejar de seguir
+	Follows int
 }
 
 func LoadAppConfig() (*Config, error) {
 	kafkaBrokers := os.Getenv("KAFKA_BROKERS")
 	kafkaTopic := os.Getenv("KAFKA_TOPIC")
+	kafkaGroupID := os.Getenv("KAFKA_GROUP_ID")
+	kafkaTrendingGroupID := os.Getenv("KAFKA_TRENDING_GROUP_ID")
+	kafkaNotificationsTopic := os.Getenv("KAFKA_NOTIFICATIONS_TOPIC")
+	kafkaNotificationsGroupID := os.Getenv("KAFKA_NOTIFICATIONS_GROUP_ID")
 	redisAddr := os.Getenv("REDIS_ADDR")
+	storage := os.Getenv("STORAGE")
+	databaseURL := os.Getenv("DATABASE_URL")
+	celebrityThreshold := os.Getenv("CELEBRITY_THRESHOLD")
+	authSecret := os.Getenv("AUTH_SECRET")
+	authTokenTTL := os.Getenv("AUTH_TOKEN_TTL")
+	rateLimitTweets := os.Getenv("RATE_LIMIT_TWEETS")
+	rateLimitFollows := os.Getenv("RATE_LIMIT_FOLLOWS")
+
+	if kafkaGroupID == "" {
+		kafkaGroupID = "timeline-fanout"
+	}
+
+	if kafkaTrendingGroupID == "" {
+		kafkaTrendingGroupID = "trending"
+	}
+
+	if kafkaTrendingGroupID == kafkaGroupID {
+		return nil, fmt.Errorf("KAFKA_TRENDING_GROUP_ID must be different from KAFKA_GROUP_ID")
+	}
+
+	if kafkaNotificationsTopic == "" {
+		kafkaNotificationsTopic = "notifications"
+	}
+
+	if kafkaNotificationsTopic == kafkaTopic {
+		return nil, fmt.Errorf("KAFKA_NOTIFICATIONS_TOPIC must be different from KAFKA_TOPIC")
+	}
+
+	if kafkaNotificationsGroupID == "" {
+		kafkaNotificationsGroupID = "notifications"
+	}
 
 	kafkaConfig := KafkaConfig{
-		Brokers: []string{kafkaBrokers},
-		Topic:   kafkaTopic,
+		Brokers:              []string{kafkaBrokers},
+		Topic:                kafkaTopic,
+		GroupID:              kafkaGroupID,
+		TrendingGroupID:      kafkaTrendingGroupID,
+		NotificationsTopic:   kafkaNotificationsTopic,
+		NotificationsGroupID: kafkaNotificationsGroupID,
 	}
 
 	redisConfig := redis.Options{
@@ -30,8 +121,75 @@ func LoadAppConfig() (*Config, error) {
 		DB:   0,
 	}
 
+	// Por defecto seguimos en memoria para no depender de una base de datos
+	if storage == "" {
+		storage = StorageMemory
+	}
+
+	if storage != StorageMemory && storage != StoragePostgres {
+		return nil, fmt.Errorf("invalid STORAGE %q", storage)
+	}
+
+	if storage == StoragePostgres && databaseURL == "" {
+		return nil, fmt.Errorf("DATABASE_URL is required when STORAGE is %s", StoragePostgres)
+	}
+
+	timelineConfig := TimelineConfig{
+		CelebrityThreshold: 10000,
+	}
+
+	if celebrityThreshold != "" {
+		threshold, err := strconv.Atoi(celebrityThreshold)
+		if err != nil || threshold < 0 {
+			return nil, fmt.Errorf("invalid CELEBRITY_THRESHOLD %q", celebrityThreshold)
+		}
+		timelineConfig.CelebrityThreshold = threshold
+	}
+
+	authConfig := AuthConfig{
+		Secret:   authSecret,
+		TokenTTL: 30 * 24 * time.Hour,
+	}
+
+	if authTokenTTL != "" {
+		ttl, err := time.ParseDuration(authTokenTTL)
+		if err != nil || ttl <= 0 {
+			return nil, fmt.Errorf("invalid AUTH_TOKEN_TTL %q", authTokenTTL)
+		}
+		authConfig.TokenTTL = ttl
+	}
+
+	rateLimitConfig := RateLimitConfig{
+		Window:  time.Minute,
+		Tweets:  30,
+		Follows: 60,
+	}
+
+	if rateLimitTweets != "" {
+		limit, err := strconv.Atoi(rateLimitTweets)
+		if err != nil || limit < 0 {
+			return nil, fmt.Errorf("invalid RATE_LIMIT_TWEETS %q", rateLimitTweets)
+		}
+		rateLimitConfig.Tweets = limit
+	}
+
+	if rateLimitFollows != "" {
+		limit, err := strconv.Atoi(rateLimitFollows)
+		if err != nil || limit < 0 {
+			return nil, fmt.Errorf("invalid RATE_LIMIT_FOLLOWS %q", rateLimitFollows)
+		}
+		rateLimitConfig.Follows = limit
+	}
+
 	return &Config{
 		Kafka: kafkaConfig,
 		Redis: &redisConfig,
+		Database: DatabaseConfig{
+			Storage: storage,
+			DSN:     databaseURL,
+		},
+		Timeline:  timelineConfig,
+		Auth:      authConfig,
+		RateLimit: rateLimitConfig,
 	}, nil
 }
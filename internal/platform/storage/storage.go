@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"ChallengeUALA/internal/application/ports"
+	"ChallengeUALA/internal/infrastructure/database"
+	"ChallengeUALA/internal/infrastructure/repositories"
+	"ChallengeUALA/internal/platform/config"
+)
+
+// TweetStore agrupa el repositorio de tweets con su outbox, que siempre viven en el mismo almacenamiento
+type TweetStore interface {
+	ports.TweetRepository
+	ports.OutboxRepository
+}
+
+// Storage agrupa los repositorios según el almacenamiento configurado.
+// Lo comparten los binarios api, consumer y worker.
+type Storage struct {
+	Tweets  TweetStore
+	Follows ports.FollowRepository
+	Users   ports.UserRepository
+
+	db *sql.DB
+}
+
+// Open crea los repositorios para el almacenamiento configurado. Con PostgreSQL además
+// aplica las migraciones pendientes.
+func Open(ctx context.Context, cfg config.DatabaseConfig) (*Storage, error) {
+	if cfg.Storage != config.StoragePostgres {
+		return &Storage{
+			Tweets:  repositories.NewTweetRepository(),
+			Follows: repositories.NewFollowRepository(),
+			Users:   repositories.NewUserRepository(),
+		}, nil
+	}
+
+	db, err := database.Open(ctx, cfg.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := database.Migrate(ctx, db); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("error running migrations: %w", err)
+	}
+
+	return &Storage{
+		Tweets:  repositories.NewPostgresTweetRepository(db),
+		Follows: repositories.NewPostgresFollowRepository(db),
+		Users:   repositories.NewPostgresUserRepository(db),
+		db:      db,
+	}, nil
+}
+
+// Shared indica si el almacenamiento puede compartirse entre procesos.
+// En memoria cada proceso tiene sus propios datos.
+func (s *Storage) Shared() bool {
+	return s.db != nil
+}
+
+// Close libera la conexión a la base de datos, si la hay.
+func (s *Storage) Close() error {
+	if s.db == nil {
+		return nil
+	}
+	return s.db.Close()
+}
@@ -2,7 +2,6 @@ package worker
 
 import (
 	"context"
-	"encoding/json"
 	"log"
 	"time"
 
@@ -52,15 +51,18 @@ func (w *DLQWorker) processDLQ(ctx context.Context) {
 	}
 
 	for _, msg := range messages {
-		var tweet domain.Tweet
-		if err := json.Unmarshal([]byte(msg.Body), &tweet); err != nil {
+		event, err := domain.DecodeTweetEvent([]byte(msg.Body))
+		if err != nil {
 			w.logger.Printf("Error unmarshalling tweet from DLQ: %v", err)
 			continue
 		}
 
-		// Intentar publicar el evento nuevamente
-		if err := w.eventProducer.PublishEvent(ctx, tweet.UserID, []byte(msg.Body)); err != nil {
+		// Intentar publicar el evento nuevamente. Si falla vuelve a la DLQ para el próximo intento
+		if err := w.eventProducer.PublishEvent(ctx, event.Tweet.UserID, []byte(msg.Body)); err != nil {
 			w.logger.Printf("Error reprocessing event from DLQ: %v", err)
+			if err := w.deadLetterQueue.StoreEvent(ctx, msg.ID, msg.Body); err != nil {
+				w.logger.Printf("Error storing event back in DLQ: %v", err)
+			}
 			continue
 		}
 
@@ -0,0 +1,276 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"ChallengeUALA/internal/application/ports"
+	"ChallengeUALA/internal/domain"
+	"github.com/segmentio/kafka-go"
+)
+
+// Un mensaje se intenta procesar hasta maxAttempts veces, esperando entre intentos un backoff que se
+// duplica hasta maxRetryBackoff
+const (
+	maxAttempts     = 5
+	maxRetryBackoff = 30 * time.Second
+)
+
+// deadLetterEventType es el tipo con el que se guardan en la DLQ los eventos que agotaron los intentos
+const deadLetterEventType = "timeline_events"
+
+// errMalformedMessage indica que el mensaje nunca se va a poder procesar, así que se commitea igual
+var errMalformedMessage = errors.New("malformed message")
+
+// MessageReader define lo que FanoutConsumer necesita de un kafka.Reader
+type MessageReader interface {
+	FetchMessage(ctx context.Context) (kafka.Message, error)
+	CommitMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// TimelineUpdater es el handler que recibe cada tweet consumido
+type TimelineUpdater interface {
+	UpdateTimeline(ctx context.Context, tweet *domain.Tweet) error
+	ReplaceTweet(ctx context.Context, tweet *domain.Tweet) error
+	RemoveTweet(ctx context.Context, tweet *domain.Tweet) error
+}
+
+// TweetIndexer recibe los tweets consumidos para mantener un índice derivado de ellos,
+// como las tendencias o la búsqueda
+type TweetIndexer interface {
+	IndexTweet(ctx context.Context, tweet *domain.Tweet) error
+	ReindexTweet(ctx context.Context, tweet *domain.Tweet) error
+	UnindexTweet(ctx context.Context, tweet *domain.Tweet) error
+}
+
+// NotificationDeliverer guarda las notificaciones consumidas en el inbox de su destinatario
+type NotificationDeliverer interface {
+	Deliver(ctx context.Context, notification *domain.Notification) error
+}
+
+// FanoutConsumer lee los tweets publicados en Kafka y los distribuye en los timelines de los seguidores.
+type FanoutConsumer struct {
+	reader   MessageReader
+	timeline TimelineUpdater
+	// deadLetters recibe los mensajes que agotaron los intentos; si es nil se descartan
+	deadLetters ports.DeadLetterQueue
+	// handle procesa cada mensaje; por defecto es handleMessage
+	handle       func(ctx context.Context, msg kafka.Message) error
+	retryBackoff time.Duration
+	logger       *log.Logger
+}
+
+// NewFanoutConsumer crea una nueva instancia de FanoutConsumer. Los mensajes que fallan maxAttempts
+// veces se guardan en deadLetters para que el DLQWorker los vuelva a publicar.
+func NewFanoutConsumer(reader MessageReader, timeline TimelineUpdater, deadLetters ports.DeadLetterQueue, logger *log.Logger) *FanoutConsumer {
+	c := &FanoutConsumer{
+		reader:       reader,
+		timeline:     timeline,
+		deadLetters:  deadLetters,
+		retryBackoff: time.Second,
+		logger:       logger,
+	}
+	c.handle = c.handleMessage
+	return c
+}
+
+// NewTrendingConsumer crea un FanoutConsumer que, en vez de actualizar timelines, le pasa cada
+// evento a indexer. Tiene que leer con su propio consumer group para recibir todos los tweets.
+// No tiene DLQ: el DLQWorker republica en el tópico de tweets y duplicaría el fan-out, así que los
+// mensajes que agotan los intentos se descartan.
+func NewTrendingConsumer(reader MessageReader, indexer TweetIndexer, logger *log.Logger) *FanoutConsumer {
+	return NewFanoutConsumer(reader, IndexerUpdater(indexer), nil, logger)
+}
+
+// NewNotificationConsumer crea un FanoutConsumer que lee el tópico de notificaciones y se las pasa a
+// deliverer, con los mismos reintentos y commits que el fan-out. Como las notificaciones son
+// best-effort, las que agotan los intentos se descartan.
+func NewNotificationConsumer(reader MessageReader, deliverer NotificationDeliverer, logger *log.Logger) *FanoutConsumer {
+	c := NewFanoutConsumer(reader, nil, nil, logger)
+	c.handle = func(ctx context.Context, msg kafka.Message) error {
+		notification, err := domain.DecodeNotification(msg.Value)
+		if err != nil {
+			return fmt.Errorf("%w: %v", errMalformedMessage, err)
+		}
+
+		if err := deliverer.Deliver(ctx, notification); err != nil {
+			return fmt.Errorf("error delivering notification: %w", err)
+		}
+
+		logger.Printf("Delivered %s notification %s to user %s", notification.Type, notification.ID, notification.UserID)
+		return nil
+	}
+	return c
+}
+
+// IndexerUpdater adapta un TweetIndexer a TimelineUpdater
+func IndexerUpdater(indexer TweetIndexer) TimelineUpdater {
+	return indexerUpdater{indexer: indexer}
+}
+
+type indexerUpdater struct {
+	indexer TweetIndexer
+}
+
+func (u indexerUpdater) UpdateTimeline(ctx context.Context, tweet *domain.Tweet) error {
+	return u.indexer.IndexTweet(ctx, tweet)
+}
+
+func (u indexerUpdater) ReplaceTweet(ctx context.Context, tweet *domain.Tweet) error {
+	return u.indexer.ReindexTweet(ctx, tweet)
+}
+
+func (u indexerUpdater) RemoveTweet(ctx context.Context, tweet *domain.Tweet) error {
+	return u.indexer.UnindexTweet(ctx, tweet)
+}
+
+// MultiUpdater pasa cada evento a todos los updaters en orden. Si uno falla el mensaje se
+// reintenta entero, así que los updaters tienen que tolerar recibir el mismo evento dos veces.
+func MultiUpdater(updaters ...TimelineUpdater) TimelineUpdater {
+	return multiUpdater(updaters)
+}
+
+type multiUpdater []TimelineUpdater
+
+func (m multiUpdater) UpdateTimeline(ctx context.Context, tweet *domain.Tweet) error {
+	for _, updater := range m {
+		if err := updater.UpdateTimeline(ctx, tweet); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m multiUpdater) ReplaceTweet(ctx context.Context, tweet *domain.Tweet) error {
+	for _, updater := range m {
+		if err := updater.ReplaceTweet(ctx, tweet); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m multiUpdater) RemoveTweet(ctx context.Context, tweet *domain.Tweet) error {
+	for _, updater := range m {
+		if err := updater.RemoveTweet(ctx, tweet); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Run consume mensajes hasta que se cancela ctx. El offset de un mensaje solo se commitea
+// cuando el timeline se actualizó bien o cuando, después de maxAttempts intentos con backoff, se
+// pasó a la DLQ: así un mensaje que no se puede procesar no frena la partición para siempre.
+// El mensaje que se está procesando al momento de cancelar se termina de procesar antes de salir,
+// y si no se pudo procesar queda sin commitear para que lo tome otra instancia del grupo.
+func (c *FanoutConsumer) Run(ctx context.Context) error {
+	c.logger.Println("Kafka consumer started")
+
+	for {
+		msg, err := c.reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, context.Canceled) {
+				c.logger.Println("Kafka consumer stopped")
+				return nil
+			}
+			c.logger.Printf("Error fetching message: %v", err)
+			continue
+		}
+
+		if !c.processWithRetry(ctx, msg) {
+			c.logger.Println("Kafka consumer stopped")
+			return nil
+		}
+
+		if err := c.reader.CommitMessages(context.WithoutCancel(ctx), msg); err != nil {
+			c.logger.Printf("Error committing message at offset %d: %v", msg.Offset, err)
+		}
+	}
+}
+
+// processWithRetry procesa el mensaje hasta que sale bien o se agotan los intentos, y en ese caso
+// lo pasa a la DLQ. Devuelve false si se canceló ctx antes de poder procesarlo.
+func (c *FanoutConsumer) processWithRetry(ctx context.Context, msg kafka.Message) bool {
+	backoff := c.retryBackoff
+	for attempt := 1; ; attempt++ {
+		err := c.handle(context.WithoutCancel(ctx), msg)
+		if err == nil {
+			return true
+		}
+
+		if errors.Is(err, errMalformedMessage) {
+			c.logger.Printf("Skipping message at offset %d: %v", msg.Offset, err)
+			return true
+		}
+
+		if attempt == maxAttempts {
+			c.deadLetter(ctx, msg, err)
+			return true
+		}
+
+		c.logger.Printf("Error handling message at offset %d, retrying in %s: %v", msg.Offset, backoff, err)
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > maxRetryBackoff {
+			backoff = maxRetryBackoff
+		}
+	}
+}
+
+// deadLetter guarda en la DLQ un mensaje que agotó los intentos, o lo descarta si no hay DLQ
+func (c *FanoutConsumer) deadLetter(ctx context.Context, msg kafka.Message, err error) {
+	if c.deadLetters == nil {
+		c.logger.Printf("Dropping message at offset %d after %d attempts: %v", msg.Offset, maxAttempts, err)
+		return
+	}
+
+	c.logger.Printf("Moving message at offset %d to the DLQ after %d attempts: %v", msg.Offset, maxAttempts, err)
+	if err := c.deadLetters.StoreEvent(context.WithoutCancel(ctx), deadLetterEventType, msg.Value); err != nil {
+		c.logger.Printf("Error storing message at offset %d in the DLQ: %v", msg.Offset, err)
+	}
+}
+
+// handleMessage decodifica el evento y actualiza los timelines de los seguidores del autor.
+func (c *FanoutConsumer) handleMessage(ctx context.Context, msg kafka.Message) error {
+	event, err := domain.DecodeTweetEvent(msg.Value)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errMalformedMessage, err)
+	}
+	tweet := event.Tweet
+
+	switch event.Type {
+	case domain.EventTweetCreated:
+		// Actualizar el timeline para los seguidores del usuario que publicó el tweet
+		if err := c.timeline.UpdateTimeline(ctx, tweet); err != nil {
+			return fmt.Errorf("error updating timeline: %w", err)
+		}
+	case domain.EventTweetEdited:
+		if err := c.timeline.ReplaceTweet(ctx, tweet); err != nil {
+			return fmt.Errorf("error replacing tweet in timelines: %w", err)
+		}
+	case domain.EventTweetDeleted:
+		if err := c.timeline.RemoveTweet(ctx, tweet); err != nil {
+			return fmt.Errorf("error removing tweet from timelines: %w", err)
+		}
+	default:
+		return fmt.Errorf("%w: unknown event type %q", errMalformedMessage, event.Type)
+	}
+
+	c.logger.Printf("Processed %s for tweet %s of user %s", event.Type, tweet.ID, tweet.UserID)
+	return nil
+}
+
+// Close cierra el reader de Kafka.
+func (c *FanoutConsumer) Close() error {
+	return c.reader.Close()
+}
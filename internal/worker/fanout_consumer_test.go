@@ -0,0 +1,270 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"log"
+	"testing"
+	"time"
+
+	"ChallengeUALA/internal/domain"
+	"ChallengeUALA/internal/infrastructure/dlq"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// fakeReader devuelve los mensajes en orden y después bloquea hasta que se cancela el contexto
+type fakeReader struct {
+	messages  []kafka.Message
+	committed []int64
+	cancel    context.CancelFunc
+	closed    bool
+}
+
+func (r *fakeReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	if len(r.messages) == 0 {
+		r.cancel()
+		<-ctx.Done()
+		return kafka.Message{}, ctx.Err()
+	}
+	msg := r.messages[0]
+	r.messages = r.messages[1:]
+	return msg, nil
+}
+
+func (r *fakeReader) CommitMessages(ctx context.Context, msgs ...kafka.Message) error {
+	for _, msg := range msgs {
+		r.committed = append(r.committed, msg.Offset)
+	}
+	return nil
+}
+
+func (r *fakeReader) Close() error {
+	r.closed = true
+	return nil
+}
+
+type MockTimelineUpdater struct {
+	mock.Mock
+}
+
+func (m *MockTimelineUpdater) UpdateTimeline(ctx context.Context, tweet *domain.Tweet) error {
+	args := m.Called(ctx, tweet)
+	return args.Error(0)
+}
+
+func (m *MockTimelineUpdater) ReplaceTweet(ctx context.Context, tweet *domain.Tweet) error {
+	args := m.Called(ctx, tweet)
+	return args.Error(0)
+}
+
+func (m *MockTimelineUpdater) RemoveTweet(ctx context.Context, tweet *domain.Tweet) error {
+	args := m.Called(ctx, tweet)
+	return args.Error(0)
+}
+
+func tweetWithID(id string) interface{} {
+	return mock.MatchedBy(func(tweet *domain.Tweet) bool {
+		return tweet.ID == id
+	})
+}
+
+func TestFanoutConsumer_Run(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	reader := &fakeReader{
+		messages: []kafka.Message{
+			{Offset: 1, Value: []byte(`{"ID":"1","UserID":"user1","Content":"Tweet 1"}`)},
+			{Offset: 2, Value: []byte(`not json`)},
+			{Offset: 3, Value: []byte(`{"type":"tweet_created","tweet":{"ID":"2","UserID":"user2","Content":"Tweet 2"}}`)},
+			{Offset: 4, Value: []byte(`{"type":"tweet_edited","tweet":{"ID":"2","UserID":"user2","Content":"Tweet 2 (edited)"}}`)},
+			{Offset: 5, Value: []byte(`{"type":"tweet_deleted","tweet":{"ID":"1","UserID":"user1","Content":"Tweet 1"}}`)},
+			{Offset: 6, Value: []byte(`{"type":"tweet_liked","tweet":{"ID":"1","UserID":"user1"}}`)},
+		},
+		cancel: cancel,
+	}
+	mockTimeline := new(MockTimelineUpdater)
+
+	// El primer tweet falla una vez y se reintenta antes de commitear
+	mockTimeline.On("UpdateTimeline", mock.Anything, tweetWithID("1")).Return(errors.New("redis error")).Once()
+	mockTimeline.On("UpdateTimeline", mock.Anything, tweetWithID("1")).Return(nil).Once()
+	mockTimeline.On("UpdateTimeline", mock.Anything, tweetWithID("2")).Return(nil).Once()
+	mockTimeline.On("ReplaceTweet", mock.Anything, tweetWithID("2")).Return(nil).Once()
+	mockTimeline.On("RemoveTweet", mock.Anything, tweetWithID("1")).Return(nil).Once()
+
+	consumer := NewFanoutConsumer(reader, mockTimeline, nil, log.Default())
+	consumer.retryBackoff = time.Millisecond
+
+	err := consumer.Run(ctx)
+	assert.NoError(t, err)
+	mockTimeline.AssertExpectations(t)
+
+	// Los mensajes mal formados o de tipos desconocidos se commitean porque nunca se van a poder procesar
+	assert.Equal(t, []int64{1, 2, 3, 4, 5, 6}, reader.committed)
+
+	assert.NoError(t, consumer.Close())
+	assert.True(t, reader.closed)
+}
+
+func TestFanoutConsumer_Run_DoesNotCommitFailedMessage(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	reader := &fakeReader{
+		messages: []kafka.Message{
+			{Offset: 1, Value: []byte(`{"ID":"1","UserID":"user1","Content":"Tweet 1"}`)},
+		},
+		cancel: cancel,
+	}
+	mockTimeline := new(MockTimelineUpdater)
+
+	// Se cancela mientras el mensaje sigue fallando
+	mockTimeline.On("UpdateTimeline", mock.Anything, tweetWithID("1")).
+		Run(func(args mock.Arguments) { cancel() }).
+		Return(errors.New("redis error"))
+
+	consumer := NewFanoutConsumer(reader, mockTimeline, nil, log.Default())
+	consumer.retryBackoff = time.Hour
+
+	err := consumer.Run(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, reader.committed)
+}
+
+// fakeDLQ guarda los eventos que recibe
+type fakeDLQ struct {
+	stored [][]byte
+}
+
+func (d *fakeDLQ) StoreEvent(ctx context.Context, eventType string, payload []byte) error {
+	d.stored = append(d.stored, payload)
+	return nil
+}
+
+func (d *fakeDLQ) RetrieveEvents(ctx context.Context) ([]dlq.Message, error) {
+	return nil, nil
+}
+
+func TestFanoutConsumer_Run_MovesToDLQAfterMaxAttempts(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	poison := []byte(`{"type":"tweet_created","tweet":{"ID":"1","UserID":"user1","Content":"Tweet 1"}}`)
+	reader := &fakeReader{
+		messages: []kafka.Message{
+			{Offset: 1, Value: poison},
+			{Offset: 2, Value: []byte(`{"type":"tweet_created","tweet":{"ID":"2","UserID":"user2","Content":"Tweet 2"}}`)},
+		},
+		cancel: cancel,
+	}
+	mockTimeline := new(MockTimelineUpdater)
+	mockTimeline.On("UpdateTimeline", mock.Anything, tweetWithID("1")).Return(errors.New("redis error"))
+	mockTimeline.On("UpdateTimeline", mock.Anything, tweetWithID("2")).Return(nil).Once()
+
+	deadLetters := &fakeDLQ{}
+	consumer := NewFanoutConsumer(reader, mockTimeline, deadLetters, log.Default())
+	consumer.retryBackoff = time.Millisecond
+
+	err := consumer.Run(ctx)
+	assert.NoError(t, err)
+
+	// El mensaje que sigue fallando se intenta maxAttempts veces, pasa a la DLQ una sola vez y se
+	// commitea para que la partición siga avanzando
+	mockTimeline.AssertNumberOfCalls(t, "UpdateTimeline", maxAttempts+1)
+	assert.Equal(t, [][]byte{poison}, deadLetters.stored)
+	assert.Equal(t, []int64{1, 2}, reader.committed)
+}
+
+type MockTweetIndexer struct {
+	mock.Mock
+}
+
+func (m *MockTweetIndexer) IndexTweet(ctx context.Context, tweet *domain.Tweet) error {
+	args := m.Called(ctx, tweet)
+	return args.Error(0)
+}
+
+func (m *MockTweetIndexer) ReindexTweet(ctx context.Context, tweet *domain.Tweet) error {
+	args := m.Called(ctx, tweet)
+	return args.Error(0)
+}
+
+func (m *MockTweetIndexer) UnindexTweet(ctx context.Context, tweet *domain.Tweet) error {
+	args := m.Called(ctx, tweet)
+	return args.Error(0)
+}
+
+func TestTrendingConsumer_Run(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	reader := &fakeReader{
+		messages: []kafka.Message{
+			{Offset: 1, Value: []byte(`{"type":"tweet_created","tweet":{"ID":"1","UserID":"user1","Content":"#go"}}`)},
+			{Offset: 2, Value: []byte(`{"type":"tweet_edited","tweet":{"ID":"1","UserID":"user1","Content":"#rust"}}`)},
+			{Offset: 3, Value: []byte(`{"type":"tweet_deleted","tweet":{"ID":"1","UserID":"user1","Content":"#rust"}}`)},
+		},
+		cancel: cancel,
+	}
+	mockIndexer := new(MockTweetIndexer)
+	mockIndexer.On("IndexTweet", mock.Anything, tweetWithID("1")).Return(nil).Once()
+	mockIndexer.On("ReindexTweet", mock.Anything, tweetWithID("1")).Return(nil).Once()
+	mockIndexer.On("UnindexTweet", mock.Anything, tweetWithID("1")).Return(nil).Once()
+
+	consumer := NewTrendingConsumer(reader, mockIndexer, log.Default())
+
+	err := consumer.Run(ctx)
+	assert.NoError(t, err)
+	mockIndexer.AssertExpectations(t)
+	assert.Equal(t, []int64{1, 2, 3}, reader.committed)
+}
+
+func TestMultiUpdater(t *testing.T) {
+	ctx := context.Background()
+	tweet := &domain.Tweet{ID: "1"}
+	first := new(MockTimelineUpdater)
+	second := new(MockTimelineUpdater)
+	updater := MultiUpdater(first, second)
+
+	first.On("UpdateTimeline", ctx, tweet).Return(nil)
+	second.On("UpdateTimeline", ctx, tweet).Return(nil)
+	assert.NoError(t, updater.UpdateTimeline(ctx, tweet))
+
+	// Si uno falla no se llama a los siguientes y el error vuelve para reintentar el mensaje
+	first.On("RemoveTweet", ctx, tweet).Return(errors.New("redis error"))
+	assert.Error(t, updater.RemoveTweet(ctx, tweet))
+	second.AssertNotCalled(t, "RemoveTweet", ctx, tweet)
+
+	first.On("ReplaceTweet", ctx, tweet).Return(nil)
+	second.On("ReplaceTweet", ctx, tweet).Return(nil)
+	assert.NoError(t, updater.ReplaceTweet(ctx, tweet))
+
+	first.AssertExpectations(t)
+	second.AssertExpectations(t)
+}
+
+type MockNotificationDeliverer struct {
+	mock.Mock
+}
+
+func (m *MockNotificationDeliverer) Deliver(ctx context.Context, notification *domain.Notification) error {
+	args := m.Called(ctx, notification)
+	return args.Error(0)
+}
+
+func TestNotificationConsumer_Run(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	reader := &fakeReader{
+		messages: []kafka.Message{
+			{Offset: 1, Value: []byte(`{"id":"n1","type":"follow","user_id":"user1","actor_id":"user2"}`)},
+			{Offset: 2, Value: []byte(`{"type":"tweet_created"}`)},
+		},
+		cancel: cancel,
+	}
+	mockDeliverer := new(MockNotificationDeliverer)
+	mockDeliverer.On("Deliver", mock.Anything, mock.MatchedBy(func(notification *domain.Notification) bool {
+		return notification.ID == "n1" && notification.UserID == "user1" && notification.Type == domain.NotificationFollow
+	})).Return(nil).Once()
+
+	consumer := NewNotificationConsumer(reader, mockDeliverer, log.Default())
+
+	// El mensaje que no es una notificación se saltea y se commitea igual
+	err := consumer.Run(ctx)
+	assert.NoError(t, err)
+	mockDeliverer.AssertExpectations(t)
+	assert.Equal(t, []int64{1, 2}, reader.committed)
+}
@@ -0,0 +1,77 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"ChallengeUALA/internal/application/ports"
+)
+
+// outboxBatchSize es la cantidad máxima de eventos que se publican por iteración
+const outboxBatchSize = 100
+
+// OutboxRelay es un worker que publica en Kafka los eventos pendientes del outbox.
+// Un evento solo se marca como publicado después de que el producer lo confirma, por lo que
+// si el proceso se cae en el medio el evento se vuelve a publicar (at-least-once).
+type OutboxRelay struct {
+	outbox        ports.OutboxRepository
+	eventProducer ports.EventProducer
+	interval      time.Duration
+	logger        *log.Logger
+}
+
+// NewOutboxRelay crea una nueva instancia de OutboxRelay.
+func NewOutboxRelay(outbox ports.OutboxRepository, ep ports.EventProducer, interval time.Duration, logger *log.Logger) *OutboxRelay {
+	return &OutboxRelay{
+		outbox:        outbox,
+		eventProducer: ep,
+		interval:      interval,
+		logger:        logger,
+	}
+}
+
+// Start inicia el worker para drenar el outbox cada interval.
+func (w *OutboxRelay) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Println("Outbox relay stopped")
+			return
+		case <-ticker.C:
+			w.processOutbox(ctx)
+		}
+	}
+}
+
+// processOutbox publica los eventos pendientes en orden. Si uno falla corta la iteración
+// para no desordenar los eventos, y se reintenta en el próximo tick.
+func (w *OutboxRelay) processOutbox(ctx context.Context) {
+	for {
+		events, err := w.outbox.FetchPending(ctx, outboxBatchSize)
+		if err != nil {
+			w.logger.Printf("Error fetching events from outbox: %v", err)
+			return
+		}
+
+		for _, event := range events {
+			if err := w.eventProducer.PublishEvent(ctx, event.Key, event.Payload); err != nil {
+				w.logger.Printf("Error publishing outbox event %s: %v", event.ID, err)
+				return
+			}
+
+			if err := w.outbox.MarkPublished(ctx, event.ID); err != nil {
+				w.logger.Printf("Error marking outbox event %s as published: %v", event.ID, err)
+				return
+			}
+		}
+
+		// Si el batch vino incompleto no quedan más eventos pendientes
+		if len(events) < outboxBatchSize {
+			return
+		}
+	}
+}
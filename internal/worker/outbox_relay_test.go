@@ -0,0 +1,78 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"log"
+	"testing"
+
+	"ChallengeUALA/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockOutboxRepository struct {
+	mock.Mock
+}
+
+func (m *MockOutboxRepository) FetchPending(ctx context.Context, limit int) ([]*domain.OutboxEvent, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]*domain.OutboxEvent), args.Error(1)
+}
+
+func (m *MockOutboxRepository) MarkPublished(ctx context.Context, eventID string) error {
+	args := m.Called(ctx, eventID)
+	return args.Error(0)
+}
+
+type MockEventProducer struct {
+	mock.Mock
+}
+
+func (m *MockEventProducer) PublishEvent(ctx context.Context, key string, value []byte) error {
+	args := m.Called(ctx, key, value)
+	return args.Error(0)
+}
+
+func TestOutboxRelay_ProcessOutbox(t *testing.T) {
+	ctx := context.Background()
+	mockOutbox := new(MockOutboxRepository)
+	mockProducer := new(MockEventProducer)
+
+	events := []*domain.OutboxEvent{
+		{ID: "1", Key: "user1", Payload: []byte("tweet1")},
+		{ID: "2", Key: "user2", Payload: []byte("tweet2")},
+	}
+
+	mockOutbox.On("FetchPending", ctx, outboxBatchSize).Return(events, nil)
+	mockProducer.On("PublishEvent", ctx, "user1", []byte("tweet1")).Return(nil)
+	mockProducer.On("PublishEvent", ctx, "user2", []byte("tweet2")).Return(nil)
+	mockOutbox.On("MarkPublished", ctx, "1").Return(nil)
+	mockOutbox.On("MarkPublished", ctx, "2").Return(nil)
+
+	relay := NewOutboxRelay(mockOutbox, mockProducer, 0, log.Default())
+	relay.processOutbox(ctx)
+
+	mockOutbox.AssertExpectations(t)
+	mockProducer.AssertExpectations(t)
+}
+
+func TestOutboxRelay_ProcessOutbox_PublishFails(t *testing.T) {
+	ctx := context.Background()
+	mockOutbox := new(MockOutboxRepository)
+	mockProducer := new(MockEventProducer)
+
+	events := []*domain.OutboxEvent{
+		{ID: "1", Key: "user1", Payload: []byte("tweet1")},
+		{ID: "2", Key: "user2", Payload: []byte("tweet2")},
+	}
+
+	mockOutbox.On("FetchPending", ctx, outboxBatchSize).Return(events, nil)
+	mockProducer.On("PublishEvent", ctx, "user1", []byte("tweet1")).Return(errors.New("kafka down"))
+
+	relay := NewOutboxRelay(mockOutbox, mockProducer, 0, log.Default())
+	relay.processOutbox(ctx)
+
+	// Si falla la publicación el evento queda pendiente y no se sigue con los siguientes
+	mockOutbox.AssertNotCalled(t, "MarkPublished", ctx, "1")
+	mockProducer.AssertNotCalled(t, "PublishEvent", ctx, "user2", []byte("tweet2"))
+}